@@ -35,16 +35,23 @@ func NewConfigCommand(cfgFactory config.Factory) *cobra.Command {
 
 	configRootCmd.AddCommand(NewGetContextCommand(cfgFactory))
 	configRootCmd.AddCommand(NewSetContextCommand(cfgFactory))
+	configRootCmd.AddCommand(NewDeleteContextCommand(cfgFactory))
+	configRootCmd.AddCommand(NewRenameContextCommand(cfgFactory))
 
 	configRootCmd.AddCommand(NewGetManagementConfigCommand(cfgFactory))
 	configRootCmd.AddCommand(NewSetManagementConfigCommand(cfgFactory))
+	configRootCmd.AddCommand(NewDeleteManagementConfigCommand(cfgFactory))
 
 	configRootCmd.AddCommand(NewUseContextCommand(cfgFactory))
 
 	configRootCmd.AddCommand(NewGetManifestCommand(cfgFactory))
 	configRootCmd.AddCommand(NewSetManifestCommand(cfgFactory))
+	configRootCmd.AddCommand(NewDeleteManifestCommand(cfgFactory))
+
+	configRootCmd.AddCommand(NewViewCommand(cfgFactory))
 
 	// Init will have different factory
 	configRootCmd.AddCommand(NewInitCommand())
+	configRootCmd.AddCommand(NewMigrateCommand())
 	return configRootCmd
 }
@@ -0,0 +1,61 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package config
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+)
+
+const (
+	deleteContextLong = `
+Deletes the context from the airshipctl config file. The current context is deleted only if
+the --switch-to flag names another context, which becomes the current one. Contexts loaded
+from the system or per-project config can't be deleted.
+`
+
+	deleteContextExample = `
+Delete a context named "exampleContext"
+# airshipctl config delete-context exampleContext
+
+Delete the current context named "exampleContext" and make "otherContext" current
+# airshipctl config delete-context exampleContext --switch-to otherContext
+`
+
+	flagForce    = "force"
+	flagSwitchTo = "switch-to"
+)
+
+// NewDeleteContextCommand creates a command for deleting a context from the airshipctl config file.
+func NewDeleteContextCommand(cfgFactory config.Factory) *cobra.Command {
+	o := &config.RunDeleteOptions{CfgFactory: cfgFactory}
+	cmd := &cobra.Command{
+		Use:     "delete-context CONTEXT_NAME",
+		Short:   "Airshipctl command to delete context from the airshipctl config file",
+		Long:    deleteContextLong[1:],
+		Example: deleteContextExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.Name = args[0]
+			o.Writer = cmd.OutOrStdout()
+			return o.RunDeleteContext()
+		},
+	}
+
+	cmd.Flags().StringVar(&o.SwitchTo, flagSwitchTo, "",
+		"the context to make current if the deleted context is the current one")
+	return cmd
+}
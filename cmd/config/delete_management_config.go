@@ -0,0 +1,58 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package config
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+)
+
+const (
+	deleteManagementConfigLong = `
+Deletes the management configuration from the airshipctl config file. The management configuration
+used by contexts is deleted only if the --force flag is set, the references to it are removed in this case.
+Management configurations loaded from the system or per-project config can't be deleted.
+`
+
+	deleteManagementConfigExample = `
+Delete a management configuration named "default"
+# airshipctl config delete-management-config default
+
+Delete a management configuration named "default" along with references to it from contexts
+# airshipctl config delete-management-config default --force
+`
+)
+
+// NewDeleteManagementConfigCommand creates a command for deleting a management configuration
+// from the airshipctl config file.
+func NewDeleteManagementConfigCommand(cfgFactory config.Factory) *cobra.Command {
+	o := &config.RunDeleteOptions{CfgFactory: cfgFactory}
+	cmd := &cobra.Command{
+		Use:     "delete-management-config MGMT_CONFIG_NAME",
+		Short:   "Airshipctl command to delete management configuration from the airshipctl config file",
+		Long:    deleteManagementConfigLong[1:],
+		Example: deleteManagementConfigExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.Name = args[0]
+			o.Writer = cmd.OutOrStdout()
+			return o.RunDeleteManagementConfig()
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.Force, flagForce, false, "delete the management configuration even if it is in use")
+	return cmd
+}
@@ -0,0 +1,54 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config_test
+
+import (
+	"errors"
+	"testing"
+
+	cmd "opendev.org/airship/airshipctl/cmd/config"
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+func TestConfigDeleteManagementConfig(t *testing.T) {
+	settings := func() (*config.Config, error) {
+		return testutil.DummyConfig(), nil
+	}
+	cmdTests := []*testutil.CmdTest{
+		{
+			Name:    "config-delete-management-config-with-help",
+			CmdLine: "--help",
+			Cmd:     cmd.NewDeleteManagementConfigCommand(nil),
+		},
+		{
+			Name:    "config-delete-management-config-in-use",
+			CmdLine: "dummy_management_config",
+			Cmd:     cmd.NewDeleteManagementConfigCommand(settings),
+			Error: errors.New("management configuration with name 'dummy_management_config' is used by " +
+				"context 'dummy_context', use --force flag to delete it anyway"),
+		},
+		{
+			Name:    "config-delete-management-config-does-not-exist",
+			CmdLine: "foo",
+			Cmd:     cmd.NewDeleteManagementConfigCommand(settings),
+			Error:   errors.New("Unknown management configuration 'foo'."),
+		},
+	}
+
+	for _, tt := range cmdTests {
+		testutil.RunTest(t, tt)
+	}
+}
@@ -0,0 +1,66 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package config
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+)
+
+const (
+	deleteManifestLong = `
+Deletes the manifest or, if the --repo flag is set, the repository of the manifest from the airshipctl
+config file. The manifest used by contexts or the phase and inventory repository of the manifest are
+deleted only if the --force flag is set, the references to them are removed in this case. The manifest
+of the current context, its phase and inventory repositories and manifests loaded from the system or
+per-project config can't be deleted.
+`
+
+	deleteManifestExample = `
+Delete a manifest named "exampleManifest"
+# airshipctl config delete-manifest exampleManifest
+
+Delete a manifest named "exampleManifest" along with references to it from contexts
+# airshipctl config delete-manifest exampleManifest --force
+
+Delete a repository named "exampleRepo" of the manifest named "exampleManifest"
+# airshipctl config delete-manifest exampleManifest --repo exampleRepo
+`
+)
+
+// NewDeleteManifestCommand creates a command for deleting a manifest or its repository
+// from the airshipctl config file.
+func NewDeleteManifestCommand(cfgFactory config.Factory) *cobra.Command {
+	o := &config.RunDeleteOptions{CfgFactory: cfgFactory}
+	var repoName string
+	cmd := &cobra.Command{
+		Use:     "delete-manifest MANIFEST_NAME",
+		Short:   "Airshipctl command to delete manifest or its repository from the airshipctl config file",
+		Long:    deleteManifestLong[1:],
+		Example: deleteManifestExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.Name = args[0]
+			o.Writer = cmd.OutOrStdout()
+			return o.RunDeleteManifest(repoName)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&repoName, "repo", "", "the name of the repository to be deleted from this manifest")
+	flags.BoolVar(&o.Force, flagForce, false, "delete the manifest or repository even if it is in use")
+	return cmd
+}
@@ -0,0 +1,54 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config_test
+
+import (
+	"errors"
+	"testing"
+
+	cmd "opendev.org/airship/airshipctl/cmd/config"
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+func TestConfigDeleteManifest(t *testing.T) {
+	settings := func() (*config.Config, error) {
+		return testutil.DummyConfig(), nil
+	}
+	cmdTests := []*testutil.CmdTest{
+		{
+			Name:    "config-delete-manifest-with-help",
+			CmdLine: "--help",
+			Cmd:     cmd.NewDeleteManifestCommand(nil),
+		},
+		{
+			Name:    "config-delete-manifest-current",
+			CmdLine: "dummy_manifest --force",
+			Cmd:     cmd.NewDeleteManifestCommand(settings),
+			Error: errors.New("manifest with name 'dummy_manifest' is used by the current context 'dummy_context', " +
+				"make another context current to delete it"),
+		},
+		{
+			Name:    "config-delete-manifest-repo-does-not-exist",
+			CmdLine: "dummy_manifest --repo foo",
+			Cmd:     cmd.NewDeleteManifestCommand(settings),
+			Error:   errors.New(`Repository "foo" not found.`),
+		},
+	}
+
+	for _, tt := range cmdTests {
+		testutil.RunTest(t, tt)
+	}
+}
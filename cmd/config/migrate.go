@@ -0,0 +1,64 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package config
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+)
+
+const (
+	migrateLong = `
+Upgrades airshipctl config file written by previous airshipctl releases to the current format.
+The file is modified in place and its original content is saved next to it with the .bak suffix
+beforehand, the command fails if the .bak file already exists.
+The config format has never been versioned, all releases use the same apiVersion, so the outdated
+fields are detected and converted regardless of apiVersion.
+The config file is taken from the --airshipconf flag or the positional argument, $HOME/.airship/config
+is used by default.
+`
+	migrateExample = `
+Migrate airshipctl config file at the default location
+# airshipctl config migrate
+
+Migrate airshipctl config file at the custom location
+# airshipctl config migrate path/to/config
+`
+)
+
+// NewMigrateCommand creates a command for upgrading airshipctl config file to the current format.
+func NewMigrateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "migrate [PATH]",
+		Short:   "Airshipctl command to upgrade config file to the current format",
+		Long:    migrateLong[1:],
+		Example: migrateExample,
+		Args:    cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			airshipConfigPath, err := cmd.Flags().GetString("airshipconf")
+			if err != nil {
+				airshipConfigPath = ""
+			}
+			if len(args) == 1 {
+				airshipConfigPath = args[0]
+			}
+
+			return config.RunMigrate(airshipConfigPath, cmd.OutOrStdout())
+		},
+	}
+
+	return cmd
+}
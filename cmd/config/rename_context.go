@@ -0,0 +1,50 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package config
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+)
+
+const (
+	renameContextLong = `
+Renames the context in the airshipctl config file. The current context is updated
+if the renamed context is the current one. Contexts loaded from the system or per-project
+config can't be renamed.
+`
+
+	renameContextExample = `
+Rename a context named "exampleContext" to "newContext"
+# airshipctl config rename-context exampleContext newContext
+`
+)
+
+// NewRenameContextCommand creates a command for renaming a context in the airshipctl config file.
+func NewRenameContextCommand(cfgFactory config.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "rename-context CONTEXT_NAME NEW_NAME",
+		Short:   "Airshipctl command to rename context in the airshipctl config file",
+		Long:    renameContextLong[1:],
+		Example: renameContextExample,
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return config.RunRenameContext(cfgFactory, args[0], args[1], cmd.OutOrStdout())
+		},
+	}
+
+	return cmd
+}
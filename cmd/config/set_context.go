@@ -25,6 +25,8 @@ const (
 	setContextLong = `
 Creates or modifies context in the airshipctl config file based on the CONTEXT_NAME passed or for the current context
 if --current flag is specified. It accepts optional flags which include manifest name and management-config name.
+The kubeconfig override of the cluster named by --kubeconfig-override-cluster flag is modified by the other
+kubeconfig override flags, the override is removed once all its fields are set to empty values.
 `
 
 	setContextExample = `
@@ -33,11 +35,24 @@ To create a new context named "exampleContext"
 
 To update the manifest of the current-context
 # airshipctl config set-context --current --manifest=exampleManifest
+
+To use local kubeconfig file and proxy for the cluster "target-cluster" in the current-context
+# airshipctl config set-context --current --kubeconfig-override-cluster=target-cluster \
+  --kubeconfig-override-path=/home/user/.kube/target --kubeconfig-override-proxy-url=socks5://bastion:1080
+
+To remove kubeconfig override for the cluster "target-cluster" in the current-context
+# airshipctl config set-context --current --kubeconfig-override-cluster=target-cluster \
+  --kubeconfig-override-path= --kubeconfig-override-context= --kubeconfig-override-proxy-url=
 `
 
 	setContextManifestFlag         = "manifest"
 	setContextManagementConfigFlag = "management-config"
 	setContextCurrentFlag          = "current"
+
+	setContextKubeconfigOverrideClusterFlag  = "kubeconfig-override-cluster"
+	setContextKubeconfigOverridePathFlag     = "kubeconfig-override-path"
+	setContextKubeconfigOverrideContextFlag  = "kubeconfig-override-context"
+	setContextKubeconfigOverrideProxyURLFlag = "kubeconfig-override-proxy-url"
 )
 
 // NewSetContextCommand creates a command for creating and modifying contexts
@@ -66,6 +81,14 @@ func addSetContextFlags(cmd *cobra.Command, o *config.ContextOptions) {
 		"set the management config for the specified context")
 	flags.BoolVar(&o.Current, setContextCurrentFlag, false,
 		"update the current context")
+	flags.StringVar(&o.KubeconfigOverrideCluster, setContextKubeconfigOverrideClusterFlag, "",
+		"the cluster of the cluster map to modify kubeconfig override for")
+	flags.StringVar(&o.KubeconfigOverride.Path, setContextKubeconfigOverridePathFlag, "",
+		"set the kubeconfig file used for the cluster instead of the cluster map sources")
+	flags.StringVar(&o.KubeconfigOverride.ContextName, setContextKubeconfigOverrideContextFlag, "",
+		"set the context of the cluster in the kubeconfig override file")
+	flags.StringVar(&o.KubeconfigOverride.ProxyURL, setContextKubeconfigOverrideProxyURLFlag, "",
+		"set the proxy URL used to access the cluster")
 }
 
 func setContextRunE(cfgFactory config.Factory, o *config.ContextOptions) func(cmd *cobra.Command, args []string) error {
@@ -77,16 +100,31 @@ func setContextRunE(cfgFactory config.Factory, o *config.ContextOptions) func(cm
 
 		// Go through all the flags that have been set
 		var opts []config.ContextOption
+		var overrideOpts []config.KubeconfigOverrideOption
 		fn := func(flag *pflag.Flag) {
 			switch flag.Name {
 			case setContextManifestFlag:
 				opts = append(opts, config.SetContextManifest(o.Manifest))
 			case setContextManagementConfigFlag:
 				opts = append(opts, config.SetContextManagementConfig(o.ManagementConfiguration))
+			case setContextKubeconfigOverridePathFlag:
+				overrideOpts = append(overrideOpts, config.SetKubeconfigOverridePath(o.KubeconfigOverride.Path))
+			case setContextKubeconfigOverrideContextFlag:
+				overrideOpts = append(overrideOpts,
+					config.SetKubeconfigOverrideContextName(o.KubeconfigOverride.ContextName))
+			case setContextKubeconfigOverrideProxyURLFlag:
+				overrideOpts = append(overrideOpts, config.SetKubeconfigOverrideProxyURL(o.KubeconfigOverride.ProxyURL))
 			}
 		}
 		cmd.Flags().Visit(fn)
 
+		if len(overrideOpts) > 0 {
+			if o.KubeconfigOverrideCluster == "" {
+				return config.ErrMissingKubeconfigOverrideCluster{}
+			}
+			opts = append(opts, config.SetContextKubeconfigOverride(o.KubeconfigOverrideCluster, overrideOpts...))
+		}
+
 		options := &config.RunSetContextOptions{
 			CfgFactory: cfgFactory,
 			CtxName:    ctxName,
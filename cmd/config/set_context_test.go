@@ -15,6 +15,7 @@ limitations under the License.
 package config_test
 
 import (
+	"errors"
 	"testing"
 
 	"opendev.org/airship/airshipctl/cmd/config"
@@ -28,6 +29,12 @@ func TestConfigSetContext(t *testing.T) {
 			CmdLine: "--help",
 			Cmd:     config.NewSetContextCommand(nil),
 		},
+		{
+			Name:    "config-cmd-set-context-override-without-cluster",
+			CmdLine: "--current --kubeconfig-override-path=/tmp/kubeconfig",
+			Cmd:     config.NewSetContextCommand(nil),
+			Error:   errors.New("--kubeconfig-override-cluster flag must be set to modify kubeconfig override"),
+		},
 	}
 	for _, tt := range cmdTests {
 		testutil.RunTest(t, tt)
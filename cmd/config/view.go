@@ -0,0 +1,64 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package config
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+)
+
+const (
+	viewLong = `
+Displays the airshipctl config merged from the system wide config, the user config and
+the per-project .airship/config found by walking up from the current directory, with
+AIRSHIP_CONTEXT, AIRSHIP_MANIFEST, AIRSHIP_TARGET_PATH, AIRSHIP_METADATA_PATH,
+AIRSHIP_PHASE_REPOSITORY and AIRSHIP_INVENTORY_REPOSITORY environment variables applied.
+
+The per-project config is used only if the project directory holding .airship is listed
+in AIRSHIPTRUSTEDPROJECTS environment variable. Repository credential sources, i.e. sshKey,
+keyPassFrom, httpPassFrom and sshPassFrom, and kubeconfigOverrides of the contexts are never
+taken from the per-project config.
+`
+
+	viewExample = `
+Display the config
+# airshipctl config view
+
+Display the config along with the file or environment variable each value came from
+# airshipctl config view --show-origin
+`
+
+	showOriginFlag = "show-origin"
+)
+
+// NewViewCommand creates a command for viewing the airshipctl config
+func NewViewCommand(cfgFactory config.Factory) *cobra.Command {
+	var showOrigin bool
+	cmd := &cobra.Command{
+		Use:     "view",
+		Short:   "Airshipctl command to display merged airshipctl config",
+		Long:    viewLong[1:],
+		Example: viewExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return config.RunView(cfgFactory, showOrigin, cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().BoolVar(&showOrigin, showOriginFlag, false,
+		"annotate values with the config file or environment variable they were loaded from")
+	return cmd
+}
@@ -0,0 +1,94 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package document
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/fs"
+)
+
+const (
+	cacheLong = `
+Provides commands to manage the cache of rendered documents.
+Documents are cached in the airshipctl work directory and reused
+until any of the files used to render them or any of the environment
+variables exported to KRM functions from the host is changed.
+Documents which may hold plaintext secrets, i.e. Secret documents and the ones
+rendered by templater or sops functions, are never cached. Neither are the documents
+rendered by function images not pinned by digest, functions with volume mounts
+or kustomizations with remote resources.
+`
+
+	pruneLong = `
+Remove outdated entries from the cache of rendered documents, i.e. the ones
+rendered from the files which have changed since then.
+`
+
+	pruneExample = `
+Remove outdated cache entries
+# airshipctl document cache prune
+
+Remove all cache entries
+# airshipctl document cache prune --all
+`
+)
+
+// NewCacheCommand creates a new command for managing the cache of rendered documents
+func NewCacheCommand(cfgFactory config.Factory) *cobra.Command {
+	cacheCmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Airshipctl command to manage the cache of rendered documents",
+		Long:  cacheLong[1:],
+	}
+
+	cacheCmd.AddCommand(NewCachePruneCommand(cfgFactory))
+	return cacheCmd
+}
+
+// NewCachePruneCommand creates a new command for removing outdated cache entries
+func NewCachePruneCommand(cfgFactory config.Factory) *cobra.Command {
+	var all bool
+	pruneCmd := &cobra.Command{
+		Use:     "prune",
+		Short:   "Airshipctl command to remove outdated entries from the cache of rendered documents",
+		Long:    pruneLong[1:],
+		Example: pruneExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := cfgFactory()
+			if err != nil {
+				return err
+			}
+			workDir, err := cfg.WorkDir()
+			if err != nil {
+				return err
+			}
+			removed, err := document.PruneBundleCache(fs.NewDocumentFs(), document.BundleCachePath(workDir), all)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Removed %d cache entries\n", removed)
+			return nil
+		},
+	}
+
+	pruneCmd.Flags().BoolVar(&all, "all", false, "remove all cache entries")
+	return pruneCmd
+}
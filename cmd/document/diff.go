@@ -0,0 +1,82 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package document
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document/diff"
+	"opendev.org/airship/airshipctl/pkg/phase"
+)
+
+const (
+	diffLong = `
+Compare rendered documents of the phase or of the kustomize entrypoint at two revisions
+of the phase repository. Revisions are checked out to temporary clones of the phase
+repository, so the working tree isn't modified. If the --to revision is not set, current
+content of the phase repository including uncommitted changes is used.
+
+Directories located next to the phase repository are checked out at the tag or the branch
+of the same name if they are git repositories having it, otherwise their current content
+is used and a warning is printed.
+
+Documents are matched by group, kind, namespace and name, so reordering of the documents
+isn't reported. Elements of the lists are matched by name if every element has a unique
+name, e.g. containers or volumes. The data of Secrets, SOPS encrypted values and SOPS
+metadata are masked unless --show-secrets is set, since they change on every generation
+or encryption of the secrets.
+
+If the argument is a directory relative to the root of the phase repository it's used as
+kustomize entrypoint, otherwise it's considered to be the name of the phase.
+`
+
+	diffExample = `
+Show changes of the phase made by the last commit
+# airshipctl document diff --from HEAD~1 --to HEAD initinfra
+
+Show changes of the kustomize entrypoint between the tag and uncommitted changes
+# airshipctl document diff --from v2.1.0 manifests/site/test-site/target/initinfra
+
+Show changes between two branches as JSON
+# airshipctl document diff --from master --to feature initinfra -o json
+`
+)
+
+// NewDiffCommand creates a new command for comparing rendered documents of two revisions
+func NewDiffCommand(cfgFactory config.Factory) *cobra.Command {
+	c := &phase.DiffCommand{Factory: cfgFactory}
+	diffCmd := &cobra.Command{
+		Use:     "diff PHASE_NAME|ENTRYPOINT",
+		Short:   "Airshipctl command to compare rendered documents of two revisions",
+		Long:    diffLong[1:],
+		Example: diffExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c.Target = args[0]
+			c.Writer = cmd.OutOrStdout()
+			return c.RunE()
+		},
+	}
+
+	flags := diffCmd.Flags()
+	flags.StringVar(&c.From, "from", "", "git revision of the phase repository to compare from, required")
+	flags.StringVar(&c.To, "to", "",
+		"git revision of the phase repository to compare to, current content is used if not set")
+	flags.BoolVar(&c.ShowSecrets, "show-secrets", false, "compare the data of Secrets and SOPS encrypted values")
+	flags.StringVarP(&c.OutputFormat, "output", "o", diff.TextOutputFormat,
+		"output format. Supported formats are 'text', 'yaml' and 'json'")
+	return diffCmd
+}
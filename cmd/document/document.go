@@ -33,6 +33,10 @@ func NewDocumentCommand(cfgFactory config.Factory) *cobra.Command {
 	}
 
 	documentRootCmd.AddCommand(NewPullCommand(cfgFactory))
+	documentRootCmd.AddCommand(NewCacheCommand(cfgFactory))
+	documentRootCmd.AddCommand(NewLintCommand(cfgFactory))
+	documentRootCmd.AddCommand(NewQueryCommand(cfgFactory))
+	documentRootCmd.AddCommand(NewDiffCommand(cfgFactory))
 
 	return documentRootCmd
 }
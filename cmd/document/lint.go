@@ -0,0 +1,75 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package document
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document/lint"
+	"opendev.org/airship/airshipctl/pkg/phase"
+)
+
+const (
+	lintLong = `
+Run static analysis of the rendered documents. Documents of the phase entrypoint
+or of the kustomize path are checked against the following rules:
+duplicate-resource-id, missing-namespace, unresolved-replacement-source,
+image-not-in-catalogue, deprecated-api-version and placeholder-secret-value.
+
+Rules can be configured per site with the LintConfig document of the phase bundle.
+Command fails if any finding with error severity is reported.
+`
+
+	lintExample = `
+Lint documents of the phase
+# airshipctl document lint initinfra
+
+Lint documents of the kustomize entrypoint and print SARIF report
+# airshipctl document lint --path manifests/site/test-site/target/initinfra -o sarif
+
+Lint documents using specific LintConfig document
+# airshipctl document lint initinfra --config strict-lint
+`
+)
+
+// NewLintCommand creates a new command for linting rendered documents
+func NewLintCommand(cfgFactory config.Factory) *cobra.Command {
+	c := &phase.LintCommand{Factory: cfgFactory}
+	lintCmd := &cobra.Command{
+		Use:     "lint [PHASE_NAME]",
+		Short:   "Airshipctl command to run static analysis of rendered documents",
+		Long:    lintLong[1:],
+		Example: lintExample,
+		Args:    cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				c.PhaseID.Name = args[0]
+			}
+			if (c.PhaseID.Name == "") == (c.Path == "") {
+				return ErrLintTarget{}
+			}
+			c.Writer = cmd.OutOrStdout()
+			return c.RunE()
+		},
+	}
+
+	flags := lintCmd.Flags()
+	flags.StringVar(&c.Path, "path", "", "path to the kustomize entrypoint to lint instead of the phase")
+	flags.StringVar(&c.ConfigName, "config", "", "name of the LintConfig document to use")
+	flags.StringVarP(&c.OutputFormat, "output", "o", lint.TableOutputFormat,
+		"output format. Supported formats are 'table', 'json' and 'sarif'")
+	return lintCmd
+}
@@ -0,0 +1,77 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package document
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document/query"
+	"opendev.org/airship/airshipctl/pkg/phase"
+)
+
+const (
+	queryLong = `
+Query rendered documents of the phase or of the kustomize entrypoint. Documents are
+selected by name, namespace, API version, kind, labels and annotations. If the field
+is requested, its value is printed for each of the selected documents and documents
+which don't have the field are skipped. The field is either a JSON path query enclosed
+in curly braces or a dot separated path.
+
+If the argument is an existing directory it's used as kustomize entrypoint, otherwise
+it's considered to be the name of the phase.
+`
+
+	queryExample = `
+List all Deployments of the phase
+# airshipctl document query initinfra --kind Deployment
+
+Print images of the containers of the kustomize entrypoint documents
+# airshipctl document query manifests/site/test-site/target/initinfra --kind Deployment \
+  --field '{.spec.template.spec.containers[*].image}'
+
+Print replicas of the documents labeled with app=web as JSON
+# airshipctl document query initinfra -l app=web --field spec.replicas -o json
+`
+)
+
+// NewQueryCommand creates a new command for querying rendered documents
+func NewQueryCommand(cfgFactory config.Factory) *cobra.Command {
+	c := &phase.QueryCommand{Factory: cfgFactory}
+	queryCmd := &cobra.Command{
+		Use:     "query PHASE_NAME|ENTRYPOINT",
+		Short:   "Airshipctl command to query rendered documents",
+		Long:    queryLong[1:],
+		Example: queryExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c.Target = args[0]
+			c.Writer = cmd.OutOrStdout()
+			return c.RunE()
+		},
+	}
+
+	flags := queryCmd.Flags()
+	flags.StringVar(&c.Name, "name", "", "select documents by name")
+	flags.StringVarP(&c.Namespace, "namespace", "n", "", "select documents by namespace")
+	flags.StringVar(&c.APIVersion, "api-version", "", "select documents by API version, e.g. apps/v1")
+	flags.StringVarP(&c.Kind, "kind", "k", "", "select documents by kind")
+	flags.StringVarP(&c.Labels, "labels", "l", "", "select documents by label selector, e.g. app=web,tier!=db")
+	flags.StringVarP(&c.Annotations, "annotations", "a", "", "select documents by annotation selector")
+	flags.StringVarP(&c.Field, "field", "f", "", "JSON path query or dot separated path of the field to print")
+	flags.StringVarP(&c.OutputFormat, "output", "o", query.TableOutputFormat,
+		"output format. Supported formats are 'table', 'yaml' and 'json'")
+	return queryCmd
+}
@@ -0,0 +1,78 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/phase"
+)
+
+const (
+	graphLong = `
+Get dependency graph of the kustomize entrypoints of all phases. Nodes of the graph are
+kustomization directories and files used by the phases directly or through resources,
+bases, components, generators and transformers. Each node is listed along with the phases
+which depend on it, so shared resources can be found.
+
+Use --affected-by flag to get only the phases and the nodes depending on the path, e.g.
+to find out which phases have to be tested after changing manifests/function/capi.
+The path is either absolute or relative to the root of the phase repository.
+`
+
+	graphExample = `
+List directories and files used by each phase
+# airshipctl phase graph
+
+Find phases affected by the change of capi function
+# airshipctl phase graph --affected-by manifests/function/capi
+
+Render the dependency graph as an image with graphviz
+# airshipctl phase graph -o dot | dot -Tsvg > phases.svg
+
+To output the graph in json format
+# airshipctl phase graph -o json
+`
+)
+
+// NewGraphCommand creates a command which prints dependency graph of the phases
+func NewGraphCommand(cfgFactory config.Factory) *cobra.Command {
+	p := &phase.GraphCommand{Factory: cfgFactory}
+
+	graphCmd := &cobra.Command{
+		Use:     "graph",
+		Short:   "Airshipctl command to show dependency graph of kustomize entrypoints of phases",
+		Long:    graphLong[1:],
+		Example: graphExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p.Writer = cmd.OutOrStdout()
+			return p.RunE()
+		},
+	}
+	addGraphFlags(p, graphCmd)
+	return graphCmd
+}
+
+// addGraphFlags adds flags for phase graph sub-command
+func addGraphFlags(options *phase.GraphCommand, cmd *cobra.Command) {
+	flags := cmd.Flags()
+
+	flags.StringVar(&options.Options.AffectedBy, "affected-by", "",
+		"show only the phases and the nodes depending on the path")
+	flags.StringVarP(&options.Options.FormatType, "output", "o", "table",
+		"output format. Supported formats are 'table', 'dot' and 'json'")
+}
@@ -37,6 +37,7 @@ func NewPhaseCommand(cfgFactory config.Factory) *cobra.Command {
 	phaseRootCmd.AddCommand(NewRenderCommand(cfgFactory))
 	phaseRootCmd.AddCommand(NewListCommand(cfgFactory))
 	phaseRootCmd.AddCommand(NewRunCommand(cfgFactory))
+	phaseRootCmd.AddCommand(NewGraphCommand(cfgFactory))
 	phaseRootCmd.AddCommand(NewTreeCommand(cfgFactory))
 	phaseRootCmd.AddCommand(NewValidateCommand(cfgFactory))
 	phaseRootCmd.AddCommand(NewStatusCommand(cfgFactory))
@@ -38,6 +38,18 @@ Get all documents from config bundle
 
 Get all documents executor rendered documents for a phase
 # airshipctl phase render initinfra --source executor
+
+Get all 'initinfra' phase documents annotated with the replacements and templates which produced them
+# airshipctl phase render initinfra --trace
+
+Write each 'initinfra' phase document to a separate file along with kustomization listing them
+# airshipctl phase render initinfra --format kustomization --output-dir ./initinfra
+
+Pack 'initinfra' phase documents to a tarball
+# airshipctl phase render initinfra --format tar > initinfra.tar.gz
+
+Write 'initinfra' phase documents to OCI image layout to ship them to air-gapped site
+# airshipctl phase render initinfra --format oci --output-dir ./initinfra-oci
 `
 )
 
@@ -73,6 +85,16 @@ func addRenderFlags(filterOptions *phase.RenderCommand, cmd *cobra.Command) {
 			"config: this will render bundle containing phase and executor documents")
 	flags.BoolVarP(&filterOptions.FailOnDecryptionError, "decrypt", "d", false,
 		"ensure that decryption of encrypted documents has finished successfully")
+	flags.BoolVar(&filterOptions.Trace, "trace", false,
+		"annotate documents with the replacements and templates which modified them, only built-in functions are traced")
+	flags.StringVar(&filterOptions.Format, "format", phase.RenderFormatYAML,
+		"yaml: documents will be written to the output as multi-document yaml\n"+
+			"directory: each document will be written to <namespace>/<kind>-<name>.yaml file of the output directory\n"+
+			"kustomization: the same as directory, kustomization listing the documents will be added\n"+
+			"tar: gzipped tarball of the kustomization directory will be written to the output\n"+
+			"oci: OCI image layout with the tarball as a layer will be written to the output directory")
+	flags.StringVar(&filterOptions.OutputDir, "output-dir", "",
+		"directory to write the documents to, required by directory, kustomization and oci formats")
 }
 
 // RenderArgs returns an error if there are not exactly n args.
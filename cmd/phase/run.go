@@ -55,6 +55,8 @@ func NewRunCommand(cfgFactory config.Factory) *cobra.Command {
 					p.Options.DryRun = f.DryRun
 				case "wait-timeout":
 					p.Options.Timeout = &f.Timeout
+				case "enforce-policies":
+					p.Options.EnforcePolicies = f.EnforcePolicies
 				}
 			}
 			cmd.Flags().Visit(fn)
@@ -64,5 +66,7 @@ func NewRunCommand(cfgFactory config.Factory) *cobra.Command {
 	flags := runCmd.Flags()
 	flags.BoolVar(&f.DryRun, "dry-run", false, "simulate phase execution")
 	flags.DurationVar(&f.Timeout, "wait-timeout", 0, "wait timeout")
+	flags.BoolVar(&f.EnforcePolicies, "enforce-policies", false,
+		"check rendered documents against validation policies before running the phase")
 	return runCmd
 }
@@ -30,6 +30,9 @@ import (
 	"opendev.org/airship/airshipctl/cmd/phase"
 	"opendev.org/airship/airshipctl/cmd/plan"
 	cfg "opendev.org/airship/airshipctl/pkg/config"
+	doc "opendev.org/airship/airshipctl/pkg/document"
+	// Import to execute templater and replacement-transformer functions in-process.
+	_ "opendev.org/airship/airshipctl/pkg/document/plugin"
 	"opendev.org/airship/airshipctl/pkg/log"
 )
 
@@ -42,6 +45,7 @@ const longRoot = `Command line utility for management of end-to-end kubernetes c
 type RootOptions struct {
 	Debug             bool
 	AirshipConfigPath string
+	NoCache           bool
 }
 
 // NewAirshipCTLCommand creates a root `airshipctl` command with the default commands attached
@@ -65,6 +69,9 @@ func NewRootCommand(out io.Writer) (*cobra.Command, *RootOptions) {
 		SilenceUsage:  true,
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
 			log.Init(options.Debug, cmd.ErrOrStderr())
+			if !options.NoCache {
+				doc.SetBundleCacheDir(doc.BundleCachePath(cfg.DefaultWorkDir()))
+			}
 		},
 	}
 	rootCmd.SetOut(out)
@@ -97,4 +104,5 @@ func initFlags(options *RootOptions, cmd *cobra.Command) {
 	defaultAirshipConfigPath := filepath.Join(defaultAirshipConfigDir, cfg.AirshipConfig)
 	flags.StringVar(&options.AirshipConfigPath, "airshipconf", "",
 		`path to the airshipctl configuration file. Defaults to "`+defaultAirshipConfigPath+`"`)
+	flags.BoolVar(&options.NoCache, "no-cache", false, "disable the cache of rendered documents")
 }
@@ -42,6 +42,8 @@ type BootstrapContainer struct {
 	Image            string `json:"image,omitempty"`
 	Volume           string `json:"volume,omitempty"`
 	Kubeconfig       string `json:"saveKubeconfigFileName,omitempty"`
+
+	ImageVerification *ImageVerification `json:"imageVerification,omitempty"`
 }
 
 // DefaultBootConfiguration can be used to safely unmarshal BootConfiguration object without nil pointers
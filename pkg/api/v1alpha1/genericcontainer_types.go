@@ -35,9 +35,6 @@ const (
 	KubeConfigEnvKeyContext = "KCTL_CONTEXT"
 	// KubeConfigEnv uses as a kubeconfig env variable
 	KubeConfigEnv = KubeConfigEnvKey + "=" + KubeConfigPath
-
-	// ValidatorPreventCleanup is an env variable that prevents validator to clean up its working directory after finish
-	ValidatorPreventCleanup = "VALIDATOR_PREVENT_CLEANUP"
 )
 
 // +kubebuilder:object:root=true
@@ -95,6 +92,26 @@ type GenericContainerSpec struct {
 
 	// Mounts are the storage or directories to mount into the container
 	StorageMounts []StorageMount `json:"mounts,omitempty" yaml:"mounts,omitempty"`
+
+	// Steps is an ordered list of containers to run instead of the single one defined by Image,
+	// output bundle of each step is passed to stdin of the next step. All other settings of the spec
+	// are shared between the steps.
+	Steps []ContainerStep `json:"steps,omitempty"`
+}
+
+// ContainerStep defines a single step of the generic container pipeline
+type ContainerStep struct {
+	// Name of the step used in events and error messages
+	Name string `json:"name,omitempty"`
+
+	// Image is the container image to run, Image of the spec is used if it's empty
+	Image string `json:"image,omitempty"`
+
+	// Cmd to run inside the airship container, Cmd of the spec is used if it's empty
+	Cmd []string `json:"cmd,omitempty"`
+
+	// EnvVars are appended to env variables of the spec
+	EnvVars []string `json:"envVars,omitempty"`
 }
 
 // AirshipContainerSpec airship container settings
@@ -108,6 +125,9 @@ type AirshipContainerSpec struct {
 
 	// Privileged identifies if the container is to be run in a Privileged mode
 	Privileged bool `json:"privileged,omitempty"`
+
+	// ImageVerification defines checks of the image that must pass before the container is run
+	ImageVerification *ImageVerification `json:"imageVerification,omitempty"`
 }
 
 // KRMContainerSpec defines a spec for running a function as a container
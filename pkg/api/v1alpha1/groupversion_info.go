@@ -54,6 +54,8 @@ func init() {
 		&GenericContainer{},
 		&BaremetalManager{},
 		&ManifestMetadata{},
+		&LintConfig{},
+		&ValidationPolicy{},
 	)
 	_ = AddToScheme(Scheme) //nolint:errcheck
 }
@@ -0,0 +1,54 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+// ImageVerification defines supply-chain checks that must pass before the container image is run
+type ImageVerification struct {
+	// RequireDigest allows only images referenced by digest to be run,
+	// e.g. quay.io/airshipit/toolbox@sha256:<hex>
+	RequireDigest bool `json:"requireDigest,omitempty"`
+
+	// VersionsCatalogueRef is a reference to the VersionsCatalogue document which is used
+	// to resolve tagged image references to digests. Entries of image_components and
+	// image_repositories with the digest property defined are taken into account.
+	VersionsCatalogueRef *v1.ObjectReference `json:"versionsCatalogueRef,omitempty"`
+
+	// Signatures hold detached signatures of the images, if specified the signature of each
+	// image is verified offline before the container is started. Every image run under the
+	// policy, including the ones of container steps, must have its own signature in the list
+	Signatures []ImageSignature `json:"signatures,omitempty"`
+}
+
+// ImageSignature is a detached image signature in cosign-compatible format
+type ImageSignature struct {
+	// Image is the repository of the signed image without tag and digest,
+	// e.g. quay.io/airshipit/toolbox
+	Image string `json:"image"`
+
+	// PublicKey is a PEM encoded public key which is used to verify the signature,
+	// ECDSA, RSA and Ed25519 keys are supported
+	PublicKey string `json:"publicKey,omitempty"`
+
+	// Payload is a base64 encoded cosign simple signing payload, which contains
+	// the docker reference and the manifest digest of the signed image
+	Payload string `json:"payload,omitempty"`
+
+	// Signature is a base64 encoded signature of the payload
+	Signature string `json:"signature,omitempty"`
+}
@@ -0,0 +1,55 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+
+// LintConfig configures the rules used by document lint command for the site
+type LintConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec LintConfigSpec `json:"spec,omitempty"`
+}
+
+// LintConfigSpec defines lint rules settings
+type LintConfigSpec struct {
+	// Rules overrides settings of the rules by rule ID, rules which aren't
+	// listed here are enabled with the default severity
+	Rules map[string]LintRule `json:"rules,omitempty"`
+
+	// ClusterScopedKinds extends the list of kinds which aren't required to have a namespace
+	ClusterScopedKinds []string `json:"clusterScopedKinds,omitempty"`
+
+	// DeprecatedAPIVersions extends the list of deprecated API versions. Key is either
+	// apiVersion/Kind or apiVersion, value is the API version which should be used instead
+	DeprecatedAPIVersions map[string]string `json:"deprecatedAPIVersions,omitempty"`
+
+	// PlaceholderPatterns extends the list of regular expressions matching placeholder secret values
+	PlaceholderPatterns []string `json:"placeholderPatterns,omitempty"`
+}
+
+// LintRule defines settings of the lint rule
+type LintRule struct {
+	// Disabled turns the rule off
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Severity overrides the default severity of the rule findings, one of error, warning or note
+	Severity string `json:"severity,omitempty"`
+}
@@ -46,12 +46,15 @@ type ValidationConfig struct {
 	// definitions without a schema.
 	IgnoreMissingSchemas *bool `json:"ignoreMissingSchemas,omitempty"`
 
-	// KubernetesVersion is the version of Kubernetes to validate
-	// against (default "1.18.6").
+	// KubernetesVersion is the version of Kubernetes to validate against (default "1.20.4").
+	// Schemas built into airshipctl are of the default version only, schemas of other versions
+	// must be provided in the schema location
 	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
 
-	// SchemaLocation is the base URL from which to search for schemas.
-	// It can be either a remote location or a local directory
+	// SchemaLocation is a local directory with additional schemas named in kubeval manner,
+	// e.g. deployment-apps-v1.json, either directly or in the v<version>-standalone[-strict]
+	// subdirectory of the Kubernetes version. Kubernetes schemas built into airshipctl are
+	// used for the resources which don't have a schema there
 	SchemaLocation string `json:"schemaLocation,omitempty"`
 
 	// KindsToSkip defines Kinds which will be skipped during validation
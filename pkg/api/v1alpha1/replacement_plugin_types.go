@@ -86,12 +86,47 @@ type ReplSource struct {
 	ObjRef   *Target `json:"objref,omitempty" yaml:"objref,omitempty"`
 	FieldRef string  `json:"fieldref,omitempty" yaml:"fiedldref,omitempty"`
 	Value    string  `json:"value,omitempty" yaml:"value,omitempty"`
+
+	// Optional skips the replacement without an error if the source resource or field is not found
+	Optional bool `json:"optional,omitempty" yaml:"optional,omitempty"`
+	// Default is used as the value if the source resource or field is not found
+	Default string `json:"default,omitempty" yaml:"default,omitempty"`
+
+	// Format is a printf-style format string, e.g. "%s:%s", the values of Sources are substituted into
+	Format  string       `json:"format,omitempty" yaml:"format,omitempty"`
+	Sources []ReplSource `json:"sources,omitempty" yaml:"sources,omitempty"`
+
+	// Transformations are applied to the value in the given order
+	Transformations []ReplTransformation `json:"transformations,omitempty" yaml:"transformations,omitempty"`
+}
+
+// ReplTransformation defines modification of the replacement value. Type is one of
+//  - base64Encode, base64Decode
+//  - prefix, suffix - Value is prepended or appended to the replacement value
+//  - nthHost - returns IP address of the Value-th host of the CIDR, e.g. 10.0.0.0/24 and 5 give 10.0.0.5
+//  - network, netmask, prefixLength - return corresponding part of the CIDR
+type ReplTransformation struct {
+	Type  string `json:"type" yaml:"type"`
+	Value string `json:"value,omitempty" yaml:"value,omitempty"`
 }
 
 // ReplTarget defines where a substitution is to.
+// FieldRefs may contain wildcards, e.g. spec.template.spec.containers[*].image
 type ReplTarget struct {
 	ObjRef    *Selector `json:"objref,omitempty" yaml:"objref,omitempty"`
 	FieldRefs []string  `json:"fieldrefs,omitempty" yaml:"fieldrefs,omitempty"`
+
+	// NameRegex selects target resources which names match the regular expression,
+	// the whole name must match, e.g. web-.* matches web-1
+	NameRegex string `json:"nameRegex,omitempty" yaml:"nameRegex,omitempty"`
+	// AllObjects explicitly selects all resources of the kind specified in objref,
+	// objref must not have a name in this case. It's equivalent to objref without name
+	// and nameRegex, which selects all resources matching the rest of objref as well,
+	// but additionally requires the kind to be set
+	AllObjects bool `json:"allObjects,omitempty" yaml:"allObjects,omitempty"`
+	// Required makes the replacement fail if none of the fieldrefs matched a field in the target
+	// resources, e.g. a wildcard or filter path matched nothing
+	Required bool `json:"required,omitempty" yaml:"required,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -103,4 +138,7 @@ type ReplacementTransformer struct {
 
 	// Replacements list of source and target field to do a replacement
 	Replacements []Replacement `json:"replacements,omitempty" yaml:"replacements,omitempty"`
+	// Report makes the transformer print how many fields and resources were updated
+	// by each replacement target, the report is printed with debug logs otherwise
+	Report bool `json:"report,omitempty" yaml:"report,omitempty"`
 }
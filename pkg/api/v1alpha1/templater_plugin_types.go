@@ -36,4 +36,19 @@ type Templater struct {
 	// Template field is used to specify actual go-template which is going
 	// to be used to render the object defined in Spec field
 	Template string `json:"template,omitempty"`
+
+	// Seed makes keys, certificates and strings produced by the seeded template
+	// function reproducible between renders
+	Seed *TemplaterSeed `json:"seed,omitempty"`
+}
+
+// TemplaterSeed defines the source of deterministically generated material
+type TemplaterSeed struct {
+	// Value is a secret all generated material is derived from, it is expected to be
+	// set by the replacement transformer from an encrypted document
+	Value string `json:"value,omitempty"`
+
+	// NotBefore is the start of validity of generated certificates in RFC3339 format,
+	// required to generate certificates
+	NotBefore string `json:"notBefore,omitempty"`
 }
@@ -0,0 +1,87 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// PolicyActionDeny fails validation of the phase if the rule is violated
+	PolicyActionDeny = "deny"
+	// PolicyActionWarn only reports violation of the rule
+	PolicyActionWarn = "warn"
+)
+
+// +kubebuilder:object:root=true
+
+// ValidationPolicy defines rules which rendered documents of the phases must satisfy
+type ValidationPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ValidationPolicySpec `json:"spec,omitempty"`
+}
+
+// ValidationPolicySpec defines the rules of the policy
+type ValidationPolicySpec struct {
+	// Phases limits the policy to the phases with given names, policy applies
+	// to all phases if the list is empty
+	Phases []string `json:"phases,omitempty"`
+
+	Rules []PolicyRule `json:"rules"`
+}
+
+// PolicyRule defines a check evaluated against each matching document. Conditions are
+// JSONPath filter expressions, e.g. "@.spec.replicas > 1", evaluated against the document
+// or each element selected by ForEach. Besides comparisons they support logical operators
+// && and ||, negation !, regex match =~ /regex/, existence check and length(path), see
+// JSONPathFilter of kyamlutils. Exactly one of Forbid and Require must be set
+type PolicyRule struct {
+	Name string `json:"name"`
+
+	// Action is either deny (default) or warn
+	Action string `json:"action,omitempty"`
+
+	// Message explains the violation to the user
+	Message string `json:"message,omitempty"`
+
+	Match PolicyMatch `json:"match,omitempty"`
+
+	// ForEach is a JSONPath selecting the elements of the document to check,
+	// e.g. "{.spec.template.spec.containers[*]}"
+	ForEach string `json:"forEach,omitempty"`
+
+	// Forbid is a condition which must not be met
+	Forbid string `json:"forbid,omitempty"`
+
+	// Require is a condition which must be met
+	Require string `json:"require,omitempty"`
+}
+
+// PolicyMatch selects documents the rule applies to, empty match selects all documents
+type PolicyMatch struct {
+	// Kinds of the documents
+	Kinds []string `json:"kinds,omitempty"`
+
+	// Namespaces of the documents
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// ExcludeNamespaces are the namespaces which documents are ignored
+	ExcludeNamespaces []string `json:"excludeNamespaces,omitempty"`
+
+	// LabelSelector is a label selector of the documents, e.g. "app=web,tier!=db"
+	LabelSelector string `json:"labelSelector,omitempty"`
+}
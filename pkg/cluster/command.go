@@ -87,6 +87,7 @@ func (cmd *GetKubeconfigCommand) RunE(cfgFactory config.Factory, writer io.Write
 	kubeconf := kubeconfig.NewBuilder().
 		WithBundle(helper.PhaseConfigBundle()).
 		WithClusterMap(cMap).
+		WithKubeconfigOverrides(helper.KubeconfigOverrides()).
 		WithClusterName(cmd.ClusterName).
 		WithTempRoot(helper.WorkDir()).
 		SiteWide(siteWide).
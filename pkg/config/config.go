@@ -60,6 +60,12 @@ type Config struct {
 	// +not persisted in file
 	loadedConfigPath string
 	fileSystem       fs.FileSystem
+
+	// origins maps config fields to the config files or environment variables they were loaded from
+	// +not persisted in file
+	origins      map[string]string
+	layered      []layeredField
+	envOverrides []envOverride
 }
 
 // Permissions has the permissions for file and directory
@@ -115,20 +121,16 @@ func (c *Config) initConfigPath(airshipConfigPath string) {
 }
 
 // LoadConfig populates the Config from the file found at airshipConfigPath.
-// If there is no file at airshipConfigPath, this function does nothing.
+// The file is layered on top of the system wide config and the per-project
+// .airship/config found by walking up from the current directory overrides it if
+// the project is trusted, AIRSHIP_* environment variables override the current
+// context and its manifest.
 // An error is returned if:
 // * airshipConfigPath is the empty string
 // * the file at airshipConfigPath is inaccessible
-// * the file at airshipConfigPath cannot be marshaled into Config
+// * any of the config files cannot be marshaled into Config
 func (c *Config) LoadConfig() error {
-	// If I can read from the file, load from it
-	// throw an error otherwise
-	data, err := c.fileSystem.ReadFile(c.loadedConfigPath)
-	if err != nil {
-		return err
-	}
-
-	return yaml.Unmarshal(data, c)
+	return c.loadLayers()
 }
 
 // EnsureComplete verifies that a Config object is ready to use.
@@ -181,13 +183,15 @@ func (c *Config) SetFs(fsys fs.FileSystem) {
 // PersistConfig updates the airshipctl config file to match
 // the current Config object.
 // If file did not previously exist, the file will be created.
-// The file will be overwritten if overwrite argument set to true
+// The file will be overwritten if overwrite argument set to true.
+// Values of environment variables and unchanged values loaded from
+// the system wide and per-project config files are not persisted
 func (c *Config) PersistConfig(overwrite bool) error {
 	if _, err := os.Stat(c.loadedConfigPath); err == nil && !overwrite {
 		return ErrConfigFileExists{Path: c.loadedConfigPath}
 	}
 
-	airshipConfigYaml, err := c.ToYaml()
+	airshipConfigYaml, err := c.persistentYaml()
 	if err != nil {
 		return err
 	}
@@ -513,14 +517,28 @@ func (c *Config) CurrentContextManagementConfig() (*ManagementConfiguration, err
 	return managementCfg, nil
 }
 
+// CurrentContextKubeconfigOverrides returns the kubeconfig overrides of the current context keyed by cluster name
+func (c *Config) CurrentContextKubeconfigOverrides() (map[string]*KubeconfigOverride, error) {
+	currentContext, err := c.GetCurrentContext()
+	if err != nil {
+		return nil, err
+	}
+	return currentContext.KubeconfigOverrides, nil
+}
+
 // Purge removes the config file
 func (c *Config) Purge() error {
 	return c.fileSystem.RemoveAll(c.loadedConfigPath)
 }
 
+// DefaultWorkDir returns path of the working directory for airshipctl
+func DefaultWorkDir() string {
+	return filepath.Join(util.UserHomeDir(), AirshipConfigDir)
+}
+
 // WorkDir returns working directory for airshipctl. Creates if it doesn't exist
 func (c *Config) WorkDir() (dir string, err error) {
-	dir = filepath.Join(util.UserHomeDir(), AirshipConfigDir)
+	dir = DefaultWorkDir()
 	// if not dir, create it
 	if !c.fileSystem.IsDir(dir) {
 		err = c.fileSystem.MkdirAll(dir)
@@ -545,3 +563,166 @@ func (c *Config) ModifyManagementConfig(mgmtConfig *ManagementConfiguration, opt
 		o(mgmtConfig)
 	}
 }
+
+// DeleteContext removes the context. The current context is removed only if switchTo
+// names another context, which becomes the current one
+func (c *Config) DeleteContext(name, switchTo string) error {
+	if _, err := c.GetContext(name); err != nil {
+		return err
+	}
+	if err := c.checkEntryOrigin(fmt.Sprintf("context with name '%s'", name), "contexts", name); err != nil {
+		return err
+	}
+	if c.CurrentContext == name {
+		if switchTo == "" || switchTo == name {
+			return ErrDeleteCurrentContext{Name: name}
+		}
+		if _, err := c.GetContext(switchTo); err != nil {
+			return err
+		}
+		c.CurrentContext = switchTo
+	}
+	delete(c.Contexts, name)
+	return nil
+}
+
+// RenameContext changes the name of the context and updates the current context if needed
+func (c *Config) RenameContext(oldName, newName string) error {
+	if newName == "" {
+		return ErrEmptyContextName{}
+	}
+	context, err := c.GetContext(oldName)
+	if err != nil {
+		return err
+	}
+	if err = c.checkEntryOrigin(fmt.Sprintf("context with name '%s'", oldName), "contexts", oldName); err != nil {
+		return err
+	}
+	if _, exists := c.Contexts[newName]; exists {
+		return ErrContextAlreadyExists{Name: newName}
+	}
+	delete(c.Contexts, oldName)
+	c.Contexts[newName] = context
+	if c.CurrentContext == oldName {
+		c.CurrentContext = newName
+	}
+	return nil
+}
+
+// DeleteManifest removes the manifest. Manifest used by contexts is removed only
+// if force is set, the contexts are left without manifest in this case. Manifest of
+// the current context is never removed
+func (c *Config) DeleteManifest(name string, force bool) error {
+	if _, err := c.GetManifest(name); err != nil {
+		return err
+	}
+	what := fmt.Sprintf("manifest with name '%s'", name)
+	if err := c.checkEntryOrigin(what, "manifests", name); err != nil {
+		return err
+	}
+	if context, exists := c.Contexts[c.CurrentContext]; exists && context.Manifest == name {
+		return ErrUsedByCurrentContext{What: what, Context: c.CurrentContext}
+	}
+	referencedBy := c.contextsReferencing(func(context *Context) bool { return context.Manifest == name })
+	if len(referencedBy) > 0 {
+		if !force {
+			return ErrConfigEntryReferenced{
+				What:         what,
+				ReferencedBy: referencedBy,
+			}
+		}
+		for _, context := range c.Contexts {
+			if context.Manifest == name {
+				context.Manifest = ""
+			}
+		}
+	}
+	delete(c.Manifests, name)
+	return nil
+}
+
+// DeleteRepository removes the repository from the manifest. Phase or inventory repository
+// is removed only if force is set, the manifest is left without it in this case. Phase and
+// inventory repositories of the manifest used by the current context are never removed
+func (c *Config) DeleteRepository(manifestName, repoName string, force bool) error {
+	manifest, err := c.GetManifest(manifestName)
+	if err != nil {
+		return err
+	}
+	if _, exists := manifest.Repositories[repoName]; !exists {
+		return ErrRepositoryNotFound{Name: repoName}
+	}
+	err = c.checkEntryOrigin(fmt.Sprintf("manifest with name '%s'", manifestName), "manifests", manifestName)
+	if err != nil {
+		return err
+	}
+	var referencedBy []string
+	if manifest.PhaseRepositoryName == repoName {
+		referencedBy = append(referencedBy, fmt.Sprintf("phase repository of manifest '%s'", manifestName))
+	}
+	if manifest.InventoryRepositoryName == repoName {
+		referencedBy = append(referencedBy, fmt.Sprintf("inventory repository of manifest '%s'", manifestName))
+	}
+	if len(referencedBy) > 0 {
+		what := fmt.Sprintf("repository with name '%s'", repoName)
+		if context, exists := c.Contexts[c.CurrentContext]; exists && context.Manifest == manifestName {
+			return ErrUsedByCurrentContext{What: what, Context: c.CurrentContext}
+		}
+		if !force {
+			return ErrConfigEntryReferenced{
+				What:         what,
+				ReferencedBy: referencedBy,
+			}
+		}
+		if manifest.PhaseRepositoryName == repoName {
+			manifest.PhaseRepositoryName = ""
+		}
+		if manifest.InventoryRepositoryName == repoName {
+			manifest.InventoryRepositoryName = ""
+		}
+	}
+	delete(manifest.Repositories, repoName)
+	return nil
+}
+
+// DeleteManagementConfig removes the management configuration. Management configuration
+// used by contexts is removed only if force is set, the contexts are left without it in this case
+func (c *Config) DeleteManagementConfig(name string, force bool) error {
+	if _, err := c.GetManagementConfiguration(name); err != nil {
+		return err
+	}
+	what := fmt.Sprintf("management configuration with name '%s'", name)
+	if err := c.checkEntryOrigin(what, "managementConfiguration", name); err != nil {
+		return err
+	}
+	referencedBy := c.contextsReferencing(func(context *Context) bool {
+		return context.ManagementConfiguration == name
+	})
+	if len(referencedBy) > 0 {
+		if !force {
+			return ErrConfigEntryReferenced{
+				What:         what,
+				ReferencedBy: referencedBy,
+			}
+		}
+		for _, context := range c.Contexts {
+			if context.ManagementConfiguration == name {
+				context.ManagementConfiguration = ""
+			}
+		}
+	}
+	delete(c.ManagementConfiguration, name)
+	return nil
+}
+
+// contextsReferencing returns sorted descriptions of the contexts matching the filter
+func (c *Config) contextsReferencing(filter func(*Context) bool) []string {
+	var referencedBy []string
+	for name, context := range c.Contexts {
+		if filter(context) {
+			referencedBy = append(referencedBy, fmt.Sprintf("context '%s'", name))
+		}
+	}
+	sort.Strings(referencedBy)
+	return referencedBy
+}
@@ -38,6 +38,52 @@ func SetContextManagementConfig(managementConfig string) ContextOption {
 	}
 }
 
+// SetContextKubeconfigOverride modifies kubeconfig override of the cluster in context object,
+// the override is removed if none of its fields is set
+func SetContextKubeconfigOverride(cluster string, opts ...KubeconfigOverrideOption) ContextOption {
+	return func(ctx *Context) {
+		override, exists := ctx.KubeconfigOverrides[cluster]
+		if !exists {
+			override = &KubeconfigOverride{}
+		}
+		for _, opt := range opts {
+			opt(override)
+		}
+		if *override == (KubeconfigOverride{}) {
+			delete(ctx.KubeconfigOverrides, cluster)
+			return
+		}
+		if ctx.KubeconfigOverrides == nil {
+			ctx.KubeconfigOverrides = map[string]*KubeconfigOverride{}
+		}
+		ctx.KubeconfigOverrides[cluster] = override
+	}
+}
+
+// KubeconfigOverrideOption is a function that allows to modify KubeconfigOverride object
+type KubeconfigOverrideOption func(override *KubeconfigOverride)
+
+// SetKubeconfigOverridePath sets Path in KubeconfigOverride object
+func SetKubeconfigOverridePath(path string) KubeconfigOverrideOption {
+	return func(override *KubeconfigOverride) {
+		override.Path = path
+	}
+}
+
+// SetKubeconfigOverrideContextName sets ContextName in KubeconfigOverride object
+func SetKubeconfigOverrideContextName(contextName string) KubeconfigOverrideOption {
+	return func(override *KubeconfigOverride) {
+		override.ContextName = contextName
+	}
+}
+
+// SetKubeconfigOverrideProxyURL sets ProxyURL in KubeconfigOverride object
+func SetKubeconfigOverrideProxyURL(proxyURL string) KubeconfigOverrideOption {
+	return func(override *KubeconfigOverride) {
+		override.ProxyURL = proxyURL
+	}
+}
+
 // RunSetContextOptions are options required to create/modify airshipctl context
 type RunSetContextOptions struct {
 	CfgFactory Factory
@@ -236,3 +282,105 @@ func RunGetManifest(cfgFactory Factory, manifestName string, w io.Writer) error
 	_, err = w.Write(data)
 	return err
 }
+
+// RunView prints the config, fields are annotated with the config file
+// or environment variable they were loaded from if showOrigin is set
+func RunView(cfgFactory Factory, showOrigin bool, w io.Writer) error {
+	cfg, err := cfgFactory()
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	if showOrigin {
+		data, err = cfg.ToYamlWithOrigins()
+	} else {
+		data, err = cfg.ToYaml()
+	}
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// RunDeleteOptions are options required to delete an entry of airshipctl config
+type RunDeleteOptions struct {
+	CfgFactory Factory
+	Name       string
+	Force      bool
+	SwitchTo   string
+	Writer     io.Writer
+}
+
+// RunDeleteContext deletes the context from airshipctl config
+func (o *RunDeleteOptions) RunDeleteContext() error {
+	return o.runDelete(fmt.Sprintf("context with name %s", o.Name), func(cfg *Config) error {
+		return cfg.DeleteContext(o.Name, o.SwitchTo)
+	})
+}
+
+// RunDeleteManifest deletes the manifest from airshipctl config, only the repository
+// of the manifest is deleted if repoName is not empty
+func (o *RunDeleteOptions) RunDeleteManifest(repoName string) error {
+	if repoName != "" {
+		return o.runDelete(fmt.Sprintf("repository with name %s of manifest %s", repoName, o.Name),
+			func(cfg *Config) error {
+				return cfg.DeleteRepository(o.Name, repoName, o.Force)
+			})
+	}
+	return o.runDelete(fmt.Sprintf("manifest with name %s", o.Name), func(cfg *Config) error {
+		return cfg.DeleteManifest(o.Name, o.Force)
+	})
+}
+
+// RunDeleteManagementConfig deletes the management configuration from airshipctl config
+func (o *RunDeleteOptions) RunDeleteManagementConfig() error {
+	return o.runDelete(fmt.Sprintf("management configuration with name %s", o.Name), func(cfg *Config) error {
+		return cfg.DeleteManagementConfig(o.Name, o.Force)
+	})
+}
+
+func (o *RunDeleteOptions) runDelete(what string, deleteFn func(*Config) error) error {
+	cfg, err := o.CfgFactory()
+	if err != nil {
+		return err
+	}
+
+	if err = deleteFn(cfg); err != nil {
+		return err
+	}
+
+	// Verify we didn't break anything
+	if err = cfg.EnsureComplete(); err != nil {
+		return err
+	}
+
+	if _, err = fmt.Fprintf(o.Writer, "%s deleted\n", what); err != nil {
+		return err
+	}
+	// Update configuration file just in time persistence approach
+	return cfg.PersistConfig(true)
+}
+
+// RunRenameContext changes the name of the context in airshipctl config
+func RunRenameContext(cfgFactory Factory, oldName, newName string, w io.Writer) error {
+	cfg, err := cfgFactory()
+	if err != nil {
+		return err
+	}
+
+	if err = cfg.RenameContext(oldName, newName); err != nil {
+		return err
+	}
+
+	// Verify we didn't break anything
+	if err = cfg.EnsureComplete(); err != nil {
+		return err
+	}
+
+	if _, err = fmt.Fprintf(w, "context %s renamed to %s\n", oldName, newName); err != nil {
+		return err
+	}
+	return cfg.PersistConfig(true)
+}
@@ -196,6 +196,48 @@ func TestCurrentContextManagementConfig(t *testing.T) {
 	assert.Equal(t, conf.ManagementConfiguration[defaultString], managementConfig)
 }
 
+func TestCurrentContextKubeconfigOverrides(t *testing.T) {
+	conf, cleanup := testutil.InitConfig(t)
+	defer cleanup(t)
+
+	conf.CurrentContext = "foo"
+	_, err := conf.CurrentContextKubeconfigOverrides()
+	require.Error(t, err)
+
+	conf.CurrentContext = currentContextName
+	overrides, err := conf.CurrentContextKubeconfigOverrides()
+	require.NoError(t, err)
+	assert.Empty(t, overrides)
+
+	conf.ModifyContext(conf.Contexts[currentContextName], config.SetContextKubeconfigOverride("target-cluster",
+		config.SetKubeconfigOverridePath("/home/user/.kube/target"),
+		config.SetKubeconfigOverrideProxyURL("socks5://bastion:1080")))
+	require.NoError(t, conf.PersistConfig(true))
+
+	loaded := config.NewEmptyConfig()
+	loaded.SetLoadedConfigPath(conf.LoadedConfigPath())
+	require.NoError(t, loaded.LoadConfig())
+	loaded.CurrentContext = currentContextName
+	overrides, err = loaded.CurrentContextKubeconfigOverrides()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*config.KubeconfigOverride{
+		"target-cluster": {
+			Path:     "/home/user/.kube/target",
+			ProxyURL: "socks5://bastion:1080",
+		},
+	}, overrides)
+
+	// override is removed once all its fields are empty
+	conf.ModifyContext(conf.Contexts[currentContextName], config.SetContextKubeconfigOverride("target-cluster",
+		config.SetKubeconfigOverrideContextName("target")))
+	assert.Equal(t, "target", conf.Contexts[currentContextName].KubeconfigOverrides["target-cluster"].ContextName)
+	conf.ModifyContext(conf.Contexts[currentContextName], config.SetContextKubeconfigOverride("target-cluster",
+		config.SetKubeconfigOverridePath(""),
+		config.SetKubeconfigOverrideContextName(""),
+		config.SetKubeconfigOverrideProxyURL("")))
+	assert.Empty(t, conf.Contexts[currentContextName].KubeconfigOverrides)
+}
+
 func TestPurge(t *testing.T) {
 	conf, cleanup := testutil.InitConfig(t)
 	defer cleanup(t)
@@ -464,3 +506,169 @@ func TestModifyManagementConfig(t *testing.T) {
 		managementConfig.SystemActionRetries)
 	assert.EqualValues(t, conf.ManagementConfiguration["modified_mgmt_config"], managementConfig)
 }
+
+func TestDeleteContext(t *testing.T) {
+	tests := []struct {
+		name           string
+		context        string
+		switchTo       string
+		currentContext string
+		expectedErr    error
+	}{
+		{
+			name:           "not current",
+			context:        "other_context",
+			currentContext: "dummy_context",
+		},
+		{
+			name:        "current",
+			context:     "dummy_context",
+			expectedErr: config.ErrDeleteCurrentContext{Name: "dummy_context"},
+		},
+		{
+			name:        "current with switch to itself",
+			context:     "dummy_context",
+			switchTo:    "dummy_context",
+			expectedErr: config.ErrDeleteCurrentContext{Name: "dummy_context"},
+		},
+		{
+			name:        "current with switch to missing context",
+			context:     "dummy_context",
+			switchTo:    "foo",
+			expectedErr: config.ErrMissingConfig{What: "context with name 'foo'"},
+		},
+		{
+			name:           "current with switch to",
+			context:        "dummy_context",
+			switchTo:       "other_context",
+			currentContext: "other_context",
+		},
+		{
+			name:        "does not exist",
+			context:     "foo",
+			expectedErr: config.ErrMissingConfig{What: "context with name 'foo'"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			conf := testutil.DummyConfig()
+			conf.Contexts["other_context"] = testutil.DummyContext()
+
+			err := conf.DeleteContext(tt.context, tt.switchTo)
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Len(t, conf.Contexts, 2)
+				assert.Equal(t, "dummy_context", conf.CurrentContext)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotContains(t, conf.Contexts, tt.context)
+			assert.Equal(t, tt.currentContext, conf.CurrentContext)
+			assert.NoError(t, conf.EnsureComplete())
+		})
+	}
+}
+
+func TestRenameContext(t *testing.T) {
+	conf := testutil.DummyConfig()
+	conf.Contexts["other_context"] = testutil.DummyContext()
+	context := conf.Contexts["dummy_context"]
+
+	err := conf.RenameContext("dummy_context", "other_context")
+	assert.Equal(t, config.ErrContextAlreadyExists{Name: "other_context"}, err)
+
+	err = conf.RenameContext("foo", "bar")
+	assert.Equal(t, config.ErrMissingConfig{What: "context with name 'foo'"}, err)
+
+	require.NoError(t, conf.RenameContext("dummy_context", "renamed_context"))
+	assert.NotContains(t, conf.Contexts, "dummy_context")
+	assert.Equal(t, context, conf.Contexts["renamed_context"])
+	assert.Equal(t, "renamed_context", conf.CurrentContext)
+}
+
+func TestDeleteManifest(t *testing.T) {
+	conf := testutil.DummyConfig()
+	conf.Contexts["other_context"] = testutil.DummyContext()
+	conf.Manifests["other_manifest"] = testutil.DummyManifest()
+	conf.Contexts["dummy_context"].Manifest = "other_manifest"
+
+	err := conf.DeleteManifest("dummy_manifest", false)
+	assert.Equal(t, config.ErrConfigEntryReferenced{
+		What:         "manifest with name 'dummy_manifest'",
+		ReferencedBy: []string{"context 'other_context'"},
+	}, err)
+	assert.Contains(t, conf.Manifests, "dummy_manifest")
+
+	err = conf.DeleteManifest("other_manifest", true)
+	assert.Equal(t, config.ErrUsedByCurrentContext{
+		What:    "manifest with name 'other_manifest'",
+		Context: "dummy_context",
+	}, err)
+	assert.Contains(t, conf.Manifests, "other_manifest")
+
+	require.NoError(t, conf.DeleteManifest("dummy_manifest", true))
+	assert.NotContains(t, conf.Manifests, "dummy_manifest")
+	assert.Empty(t, conf.Contexts["other_context"].Manifest)
+	assert.NoError(t, conf.EnsureComplete())
+
+	err = conf.DeleteManifest("dummy_manifest", true)
+	assert.Equal(t, config.ErrMissingConfig{What: "manifest with name 'dummy_manifest'"}, err)
+}
+
+func TestDeleteRepository(t *testing.T) {
+	conf := testutil.DummyConfig()
+	manifest := conf.Manifests["dummy_manifest"]
+	manifest.Repositories["other"] = testutil.DummyRepository()
+
+	require.NoError(t, conf.DeleteRepository("dummy_manifest", "other", false))
+	assert.NotContains(t, manifest.Repositories, "other")
+
+	err := conf.DeleteRepository("dummy_manifest", "other", false)
+	assert.Equal(t, config.ErrRepositoryNotFound{Name: "other"}, err)
+
+	// phase and inventory repositories of the current context are never deleted
+	err = conf.DeleteRepository("dummy_manifest", "primary", true)
+	assert.Equal(t, config.ErrUsedByCurrentContext{
+		What:    "repository with name 'primary'",
+		Context: "dummy_context",
+	}, err)
+	assert.Contains(t, manifest.Repositories, "primary")
+
+	conf.Contexts["dummy_context"].Manifest = "other_manifest"
+	err = conf.DeleteRepository("dummy_manifest", "primary", false)
+	assert.Equal(t, config.ErrConfigEntryReferenced{
+		What: "repository with name 'primary'",
+		ReferencedBy: []string{
+			"phase repository of manifest 'dummy_manifest'",
+			"inventory repository of manifest 'dummy_manifest'",
+		},
+	}, err)
+
+	require.NoError(t, conf.DeleteRepository("dummy_manifest", "primary", true))
+	assert.Empty(t, manifest.Repositories)
+	assert.Empty(t, manifest.PhaseRepositoryName)
+	assert.Empty(t, manifest.InventoryRepositoryName)
+}
+
+func TestDeleteManagementConfig(t *testing.T) {
+	conf := testutil.DummyConfig()
+	conf.ManagementConfiguration["unused"] = testutil.DummyManagementConfiguration()
+
+	require.NoError(t, conf.DeleteManagementConfig("unused", false))
+	assert.NotContains(t, conf.ManagementConfiguration, "unused")
+
+	err := conf.DeleteManagementConfig("dummy_management_config", false)
+	assert.Equal(t, config.ErrConfigEntryReferenced{
+		What:         "management configuration with name 'dummy_management_config'",
+		ReferencedBy: []string{"context 'dummy_context'"},
+	}, err)
+
+	require.NoError(t, conf.DeleteManagementConfig("dummy_management_config", true))
+	assert.Empty(t, conf.ManagementConfiguration)
+	assert.Empty(t, conf.Contexts["dummy_context"].ManagementConfiguration)
+
+	err = conf.DeleteManagementConfig("dummy_management_config", true)
+	assert.Equal(t, config.ErrManagementConfigurationNotFound{Name: "dummy_management_config"}, err)
+}
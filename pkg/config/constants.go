@@ -24,6 +24,9 @@ const (
 	AirshipConfigEnv                      = "AIRSHIPCONFIG"
 	AirshipConfigGroup                    = "airshipit.org"
 	AirshipConfigKind                     = "Config"
+	AirshipSystemConfigEnv                = "AIRSHIPSYSTEMCONFIG"
+	AirshipSystemConfigPath               = "/etc/airship/config"
+	AirshipTrustedProjectsEnv             = "AIRSHIPTRUSTEDPROJECTS"
 	AirshipConfigVersion                  = "v1alpha1"
 	AirshipDefaultContext                 = "default"
 	AirshipDefaultDirectoryPermission     = 0750
@@ -40,6 +43,16 @@ const (
 	HomeEnvVar = "$HOME"
 )
 
+// Environment variables overriding the values of the config
+const (
+	AirshipContextEnv             = "AIRSHIP_CONTEXT"
+	AirshipManifestEnv            = "AIRSHIP_MANIFEST"
+	AirshipTargetPathEnv          = "AIRSHIP_TARGET_PATH"
+	AirshipMetadataPathEnv        = "AIRSHIP_METADATA_PATH"
+	AirshipPhaseRepositoryEnv     = "AIRSHIP_PHASE_REPOSITORY"
+	AirshipInventoryRepositoryEnv = "AIRSHIP_INVENTORY_REPOSITORY"
+)
+
 // Default values for remote operations
 const (
 	DefaultSystemActionRetries = 30
@@ -27,6 +27,30 @@ type Context struct {
 
 	// Management configuration which will be used for all hosts in the cluster
 	ManagementConfiguration string `json:"managementConfiguration"`
+
+	// KubeconfigOverrides holds local overrides of the kubeconfig sources defined in the cluster map,
+	// keyed by the cluster name
+	// +optional
+	KubeconfigOverrides map[string]*KubeconfigOverride `json:"kubeconfigOverrides,omitempty"`
+}
+
+// KubeconfigOverride describes how to access the cluster from the machine using the context,
+// it takes precedence over the kubeconfig sources of the cluster map
+type KubeconfigOverride struct {
+	// Path to the kubeconfig file which is used instead of the cluster map sources,
+	// kubeconfig is not built if the file can't be read
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// ContextName is the name of the context in the kubeconfig file, may be omitted
+	// if the file has only one context
+	// +optional
+	ContextName string `json:"contextName,omitempty"`
+
+	// ProxyURL is the URL of the proxy used to access the cluster, it is applied
+	// to the kubeconfig regardless of its source
+	// +optional
+	ProxyURL string `json:"proxyURL,omitempty"`
 }
 
 func (c *Context) String() string {
@@ -0,0 +1,145 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/google/shlex"
+)
+
+// PasswordSource references a password which is not stored in the config file,
+// exactly one of the fields must be set. Password sources are never taken from
+// the per-project config
+type PasswordSource struct {
+	// Env is the name of environment variable holding the password
+	Env string `json:"env,omitempty"`
+	// File is the path to the file holding the password, trailing newline is ignored
+	File string `json:"file,omitempty"`
+	// Helper is the command line of git credential helper, it is invoked with "get"
+	// argument and receives repository protocol, host, path and username on stdin
+	// in git credential format, the password is read from its output. Like in git,
+	// the command is split into arguments honoring shell quoting, and a command which
+	// is not an absolute path names git helper, e.g. "store --file /etc/airship/creds" runs
+	// "git credential-store --file /etc/airship/creds get"
+	Helper string `json:"helper,omitempty"`
+}
+
+// String returns password source details in string format
+func (s *PasswordSource) String() string {
+	switch {
+	case s.Env != "":
+		return "env " + s.Env
+	case s.File != "":
+		return "file " + s.File
+	default:
+		return "helper " + s.Helper
+	}
+}
+
+// Validate checks that exactly one password source is defined
+func (s *PasswordSource) Validate() error {
+	count := 0
+	for _, val := range []string{s.Env, s.File, s.Helper} {
+		if val != "" {
+			count++
+		}
+	}
+	if count != 1 {
+		return ErrInvalidPasswordSource{}
+	}
+	return nil
+}
+
+// resolve returns the plaintext password if the source is not defined, otherwise
+// the password is read from the source
+func (s *PasswordSource) resolve(field, plaintext, url, username string) (string, error) {
+	if s == nil {
+		return plaintext, nil
+	}
+	if err := s.Validate(); err != nil {
+		return "", err
+	}
+
+	var password string
+	var err error
+	switch {
+	case s.Env != "":
+		var exists bool
+		if password, exists = os.LookupEnv(s.Env); !exists {
+			err = fmt.Errorf("environment variable is not set")
+		}
+	case s.File != "":
+		var data []byte
+		if data, err = ioutil.ReadFile(s.File); err == nil {
+			password = strings.TrimRight(string(data), "\r\n")
+		}
+	default:
+		password, err = s.runHelper(url, username)
+	}
+	if err != nil {
+		return "", ErrPasswordSource{Field: field, Source: s.String(), Err: err}
+	}
+	return password, nil
+}
+
+// runHelper requests the password from the git credential helper
+func (s *PasswordSource) runHelper(url, username string) (string, error) {
+	args, err := shlex.Split(s.Helper)
+	if err != nil {
+		return "", err
+	}
+	if len(args) == 0 {
+		return "", ErrInvalidPasswordSource{}
+	}
+	if !filepath.IsAbs(args[0]) {
+		args = append([]string{"git", "credential-" + args[0]}, args[1:]...)
+	}
+
+	input := &bytes.Buffer{}
+	if endpoint, err := transport.NewEndpoint(url); err == nil {
+		fmt.Fprintf(input, "protocol=%s\nhost=%s\npath=%s\n",
+			endpoint.Protocol, endpoint.Host, strings.TrimPrefix(endpoint.Path, "/"))
+	}
+	if username != "" {
+		fmt.Fprintf(input, "username=%s\n", username)
+	}
+	fmt.Fprintln(input)
+
+	stderr := &bytes.Buffer{}
+	cmd := exec.Command(args[0], append(args[1:], "get")...)
+	cmd.Stdin = input
+	cmd.Stderr = stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		if password := strings.TrimPrefix(scanner.Text(), "password="); password != scanner.Text() {
+			return password, nil
+		}
+	}
+	return "", fmt.Errorf("helper output does not contain password")
+}
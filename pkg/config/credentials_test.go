@@ -0,0 +1,195 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+)
+
+const credentialHelper = `#!/bin/sh
+[ "$1" = get ] || exit 1
+input=$(cat)
+case "$input" in
+  *host=opendev.org*username=deployer*) echo "username=deployer"; echo "password=helper-secret";;
+  *) echo "unexpected input: $input" >&2; exit 1;;
+esac
+`
+
+const quotedArgsHelper = `#!/bin/sh
+[ "$1" = "--label" ] && [ "$2" = "airship deployer" ] && [ "$3" = get ] || exit 1
+echo "password=quoted-secret"
+`
+
+func TestPasswordSource(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "airship-credentials")
+	require.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	passwordFile := filepath.Join(testDir, "password")
+	require.NoError(t, ioutil.WriteFile(passwordFile, []byte("file-secret\n"), 0600))
+	helper := filepath.Join(testDir, "helper")
+	require.NoError(t, ioutil.WriteFile(helper, []byte(credentialHelper), 0700))
+	quotedHelper := filepath.Join(testDir, "quoted helper")
+	require.NoError(t, ioutil.WriteFile(quotedHelper, []byte(quotedArgsHelper), 0700))
+	// git looks up git-credential-<name> helpers in PATH
+	require.NoError(t, ioutil.WriteFile(filepath.Join(testDir, "git-credential-airshiptest"),
+		[]byte(credentialHelper), 0700))
+	path := os.Getenv("PATH")
+	os.Setenv("PATH", testDir+string(os.PathListSeparator)+path)
+	defer os.Setenv("PATH", path)
+
+	os.Setenv("AIRSHIP_TEST_PASSWORD", "env-secret")
+	defer os.Unsetenv("AIRSHIP_TEST_PASSWORD")
+
+	tests := []struct {
+		name        string
+		source      *config.PasswordSource
+		expected    string
+		expectedErr string
+	}{
+		{
+			name:     "plaintext",
+			expected: "plaintext-secret",
+		},
+		{
+			name:     "env",
+			source:   &config.PasswordSource{Env: "AIRSHIP_TEST_PASSWORD"},
+			expected: "env-secret",
+		},
+		{
+			name:     "file",
+			source:   &config.PasswordSource{File: passwordFile},
+			expected: "file-secret",
+		},
+		{
+			name:     "helper",
+			source:   &config.PasswordSource{Helper: helper},
+			expected: "helper-secret",
+		},
+		{
+			name:     "helper-quoted-args",
+			source:   &config.PasswordSource{Helper: `"` + quotedHelper + `" --label 'airship deployer'`},
+			expected: "quoted-secret",
+		},
+		{
+			name:     "git-helper-name",
+			source:   &config.PasswordSource{Helper: "airshiptest"},
+			expected: "helper-secret",
+		},
+		{
+			name:        "helper-unbalanced-quotes",
+			source:      &config.PasswordSource{Helper: `"` + helper},
+			expectedErr: "unable to get httpPass from helper",
+		},
+		{
+			name:        "env-not-set",
+			source:      &config.PasswordSource{Env: "AIRSHIP_TEST_NOT_SET"},
+			expectedErr: "unable to get httpPass from env AIRSHIP_TEST_NOT_SET",
+		},
+		{
+			name:        "several-sources",
+			source:      &config.PasswordSource{Env: "AIRSHIP_TEST_PASSWORD", File: passwordFile},
+			expectedErr: config.ErrInvalidPasswordSource{}.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &config.Repository{
+				URLString: "https://opendev.org/airship/treasuremap",
+				Auth: &config.RepoAuth{
+					Type:             config.HTTPBasic,
+					Username:         "deployer",
+					HTTPPasswordFrom: tt.source,
+				},
+			}
+			if tt.source == nil {
+				repo.Auth.HTTPPassword = "plaintext-secret"
+			}
+
+			auth, err := repo.ToAuth()
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, repo.Validate())
+			assert.Equal(t, &http.BasicAuth{Username: "deployer", Password: tt.expected}, auth)
+		})
+	}
+}
+
+func TestValidatePasswordSource(t *testing.T) {
+	tests := []struct {
+		name string
+		auth *config.RepoAuth
+	}{
+		{
+			name: "plaintext-and-source",
+			auth: &config.RepoAuth{
+				Type:             config.HTTPBasic,
+				HTTPPassword:     "qwerty123",
+				HTTPPasswordFrom: &config.PasswordSource{Env: "PASSWORD"},
+			},
+		},
+		{
+			name: "empty-source",
+			auth: &config.RepoAuth{
+				Type:            config.SSHPass,
+				SSHPasswordFrom: &config.PasswordSource{},
+			},
+		},
+		{
+			name: "foreign-source",
+			auth: &config.RepoAuth{
+				Type:             config.SSHPass,
+				HTTPPasswordFrom: &config.PasswordSource{File: "/path-to-password"},
+			},
+		},
+		{
+			name: "ssh-agent-and-key",
+			auth: &config.RepoAuth{
+				Type:     config.SSHAuth,
+				SSHAgent: true,
+				KeyPath:  "/path-to-key",
+			},
+		},
+		{
+			name: "ssh-agent-with-http-basic",
+			auth: &config.RepoAuth{
+				Type:     config.HTTPBasic,
+				SSHAgent: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, tt.auth.Validate())
+		})
+	}
+}
@@ -248,6 +248,15 @@ func (e ErrMissingManifestName) Error() string {
 	return "missing manifest name"
 }
 
+// ErrMissingKubeconfigOverrideCluster is returned when kubeconfig override flags are used
+// without the cluster they apply to
+type ErrMissingKubeconfigOverrideCluster struct {
+}
+
+func (e ErrMissingKubeconfigOverrideCluster) Error() string {
+	return "--kubeconfig-override-cluster flag must be set to modify kubeconfig override"
+}
+
 // ErrMissingFlag is returned when flag is not provided
 type ErrMissingFlag struct {
 	FlagName string
@@ -286,3 +295,109 @@ type ErrWrongOutputFormat struct {
 func (e ErrWrongOutputFormat) Error() string {
 	return fmt.Sprintf("wrong output format %s, must be one of %s", e.Wrong, strings.Join(e.Possible, " "))
 }
+
+// ErrOutdatedConfig is returned when the config file written by previous airshipctl release is loaded
+type ErrOutdatedConfig struct {
+	Path    string
+	Changes []string
+}
+
+func (e ErrOutdatedConfig) Error() string {
+	return fmt.Sprintf("config file %s has outdated format: %s; run 'airshipctl config migrate %s' to upgrade it",
+		e.Path, strings.Join(e.Changes, ", "), e.Path)
+}
+
+// ErrUnsupportedConfigVersion is returned when apiVersion of the config file is unknown
+type ErrUnsupportedConfigVersion struct {
+	Path       string
+	APIVersion string
+}
+
+func (e ErrUnsupportedConfigVersion) Error() string {
+	return fmt.Sprintf("config file %s has unsupported apiVersion %s, expected %s",
+		e.Path, e.APIVersion, AirshipConfigAPIVersion)
+}
+
+// ErrConfigBackupExists is returned when the backup of the config file to be migrated already exists
+type ErrConfigBackupExists struct {
+	Path string
+}
+
+func (e ErrConfigBackupExists) Error() string {
+	return fmt.Sprintf("backup file %s already exists, move it away to migrate the config file", e.Path)
+}
+
+// ErrInvalidPasswordSource is returned when none or several sources of the password are defined
+type ErrInvalidPasswordSource struct {
+}
+
+func (e ErrInvalidPasswordSource) Error() string {
+	return "exactly one of env, file or helper must be specified as the password source"
+}
+
+// ErrPasswordSource is returned when the password can't be read from its source
+type ErrPasswordSource struct {
+	Field  string
+	Source string
+	Err    error
+}
+
+func (e ErrPasswordSource) Error() string {
+	return fmt.Sprintf("unable to get %s from %s: %v", e.Field, e.Source, e.Err)
+}
+
+// ErrConfigEntryReferenced is returned when attempted to delete an entry of the config used by other entries
+type ErrConfigEntryReferenced struct {
+	What         string
+	ReferencedBy []string
+}
+
+func (e ErrConfigEntryReferenced) Error() string {
+	return fmt.Sprintf("%s is used by %s, use --force flag to delete it anyway",
+		e.What, strings.Join(e.ReferencedBy, ", "))
+}
+
+// ErrDeleteCurrentContext is returned when attempted to delete the current context without
+// switching to another one
+type ErrDeleteCurrentContext struct {
+	Name string
+}
+
+func (e ErrDeleteCurrentContext) Error() string {
+	return fmt.Sprintf("context with name '%s' is the current context, "+
+		"use --switch-to flag to make another context current and delete it", e.Name)
+}
+
+// ErrUsedByCurrentContext is returned when attempted to delete an entry of the config used by
+// the current context
+type ErrUsedByCurrentContext struct {
+	What    string
+	Context string
+}
+
+func (e ErrUsedByCurrentContext) Error() string {
+	return fmt.Sprintf("%s is used by the current context '%s', make another context current to delete it",
+		e.What, e.Context)
+}
+
+// ErrConfigEntryOrigin is returned when attempted to delete or rename an entry of the config
+// loaded from a file other than the user config file
+type ErrConfigEntryOrigin struct {
+	What   string
+	Origin string
+	Path   string
+}
+
+func (e ErrConfigEntryOrigin) Error() string {
+	return fmt.Sprintf("%s is loaded from %s, only entries of %s can be deleted or renamed",
+		e.What, e.Origin, e.Path)
+}
+
+// ErrContextAlreadyExists is returned when attempted to rename context to the name of existing one
+type ErrContextAlreadyExists struct {
+	Name string
+}
+
+func (e ErrContextAlreadyExists) Error() string {
+	return fmt.Sprintf("context with name '%s' already exists", e.Name)
+}
@@ -0,0 +1,393 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
+	"sigs.k8s.io/yaml"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/util"
+)
+
+// entrySections are config sections whose entries are loaded as a whole from a single config file
+var entrySections = map[string]bool{
+	"contexts":                true,
+	"manifests":               true,
+	"managementConfiguration": true,
+}
+
+// layeredField is a config field loaded from a file other than the user config file, it's
+// written to the user config file only if it was changed
+type layeredField struct {
+	path []string
+	// loaded is the value of the field after all config files were merged
+	loaded interface{}
+	// user is the value of the field in the user config file if any
+	user    interface{}
+	hasUser bool
+}
+
+// envOverride is a config field overridden by environment variable, the value of the field
+// which was overridden is written to the user config file
+type envOverride struct {
+	path     []string
+	value    string
+	previous string
+}
+
+// credentialFields are the fields of repository auth which make airshipctl read or execute something
+// on the host to get the credentials, they are never taken from the project config. Kubeconfig overrides
+// of the contexts are dropped from the project config as well, since they point to local kubeconfig
+// files and proxies
+var credentialFields = []string{"sshKey", "keyPassFrom", "httpPassFrom", "sshPassFrom"}
+
+// configLayers returns paths of the config files in order of precedence from the lowest along with
+// the path of the project config if it's found and trusted, the user config file is the only one
+// which is required to exist
+func (c *Config) configLayers() ([]string, string) {
+	var layers []string
+	systemPath := os.Getenv(AirshipSystemConfigEnv)
+	if systemPath == "" {
+		systemPath = AirshipSystemConfigPath
+	}
+	if systemPath != c.loadedConfigPath {
+		layers = append(layers, systemPath)
+	}
+	layers = append(layers, c.loadedConfigPath)
+	projectPath := c.findProjectConfig()
+	if projectPath == "" || projectPath == c.loadedConfigPath {
+		return layers, ""
+	}
+	if !trustedProject(projectPath) {
+		log.Printf("Project config %s is ignored, add %s to %s environment variable to trust it",
+			projectPath, projectDir(projectPath), AirshipTrustedProjectsEnv)
+		return layers, ""
+	}
+	return append(layers, projectPath), projectPath
+}
+
+// findProjectConfig looks for .airship/config walking up from the current directory,
+// the config in the home directory is not considered a project one
+func (c *Config) findProjectConfig() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	homeConfig := filepath.Join(util.UserHomeDir(), AirshipConfigDir, AirshipConfig)
+	for {
+		path := filepath.Join(dir, AirshipConfigDir, AirshipConfig)
+		if path != homeConfig && c.fileSystem.Exists(path) {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// projectDir returns the project directory holding .airship/config
+func projectDir(projectPath string) string {
+	return filepath.Dir(filepath.Dir(projectPath))
+}
+
+// trustedProject returns true if the directory of the project config is listed in
+// AIRSHIPTRUSTEDPROJECTS environment variable
+func trustedProject(projectPath string) bool {
+	dir := projectDir(projectPath)
+	for _, trusted := range filepath.SplitList(os.Getenv(AirshipTrustedProjectsEnv)) {
+		if trusted == "" {
+			continue
+		}
+		if abs, err := filepath.Abs(trusted); err == nil && abs == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// dropCredentialFields removes credential sources from repositories of the manifests and kubeconfig
+// overrides from the contexts, the paths of the removed fields are returned
+func dropCredentialFields(fields map[string]interface{}) []string {
+	var dropped []string
+	contexts, _ := fields["contexts"].(map[string]interface{})
+	for contextName, context := range contexts {
+		ctx, _ := context.(map[string]interface{})
+		if _, exists := ctx["kubeconfigOverrides"]; exists {
+			delete(ctx, "kubeconfigOverrides")
+			dropped = append(dropped, strings.Join([]string{"contexts", contextName, "kubeconfigOverrides"}, "."))
+		}
+	}
+	manifests, _ := fields["manifests"].(map[string]interface{})
+	for manifestName, manifest := range manifests {
+		m, _ := manifest.(map[string]interface{})
+		repositories, _ := m["repositories"].(map[string]interface{})
+		for repoName, repository := range repositories {
+			r, _ := repository.(map[string]interface{})
+			auth, _ := r["auth"].(map[string]interface{})
+			for _, name := range credentialFields {
+				if _, exists := auth[name]; exists {
+					delete(auth, name)
+					dropped = append(dropped,
+						strings.Join([]string{"manifests", manifestName, "repositories", repoName, "auth", name}, "."))
+				}
+			}
+		}
+	}
+	sort.Strings(dropped)
+	return dropped
+}
+
+// loadLayers merges the config files in order of their precedence and records the origin
+// of each field
+func (c *Config) loadLayers() error {
+	c.origins = map[string]string{}
+	c.layered = nil
+	c.envOverrides = nil
+
+	var userFields map[string]interface{}
+	layers, projectPath := c.configLayers()
+	for _, path := range layers {
+		if path != c.loadedConfigPath && !c.fileSystem.Exists(path) {
+			continue
+		}
+		data, err := c.fileSystem.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		fields := map[string]interface{}{}
+		if err = yaml.Unmarshal(data, &fields); err != nil {
+			return err
+		}
+		if err = checkConfigVersion(path, fields); err != nil {
+			return err
+		}
+		if path == projectPath {
+			if dropped := dropCredentialFields(fields); len(dropped) > 0 {
+				log.Printf("Credential sources and kubeconfig overrides are never taken from project config %s, "+
+					"ignoring %s",
+					path, strings.Join(dropped, ", "))
+				if data, err = yaml.Marshal(fields); err != nil {
+					return err
+				}
+			}
+		}
+		if err = yaml.Unmarshal(data, c); err != nil {
+			return err
+		}
+		for _, fieldPath := range fieldPaths(fields) {
+			c.origins[strings.Join(fieldPath, ".")] = path
+		}
+		if path == c.loadedConfigPath {
+			userFields = fields
+		}
+	}
+
+	loaded, err := c.fields()
+	if err != nil {
+		return err
+	}
+	for _, fieldPath := range fieldPaths(loaded) {
+		if origin, exists := c.origins[strings.Join(fieldPath, ".")]; !exists || origin == c.loadedConfigPath {
+			continue
+		}
+		field := layeredField{path: fieldPath}
+		field.loaded, _ = getField(loaded, fieldPath)
+		field.user, field.hasUser = getField(userFields, fieldPath)
+		c.layered = append(c.layered, field)
+	}
+
+	c.applyEnvOverrides()
+	return nil
+}
+
+// checkEntryOrigin returns an error if the config entry is loaded from a file other than
+// the user config file, such entry can't be deleted or renamed
+func (c *Config) checkEntryOrigin(what string, path ...string) error {
+	origin, exists := c.origins[strings.Join(path, ".")]
+	if !exists || origin == c.loadedConfigPath {
+		return nil
+	}
+	return ErrConfigEntryOrigin{What: what, Origin: origin, Path: c.loadedConfigPath}
+}
+
+// applyEnvOverrides replaces current context and fields of its manifest with the values
+// of environment variables
+func (c *Config) applyEnvOverrides() {
+	override := func(env string, path []string, field *string) {
+		value := os.Getenv(env)
+		if value == "" {
+			return
+		}
+		c.envOverrides = append(c.envOverrides, envOverride{path: path, value: value, previous: *field})
+		c.origins[strings.Join(path, ".")] = fmt.Sprintf("%s environment variable", env)
+		*field = value
+	}
+
+	override(AirshipContextEnv, []string{"currentContext"}, &c.CurrentContext)
+	context, exists := c.Contexts[c.CurrentContext]
+	if !exists {
+		return
+	}
+	override(AirshipManifestEnv, []string{"contexts", c.CurrentContext, "manifest"}, &context.Manifest)
+	manifest, exists := c.Manifests[context.Manifest]
+	if !exists {
+		return
+	}
+	manifestPath := []string{"manifests", context.Manifest}
+	override(AirshipTargetPathEnv, append(manifestPath, "targetPath"), &manifest.TargetPath)
+	override(AirshipMetadataPathEnv, append(manifestPath, "metadataPath"), &manifest.MetadataPath)
+	override(AirshipPhaseRepositoryEnv, append(manifestPath, "phaseRepositoryName"), &manifest.PhaseRepositoryName)
+	override(AirshipInventoryRepositoryEnv, append(manifestPath, "inventoryRepositoryName"),
+		&manifest.InventoryRepositoryName)
+}
+
+// persistentYaml returns YAML document of the config to be written to the user config file,
+// environment variable overrides are reverted and unchanged fields loaded from other
+// config files are replaced by the user config file values
+func (c *Config) persistentYaml() ([]byte, error) {
+	if len(c.layered) == 0 && len(c.envOverrides) == 0 {
+		return c.ToYaml()
+	}
+	fields, err := c.fields()
+	if err != nil {
+		return nil, err
+	}
+	for _, override := range c.envOverrides {
+		if value, exists := getField(fields, override.path); exists && value == override.value {
+			setField(fields, override.path, override.previous)
+		}
+	}
+	for _, field := range c.layered {
+		value, _ := getField(fields, field.path)
+		if !reflect.DeepEqual(value, field.loaded) {
+			continue
+		}
+		if field.hasUser {
+			setField(fields, field.path, field.user)
+		} else {
+			deleteField(fields, field.path)
+		}
+	}
+
+	data, err := yaml.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	persistent := NewEmptyConfig()
+	if err = yaml.Unmarshal(data, persistent); err != nil {
+		return nil, err
+	}
+	return persistent.ToYaml()
+}
+
+// ToYamlWithOrigins returns YAML document of the config where fields are annotated with
+// the config file or environment variable they were loaded from
+func (c *Config) ToYamlWithOrigins() ([]byte, error) {
+	data, err := c.ToYaml()
+	if err != nil {
+		return nil, err
+	}
+	rn, err := kyaml.Parse(string(data))
+	if err != nil {
+		return nil, err
+	}
+	annotateOrigins(rn.YNode(), nil, c.origins)
+	s, err := rn.String()
+	return []byte(s), err
+}
+
+func annotateOrigins(node *kyaml.Node, path []string, origins map[string]string) {
+	if node.Kind != kyaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		fieldPath := append(append([]string{}, path...), key.Value)
+		if origin, exists := origins[strings.Join(fieldPath, ".")]; exists {
+			key.LineComment = "# " + origin
+		}
+		annotateOrigins(value, fieldPath, origins)
+	}
+}
+
+// fields returns the config as a map of fields
+func (c *Config) fields() (map[string]interface{}, error) {
+	data, err := c.ToYaml()
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	return fields, yaml.Unmarshal(data, &fields)
+}
+
+// fieldPaths returns paths of top level fields and entries of the entry sections
+func fieldPaths(fields map[string]interface{}) [][]string {
+	var paths [][]string
+	for name, value := range fields {
+		entries, isMap := value.(map[string]interface{})
+		if !entrySections[name] || !isMap {
+			paths = append(paths, []string{name})
+			continue
+		}
+		for entry := range entries {
+			paths = append(paths, []string{name, entry})
+		}
+	}
+	return paths
+}
+
+func getField(fields map[string]interface{}, path []string) (interface{}, bool) {
+	var value interface{} = fields
+	for _, name := range path {
+		m, isMap := value.(map[string]interface{})
+		if !isMap {
+			return nil, false
+		}
+		var exists bool
+		if value, exists = m[name]; !exists {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+func setField(fields map[string]interface{}, path []string, value interface{}) {
+	for _, name := range path[:len(path)-1] {
+		next, isMap := fields[name].(map[string]interface{})
+		if !isMap {
+			next = map[string]interface{}{}
+			fields[name] = next
+		}
+		fields = next
+	}
+	fields[path[len(path)-1]] = value
+}
+
+func deleteField(fields map[string]interface{}, path []string) {
+	parent, exists := getField(fields, path[:len(path)-1])
+	if m, isMap := parent.(map[string]interface{}); exists && isMap {
+		delete(m, path[len(path)-1])
+	}
+}
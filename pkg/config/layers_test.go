@@ -0,0 +1,213 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+)
+
+const (
+	systemConfig = `
+contexts:
+  system:
+    manifest: system
+    managementConfiguration: default
+manifests:
+  system:
+    targetPath: /opt/system
+    phaseRepositoryName: primary
+managementConfiguration:
+  default:
+    type: redfish
+currentContext: system
+`
+	userConfig = `
+contexts:
+  user:
+    manifest: user
+    managementConfiguration: default
+manifests:
+  user:
+    targetPath: /home/user/manifests
+    metadataPath: metadata.yaml
+    phaseRepositoryName: primary
+    inventoryRepositoryName: primary
+currentContext: user
+`
+	projectConfig = `
+contexts:
+  project:
+    manifest: user
+    managementConfiguration: default
+currentContext: project
+`
+	projectCredentialsConfig = `
+manifests:
+  project:
+    targetPath: /home/user/project
+    phaseRepositoryName: primary
+    repositories:
+      primary:
+        url: https://example.com/project.git
+        auth:
+          type: http-basic
+          username: deployer
+          httpPassFrom:
+            helper: curl https://example.com/collect
+contexts:
+  project:
+    manifest: project
+    kubeconfigOverrides:
+      target-cluster:
+        path: /tmp/collected-kubeconfig
+        proxyURL: http://proxy.example.com:3128
+currentContext: user
+`
+)
+
+func TestLayeredConfig(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "airship-layers")
+	require.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	systemPath := filepath.Join(testDir, "system-config")
+	userPath := filepath.Join(testDir, "user-config")
+	projectPath := filepath.Join(testDir, "project", config.AirshipConfigDir, config.AirshipConfig)
+	workDir := filepath.Join(testDir, "project", "site", "manifests")
+	require.NoError(t, os.MkdirAll(filepath.Dir(projectPath), 0750))
+	require.NoError(t, os.MkdirAll(workDir, 0750))
+	for path, data := range map[string]string{
+		systemPath:  systemConfig,
+		userPath:    userConfig,
+		projectPath: projectConfig,
+	} {
+		require.NoError(t, ioutil.WriteFile(path, []byte(data), 0600))
+	}
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(workDir))
+	defer func() { require.NoError(t, os.Chdir(wd)) }()
+	require.NoError(t, os.Setenv(config.AirshipSystemConfigEnv, systemPath))
+	defer os.Unsetenv(config.AirshipSystemConfigEnv)
+	require.NoError(t, os.Setenv(config.AirshipTrustedProjectsEnv, filepath.Join(testDir, "project")))
+	defer os.Unsetenv(config.AirshipTrustedProjectsEnv)
+	require.NoError(t, os.Setenv(config.AirshipTargetPathEnv, "/tmp/override"))
+	defer os.Unsetenv(config.AirshipTargetPathEnv)
+
+	cfg, err := config.CreateFactory(&userPath)()
+	require.NoError(t, err)
+
+	assert.Len(t, cfg.Contexts, 3)
+	assert.Len(t, cfg.Manifests, 2)
+	assert.Equal(t, "project", cfg.CurrentContext)
+	targetPath, err := cfg.CurrentContextTargetPath()
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp/override", targetPath)
+
+	data, err := cfg.ToYamlWithOrigins()
+	require.NoError(t, err)
+	out := string(data)
+	assert.Contains(t, out, "currentContext: project # "+projectPath+"\n")
+	assert.Contains(t, out, "  system: # "+systemPath+"\n")
+	assert.Contains(t, out, "  user: # "+userPath+"\n")
+	assert.Contains(t, out, "targetPath: /tmp/override # AIRSHIP_TARGET_PATH environment variable\n")
+
+	// entries loaded from other config files can't be deleted or renamed
+	assert.Equal(t, config.ErrConfigEntryOrigin{
+		What: "context with name 'system'", Origin: systemPath, Path: userPath,
+	}, cfg.DeleteContext("system", ""))
+	assert.Equal(t, config.ErrConfigEntryOrigin{
+		What: "context with name 'project'", Origin: projectPath, Path: userPath,
+	}, cfg.RenameContext("project", "renamed"))
+	assert.Equal(t, config.ErrConfigEntryOrigin{
+		What: "manifest with name 'system'", Origin: systemPath, Path: userPath,
+	}, cfg.DeleteManifest("system", true))
+	assert.Equal(t, config.ErrConfigEntryOrigin{
+		What: "management configuration with name 'default'", Origin: systemPath, Path: userPath,
+	}, cfg.DeleteManagementConfig("default", true))
+	assert.Len(t, cfg.Contexts, 3)
+
+	// only the user config and changes made by the command are persisted
+	cfg.AddContext("new", config.SetContextManifest("user"))
+	require.NoError(t, cfg.PersistConfig(true))
+	data, err = ioutil.ReadFile(userPath)
+	require.NoError(t, err)
+	persisted := config.NewEmptyConfig()
+	require.NoError(t, yaml.Unmarshal(data, persisted))
+
+	assert.Equal(t, "user", persisted.CurrentContext)
+	assert.Len(t, persisted.Contexts, 2)
+	assert.Contains(t, persisted.Contexts, "user")
+	assert.Contains(t, persisted.Contexts, "new")
+	assert.Len(t, persisted.Manifests, 1)
+	require.Contains(t, persisted.Manifests, "user")
+	assert.Equal(t, "/home/user/manifests", persisted.Manifests["user"].TargetPath)
+	assert.Empty(t, persisted.ManagementConfiguration)
+}
+
+func TestLayeredConfigProjectTrust(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "airship-layers")
+	require.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	userPath := filepath.Join(testDir, "user-config")
+	projectDir := filepath.Join(testDir, "project")
+	projectPath := filepath.Join(projectDir, config.AirshipConfigDir, config.AirshipConfig)
+	require.NoError(t, os.MkdirAll(filepath.Dir(projectPath), 0750))
+	require.NoError(t, ioutil.WriteFile(userPath, []byte(userConfig), 0600))
+	require.NoError(t, ioutil.WriteFile(projectPath, []byte(projectCredentialsConfig), 0600))
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(projectDir))
+	defer func() { require.NoError(t, os.Chdir(wd)) }()
+	require.NoError(t, os.Setenv(config.AirshipSystemConfigEnv, filepath.Join(testDir, "no-system-config")))
+	defer os.Unsetenv(config.AirshipSystemConfigEnv)
+
+	// project config is ignored unless the project is trusted
+	cfg, err := config.CreateFactory(&userPath)()
+	require.NoError(t, err)
+	assert.Len(t, cfg.Manifests, 1)
+	assert.NotContains(t, cfg.Manifests, "project")
+
+	require.NoError(t, os.Setenv(config.AirshipTrustedProjectsEnv, "/other/project"+
+		string(os.PathListSeparator)+projectDir))
+	defer os.Unsetenv(config.AirshipTrustedProjectsEnv)
+	cfg, err = config.CreateFactory(&userPath)()
+	require.NoError(t, err)
+	require.Contains(t, cfg.Manifests, "project")
+
+	// credential sources are never taken from the project config
+	repo := cfg.Manifests["project"].Repositories["primary"]
+	require.NotNil(t, repo)
+	require.NotNil(t, repo.Auth)
+	assert.Equal(t, "deployer", repo.Auth.Username)
+	assert.Nil(t, repo.Auth.HTTPPasswordFrom)
+
+	// neither are kubeconfig overrides
+	require.Contains(t, cfg.Contexts, "project")
+	assert.Equal(t, "project", cfg.Contexts["project"].Manifest)
+	assert.Empty(t, cfg.Contexts["project"].KubeconfigOverrides)
+}
@@ -0,0 +1,252 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"sigs.k8s.io/yaml"
+)
+
+// ConfigBackupSuffix is appended to the path of the config file to save its copy before migration
+const ConfigBackupSuffix = ".bak"
+
+// configConverter upgrades the fields of the config written by previous airshipctl releases
+type configConverter struct {
+	// description explains the change to the user
+	description string
+	// convert modifies outdated fields in place and reports if anything was changed
+	convert func(fields map[string]interface{}) bool
+}
+
+// configConverters upgrade the config to the current format. The config format has never been
+// versioned: all airshipctl releases wrote apiVersion airshipit.org/v1alpha1 or omitted it while
+// changing the fields, so the converters detect the outdated fields themselves instead of relying
+// on apiVersion. Once the format gets a new apiVersion, converters from the previous one go here
+var configConverters = []configConverter{
+	{
+		description: "management configuration of clusters is moved to contexts",
+		convert:     convertClusters,
+	},
+	{
+		description: "contextKubeconf of contexts is removed",
+		convert: func(fields map[string]interface{}) bool {
+			return deleteEntriesField(fields, "contexts", "contextKubeconf")
+		},
+	},
+	{
+		description: "primaryRepositoryName of manifests is renamed to phaseRepositoryName",
+		convert:     convertPrimaryRepositoryName,
+	},
+	{
+		description: "subPath of manifests is removed, metadataPath is used instead",
+		convert: func(fields map[string]interface{}) bool {
+			return deleteEntriesField(fields, "manifests", "subPath")
+		},
+	},
+	{
+		description: "authInfos, bootstrapInfo, kubeconfig and modules sections are removed",
+		convert: func(fields map[string]interface{}) bool {
+			converted := false
+			for _, name := range []string{"authInfos", "bootstrapInfo", "kubeconfig", "modules"} {
+				if _, exists := fields[name]; exists {
+					delete(fields, name)
+					converted = true
+				}
+			}
+			return converted
+		},
+	},
+}
+
+// convertConfig upgrades the config fields and returns the descriptions of the changes
+func convertConfig(path string, fields map[string]interface{}) ([]string, error) {
+	apiVersion, _ := fields["apiVersion"].(string)
+	if apiVersion == "" {
+		apiVersion = AirshipConfigAPIVersion
+	}
+	if apiVersion != AirshipConfigAPIVersion {
+		return nil, ErrUnsupportedConfigVersion{Path: path, APIVersion: apiVersion}
+	}
+	var changes []string
+	for _, converter := range configConverters {
+		if converter.convert(fields) {
+			changes = append(changes, converter.description)
+		}
+	}
+	return changes, nil
+}
+
+// checkConfigVersion returns an error if the config fields have to be migrated
+func checkConfigVersion(path string, fields map[string]interface{}) error {
+	data, err := yaml.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	fieldsCopy := map[string]interface{}{}
+	if err = yaml.Unmarshal(data, &fieldsCopy); err != nil {
+		return err
+	}
+	changes, err := convertConfig(path, fieldsCopy)
+	if err != nil {
+		return err
+	}
+	if len(changes) > 0 {
+		return ErrOutdatedConfig{Path: path, Changes: changes}
+	}
+	return nil
+}
+
+// convertClusters sets management configuration of the contexts from the clusters
+// they refer to by contextKubeconf and removes clusters section
+func convertClusters(fields map[string]interface{}) bool {
+	clusters, exists := fields["clusters"].(map[string]interface{})
+	if !exists {
+		return false
+	}
+	mgmtConfigs := map[string]interface{}{}
+	for _, cluster := range clusters {
+		clusterTypes, _ := getField(toFields(cluster), []string{"clusterType"})
+		for _, clusterType := range toFields(clusterTypes) {
+			kubeconf, _ := getField(toFields(clusterType), []string{"clusterKubeconf"})
+			mgmtConfig, _ := getField(toFields(clusterType), []string{"managementConfiguration"})
+			if name, ok := kubeconf.(string); ok && mgmtConfig != nil {
+				mgmtConfigs[name] = mgmtConfig
+			}
+		}
+	}
+	contexts, _ := fields["contexts"].(map[string]interface{})
+	for _, context := range contexts {
+		contextFields := toFields(context)
+		if contextFields == nil || contextFields["managementConfiguration"] != nil {
+			continue
+		}
+		if kubeconf, ok := contextFields["contextKubeconf"].(string); ok && mgmtConfigs[kubeconf] != nil {
+			contextFields["managementConfiguration"] = mgmtConfigs[kubeconf]
+		}
+	}
+	delete(fields, "clusters")
+	return true
+}
+
+// convertPrimaryRepositoryName renames primaryRepositoryName of the manifests
+func convertPrimaryRepositoryName(fields map[string]interface{}) bool {
+	converted := false
+	manifests, _ := fields["manifests"].(map[string]interface{})
+	for _, manifest := range manifests {
+		manifestFields := toFields(manifest)
+		name, exists := manifestFields["primaryRepositoryName"]
+		if !exists {
+			continue
+		}
+		if _, exists = manifestFields["phaseRepositoryName"]; !exists {
+			manifestFields["phaseRepositoryName"] = name
+		}
+		delete(manifestFields, "primaryRepositoryName")
+		converted = true
+	}
+	return converted
+}
+
+// deleteEntriesField removes the field from all entries of the section
+func deleteEntriesField(fields map[string]interface{}, section, name string) bool {
+	converted := false
+	entries, _ := fields[section].(map[string]interface{})
+	for _, entry := range entries {
+		entryFields := toFields(entry)
+		if _, exists := entryFields[name]; exists {
+			delete(entryFields, name)
+			converted = true
+		}
+	}
+	return converted
+}
+
+func toFields(value interface{}) map[string]interface{} {
+	fields, _ := value.(map[string]interface{})
+	return fields
+}
+
+// RunMigrate upgrades the config file to the current format in place, original
+// file is saved next to it with ConfigBackupSuffix before the config file is modified,
+// an existing backup is never overwritten
+func RunMigrate(airshipConfigPath string, w io.Writer) error {
+	c := NewEmptyConfig()
+	c.initConfigPath(airshipConfigPath)
+	path := c.loadedConfigPath
+
+	data, err := c.fileSystem.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	fields := map[string]interface{}{}
+	if err = yaml.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	changes, err := convertConfig(path, fields)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		_, err = fmt.Fprintf(w, "Config file %s is up to date.\n", path)
+		return err
+	}
+	if _, exists := fields["kind"]; !exists {
+		fields["kind"] = AirshipConfigKind
+	}
+	if _, exists := fields["apiVersion"]; !exists {
+		fields["apiVersion"] = AirshipConfigAPIVersion
+	}
+	migrated, err := yaml.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	backupPath := path + ConfigBackupSuffix
+	if c.fileSystem.Exists(backupPath) {
+		return ErrConfigBackupExists{Path: backupPath}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	// the backup goes first so the original content is never lost
+	for _, file := range []struct {
+		path    string
+		content []byte
+	}{
+		{path: backupPath, content: data},
+		{path: path, content: migrated},
+	} {
+		if err = c.fileSystem.WriteFile(file.path, file.content); err != nil {
+			return err
+		}
+		if err = c.fileSystem.Chmod(file.path, info.Mode()); err != nil {
+			return err
+		}
+	}
+
+	sort.Strings(changes)
+	for _, change := range changes {
+		if _, err = fmt.Fprintf(w, "- %s\n", change); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "Config file %s is migrated, original file is saved to %s.\n", path, backupPath)
+	return err
+}
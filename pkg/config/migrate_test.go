@@ -0,0 +1,121 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+)
+
+const legacyConfig = `apiVersion: airshipit.org/v1alpha1
+kind: Config
+bootstrapInfo:
+  default:
+    builder:
+      networkConfigFileName: network-config
+clusters:
+  dummycluster:
+    clusterType:
+      ephemeral:
+        clusterKubeconf: dummycluster_ephemeral
+        managementConfiguration: dummy_management_config
+contexts:
+  dummy_context:
+    contextKubeconf: dummycluster_ephemeral
+    manifest: dummy_manifest
+currentContext: dummy_context
+managementConfiguration:
+  dummy_management_config:
+    type: redfish
+manifests:
+  dummy_manifest:
+    primaryRepositoryName: primary
+    subPath: manifests/site/test-site
+    targetPath: /var/tmp/
+`
+
+func TestMigrate(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "airship-migrate")
+	require.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	configPath := filepath.Join(testDir, "config")
+	require.NoError(t, ioutil.WriteFile(configPath, []byte(legacyConfig), 0600))
+
+	t.Run("load-outdated", func(t *testing.T) {
+		conf := config.NewEmptyConfig()
+		conf.SetLoadedConfigPath(configPath)
+		err := conf.LoadConfig()
+		require.Error(t, err)
+		_, ok := err.(config.ErrOutdatedConfig)
+		assert.True(t, ok, "expected ErrOutdatedConfig, got %v", err)
+		assert.Contains(t, err.Error(), "airshipctl config migrate "+configPath)
+	})
+
+	t.Run("migrate", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, config.RunMigrate(configPath, out))
+		assert.Contains(t, out.String(), "original file is saved to "+configPath+config.ConfigBackupSuffix)
+
+		backup, err := ioutil.ReadFile(configPath + config.ConfigBackupSuffix)
+		require.NoError(t, err)
+		assert.Equal(t, legacyConfig, string(backup))
+
+		conf := config.NewEmptyConfig()
+		conf.SetLoadedConfigPath(configPath)
+		require.NoError(t, conf.LoadConfig())
+		context, err := conf.GetContext("dummy_context")
+		require.NoError(t, err)
+		assert.Equal(t, "dummy_management_config", context.ManagementConfiguration)
+		manifest, err := conf.GetManifest("dummy_manifest")
+		require.NoError(t, err)
+		assert.Equal(t, "primary", manifest.PhaseRepositoryName)
+	})
+
+	t.Run("up-to-date", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, config.RunMigrate(configPath, out))
+		assert.Equal(t, "Config file "+configPath+" is up to date.\n", out.String())
+	})
+
+	t.Run("backup-exists", func(t *testing.T) {
+		path := filepath.Join(testDir, "with-backup")
+		require.NoError(t, ioutil.WriteFile(path, []byte(legacyConfig), 0600))
+		require.NoError(t, ioutil.WriteFile(path+config.ConfigBackupSuffix, []byte("backup"), 0600))
+		err := config.RunMigrate(path, &bytes.Buffer{})
+		assert.Equal(t, config.ErrConfigBackupExists{Path: path + config.ConfigBackupSuffix}, err)
+
+		for file, expected := range map[string]string{path: legacyConfig, path + config.ConfigBackupSuffix: "backup"} {
+			content, err := ioutil.ReadFile(file)
+			require.NoError(t, err)
+			assert.Equal(t, expected, string(content))
+		}
+	})
+
+	t.Run("unsupported-version", func(t *testing.T) {
+		path := filepath.Join(testDir, "unsupported")
+		require.NoError(t, ioutil.WriteFile(path, []byte("apiVersion: airshipit.org/v2\n"), 0600))
+		err := config.RunMigrate(path, &bytes.Buffer{})
+		assert.Equal(t, config.ErrUnsupportedConfigVersion{Path: path, APIVersion: "airshipit.org/v2"}, err)
+	})
+}
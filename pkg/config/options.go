@@ -26,12 +26,14 @@ import (
 
 // ContextOptions holds all configurable options for context
 type ContextOptions struct {
-	Name                    string
-	CurrentContext          bool
-	Manifest                string
-	Current                 bool
-	ManagementConfiguration string
-	Format                  string
+	Name                      string
+	CurrentContext            bool
+	Manifest                  string
+	Current                   bool
+	ManagementConfiguration   string
+	Format                    string
+	KubeconfigOverrideCluster string
+	KubeconfigOverride        KubeconfigOverride
 }
 
 // ManifestOptions holds all configurable options for manifest configuration
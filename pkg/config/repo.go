@@ -70,6 +70,15 @@ type RepoAuth struct {
 	SSHPassword string `json:"sshPass,omitempty"`
 	// Username to authenticate against git remote (used with any type)
 	Username string `json:"username,omitempty"`
+	// KeyPasswordFrom references KeyPassword stored outside of the config file (used with ssh-key auth type)
+	KeyPasswordFrom *PasswordSource `json:"keyPassFrom,omitempty"`
+	// HTTPPasswordFrom references HTTPPassword stored outside of the config file (used with http-basic auth type)
+	HTTPPasswordFrom *PasswordSource `json:"httpPassFrom,omitempty"`
+	// SSHPasswordFrom references SSHPassword stored outside of the config file (used with ssh-pass auth type)
+	SSHPasswordFrom *PasswordSource `json:"sshPassFrom,omitempty"`
+	// SSHAgent enables authentication with the keys held by ssh-agent instead of
+	// the private key on disk (used with ssh-key auth type)
+	SSHAgent bool `json:"sshAgent,omitempty"`
 }
 
 // RepoCheckout container holds information how to checkout repository
@@ -160,18 +169,44 @@ func (auth *RepoAuth) Validate() error {
 		return ErrAuthTypeNotSupported{}
 	}
 
+	for _, source := range []*PasswordSource{auth.KeyPasswordFrom, auth.HTTPPasswordFrom, auth.SSHPasswordFrom} {
+		if source == nil {
+			continue
+		}
+		if err := source.Validate(); err != nil {
+			return err
+		}
+	}
+
 	switch auth.Type {
 	case SSHAuth:
-		if auth.HTTPPassword != "" || auth.SSHPassword != "" {
-			return NewErrIncompatibleAuthOptions([]string{"http-pass, ssh-pass"}, auth.Type)
+		if auth.HTTPPassword != "" || auth.SSHPassword != "" ||
+			auth.HTTPPasswordFrom != nil || auth.SSHPasswordFrom != nil {
+			return NewErrIncompatibleAuthOptions([]string{"httpPass, httpPassFrom, sshPass, sshPassFrom"}, auth.Type)
+		}
+		if auth.SSHAgent && (auth.KeyPath != "" || auth.KeyPassword != "" || auth.KeyPasswordFrom != nil) {
+			return NewErrIncompatibleAuthOptions([]string{"sshAgent, sshKey, keyPass, keyPassFrom"}, auth.Type)
+		}
+		if auth.KeyPassword != "" && auth.KeyPasswordFrom != nil {
+			return NewErrIncompatibleAuthOptions([]string{"keyPass, keyPassFrom"}, auth.Type)
 		}
 	case HTTPBasic:
-		if auth.SSHPassword != "" || auth.KeyPath != "" || auth.KeyPassword != "" {
-			return NewErrIncompatibleAuthOptions([]string{"ssh-pass, ssh-key, key-pass"}, auth.Type)
+		if auth.SSHPassword != "" || auth.KeyPath != "" || auth.KeyPassword != "" ||
+			auth.SSHPasswordFrom != nil || auth.KeyPasswordFrom != nil || auth.SSHAgent {
+			return NewErrIncompatibleAuthOptions(
+				[]string{"sshPass, sshPassFrom, sshKey, keyPass, keyPassFrom, sshAgent"}, auth.Type)
+		}
+		if auth.HTTPPassword != "" && auth.HTTPPasswordFrom != nil {
+			return NewErrIncompatibleAuthOptions([]string{"httpPass, httpPassFrom"}, auth.Type)
 		}
 	case SSHPass:
-		if auth.KeyPath != "" || auth.KeyPassword != "" || auth.HTTPPassword != "" {
-			return NewErrIncompatibleAuthOptions([]string{"ssh-key, key-pass, http-pass"}, auth.Type)
+		if auth.KeyPath != "" || auth.KeyPassword != "" || auth.HTTPPassword != "" ||
+			auth.KeyPasswordFrom != nil || auth.HTTPPasswordFrom != nil || auth.SSHAgent {
+			return NewErrIncompatibleAuthOptions(
+				[]string{"sshKey, keyPass, keyPassFrom, httpPass, httpPassFrom, sshAgent"}, auth.Type)
+		}
+		if auth.SSHPassword != "" && auth.SSHPasswordFrom != nil {
+			return NewErrIncompatibleAuthOptions([]string{"sshPass, sshPassFrom"}, auth.Type)
 		}
 	case NoAuth:
 		return nil
@@ -225,18 +260,37 @@ func (repo *Repository) Validate() error {
 }
 
 // ToAuth returns an implementation of transport.AuthMethod for
-// the given auth type to establish an ssh connection
+// the given auth type to establish an ssh connection. Passwords referenced
+// by the config are resolved at this point
 func (repo *Repository) ToAuth() (transport.AuthMethod, error) {
 	if repo.Auth == nil || repo.Auth.Type == NoAuth {
 		return nil, nil
 	}
 	switch repo.Auth.Type {
 	case SSHAuth:
-		return ssh.NewPublicKeysFromFile(repo.Auth.Username, repo.Auth.KeyPath, repo.Auth.KeyPassword)
+		if repo.Auth.SSHAgent {
+			return ssh.NewSSHAgentAuth(repo.Auth.Username)
+		}
+		password, err := repo.Auth.KeyPasswordFrom.resolve("keyPass", repo.Auth.KeyPassword, repo.URLString,
+			repo.Auth.Username)
+		if err != nil {
+			return nil, err
+		}
+		return ssh.NewPublicKeysFromFile(repo.Auth.Username, repo.Auth.KeyPath, password)
 	case SSHPass:
-		return &ssh.Password{User: repo.Auth.Username, Password: repo.Auth.HTTPPassword}, nil
+		password, err := repo.Auth.SSHPasswordFrom.resolve("sshPass", repo.Auth.SSHPassword, repo.URLString,
+			repo.Auth.Username)
+		if err != nil {
+			return nil, err
+		}
+		return &ssh.Password{User: repo.Auth.Username, Password: password}, nil
 	case HTTPBasic:
-		return &http.BasicAuth{Username: repo.Auth.Username, Password: repo.Auth.HTTPPassword}, nil
+		password, err := repo.Auth.HTTPPasswordFrom.resolve("httpPass", repo.Auth.HTTPPassword, repo.URLString,
+			repo.Auth.Username)
+		if err != nil {
+			return nil, err
+		}
+		return &http.BasicAuth{Username: repo.Auth.Username, Password: password}, nil
 	default:
 		return nil, errors.ErrNotImplemented{What: fmt.Sprintf("authtype %s", repo.Auth.Type)}
 	}
@@ -33,6 +33,7 @@ import (
 	"sigs.k8s.io/yaml"
 
 	"opendev.org/airship/airshipctl/pkg/api/v1alpha1"
+	"opendev.org/airship/airshipctl/pkg/document"
 	"opendev.org/airship/airshipctl/pkg/log"
 	"opendev.org/airship/airshipctl/pkg/util"
 )
@@ -119,6 +120,11 @@ func (c *V1Alpha1) runAirship() error {
 		c.conf.Spec.Airship.ContainerRuntime = DriverDocker
 	}
 
+	// image must be verified before it's pulled and started
+	if err := VerifyImage(c.conf.Spec.Image, c.conf.Spec.Airship.ImageVerification); err != nil {
+		return err
+	}
+
 	var cont Container
 	if c.containerFunc == nil {
 		c.containerFunc = NewContainer
@@ -219,6 +225,14 @@ func (c *V1Alpha1) runAirship() error {
 }
 
 func (c *V1Alpha1) runKRM() error {
+	function, err := kyaml.Parse(c.conf.Config)
+	if err != nil {
+		return err
+	}
+	if factory, ok := document.BuiltinFunction(c.conf.Spec.Image, function.GetAnnotations()); ok {
+		return c.runBuiltin(factory, function)
+	}
+
 	mounts := convertKRMMount(c.conf.Spec.StorageMounts)
 	fns := &runfn.RunFns{
 		Network:               c.conf.Spec.HostNetwork,
@@ -229,10 +243,6 @@ func (c *V1Alpha1) runKRM() error {
 		StorageMounts:         mounts,
 		ContinueOnEmptyResult: true,
 	}
-	function, err := kyaml.Parse(c.conf.Config)
-	if err != nil {
-		return err
-	}
 	// Transform GenericContainer.Spec to annotation,
 	// because we need to specify runFns config in annotation
 	spec, err := yaml.Marshal(runtimeutil.FunctionSpec{
@@ -257,6 +267,30 @@ func (c *V1Alpha1) runKRM() error {
 	return fns.Execute()
 }
 
+// runBuiltin executes built-in KRM function in-process instead of running its container image
+func (c *V1Alpha1) runBuiltin(factory document.BuiltinFunctionFactory, function *kyaml.RNode) error {
+	cfg, err := function.Map()
+	if err != nil {
+		return err
+	}
+	filter, err := factory(cfg)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("executing built-in function %s in-process", c.conf.Spec.Image)
+	buf := &bytes.Buffer{}
+	err = kio.Pipeline{
+		Inputs:  []kio.Reader{&kio.ByteReader{Reader: c.input}},
+		Filters: []kio.Filter{filter},
+		Outputs: []kio.Writer{&kio.ByteWriter{Writer: buf}},
+	}.Execute()
+	if err != nil {
+		return err
+	}
+	return writeSink(c.resultsDir, buf, c.output)
+}
+
 func writeLogs(cont Container) error {
 	stderr, err := cont.GetContainerLogs(GetLogOptions{
 		Stderr: true,
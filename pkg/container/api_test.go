@@ -27,6 +27,8 @@ import (
 	"github.com/docker/docker/api/types/container"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
 
 	"opendev.org/airship/airshipctl/pkg/api/v1alpha1"
 	aircontainer "opendev.org/airship/airshipctl/pkg/container"
@@ -281,6 +283,38 @@ func TestGenericContainer(t *testing.T) {
 	}
 }
 
+func TestGenericContainerBuiltin(t *testing.T) {
+	document.RegisterBuiltinFunction("localhost/label-builtin", func(cfg map[string]interface{}) (kio.Filter, error) {
+		data, ok := cfg["data"].(map[string]interface{})
+		require.True(t, ok)
+		label, ok := data["label"].(string)
+		require.True(t, ok)
+		return kio.FilterFunc(func(items []*kyaml.RNode) ([]*kyaml.RNode, error) {
+			for _, item := range items {
+				if err := item.PipeE(kyaml.SetLabel(label, "true")); err != nil {
+					return nil, err
+				}
+			}
+			return items, nil
+		}), nil
+	})
+
+	containerAPI := &v1alpha1.GenericContainer{
+		Spec: v1alpha1.GenericContainerSpec{
+			Type:  v1alpha1.GenericContainerTypeKrm,
+			Image: "localhost/label-builtin:latest",
+		},
+		Config: `kind: ConfigMap
+data:
+  label: builtin`,
+	}
+	output := &bytes.Buffer{}
+	input := bundlePathToInput(t, "testdata/single")
+	client := aircontainer.NewV1Alpha1("", input, output, containerAPI, "", aircontainer.NewContainer)
+	require.NoError(t, client.Run())
+	assert.Contains(t, output.String(), "builtin: \"true\"")
+}
+
 // Dummy test to keep up with coverage.
 func TestNewClientV1alpha1(t *testing.T) {
 	client := aircontainer.NewClientV1Alpha1("", nil, nil, v1alpha1.DefaultGenericContainer(), "")
@@ -52,3 +52,41 @@ type ErrNoContainerDriver struct {
 func (e ErrNoContainerDriver) Error() string {
 	return fmt.Sprintf("container runtime is not defined in airshipctl config")
 }
+
+// ErrImageNotPinned returned if image is required to be referenced by digest but it's not
+type ErrImageNotPinned struct {
+	Image string
+}
+
+func (e ErrImageNotPinned) Error() string {
+	return fmt.Sprintf("image '%s' is not pinned by digest", e.Image)
+}
+
+// ErrImageDigestNotFound returned if tagged image can't be resolved to digest using versions catalogue
+type ErrImageDigestNotFound struct {
+	Image string
+}
+
+func (e ErrImageDigestNotFound) Error() string {
+	return fmt.Sprintf("digest for image '%s' is not found in versions catalogue", e.Image)
+}
+
+// ErrImageSignatureNotFound returned if image verification policy defines signatures,
+// but none of them belongs to the image repository
+type ErrImageSignatureNotFound struct {
+	Image string
+}
+
+func (e ErrImageSignatureNotFound) Error() string {
+	return fmt.Sprintf("signature of image '%s' is not found in image verification policy", e.Image)
+}
+
+// ErrImageSignatureVerification returned if detached image signature is not valid
+type ErrImageSignatureVerification struct {
+	Image  string
+	Reason string
+}
+
+func (e ErrImageSignatureVerification) Error() string {
+	return fmt.Sprintf("signature verification of image '%s' failed: %s", e.Image, e.Reason)
+}
@@ -0,0 +1,221 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package container
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"opendev.org/airship/airshipctl/pkg/api/v1alpha1"
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+const (
+	// DefaultDigestAlgorithm is used for catalogue digests which are specified without algorithm prefix
+	DefaultDigestAlgorithm = "sha256"
+	// CosignSignatureType is the type of cosign simple signing payload
+	CosignSignatureType = "cosign container image signature"
+
+	digestSeparator = "@"
+)
+
+var digestRegexp = regexp.MustCompile(`^[a-z0-9]+(?:[.+_-][a-z0-9]+)*:[a-fA-F0-9]{32,}$`)
+
+// cosignPayload is a cosign simple signing payload, only the fields required for verification are defined
+type cosignPayload struct {
+	Critical struct {
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// SplitImage splits image url into the repository, tag and digest parts. Tag and digest
+// are returned empty if the url doesn't contain them
+func SplitImage(url string) (repo, tag, digest string) {
+	repo = url
+	if i := strings.Index(repo, digestSeparator); i >= 0 {
+		repo, digest = repo[:i], repo[i+1:]
+	}
+	// tag separator must be placed after the last path element to not confuse it with registry port
+	if i := strings.LastIndex(repo, ":"); i > strings.LastIndex(repo, "/") {
+		repo, tag = repo[:i], repo[i+1:]
+	}
+	return repo, tag, digest
+}
+
+// IsDigestReference returns true if image url is pinned by digest
+func IsDigestReference(url string) bool {
+	_, _, digest := SplitImage(url)
+	return digestRegexp.MatchString(digest)
+}
+
+// ResolveImageDigest converts tagged image url into the one pinned by digest using
+// image_components and image_repositories sections of the versions catalogue. Url is
+// returned unchanged if it's already pinned by digest
+func ResolveImageDigest(url string, catalogue *v1alpha1.VersionsCatalogue) (string, error) {
+	if IsDigestReference(url) {
+		return url, nil
+	}
+	repo, tag, _ := SplitImage(url)
+	if tag == "" {
+		tag = "latest"
+	}
+
+	if catalogue != nil {
+		specs := []v1alpha1.ImageRepositorySpec{}
+		for _, component := range catalogue.Spec.ImageComponents {
+			for _, spec := range component {
+				specs = append(specs, spec)
+			}
+		}
+		for _, spec := range catalogue.Spec.ImageRepositories {
+			specs = append(specs, spec)
+		}
+
+		for _, spec := range specs {
+			if spec.Digest == "" || catalogueRepository(spec) != repo {
+				continue
+			}
+			if spec.Tag != "" && spec.Tag != tag {
+				continue
+			}
+			digest := spec.Digest
+			if !strings.Contains(digest, ":") {
+				digest = DefaultDigestAlgorithm + ":" + digest
+			}
+			resolved := repo + digestSeparator + digest
+			log.Debugf("Image '%s' is resolved to '%s' using versions catalogue", url, resolved)
+			return resolved, nil
+		}
+	}
+	return "", ErrImageDigestNotFound{Image: url}
+}
+
+func catalogueRepository(spec v1alpha1.ImageRepositorySpec) string {
+	if spec.Name == "" {
+		return spec.Repository
+	}
+	return strings.TrimSuffix(spec.Repository, "/") + "/" + spec.Name
+}
+
+// VerifyImage checks that image url satisfies image verification policy, i.e. it's pinned
+// by digest if it's required and the detached signature of its repository is valid
+func VerifyImage(url string, policy *v1alpha1.ImageVerification) error {
+	if policy == nil {
+		return nil
+	}
+	if policy.RequireDigest && !IsDigestReference(url) {
+		return ErrImageNotPinned{Image: url}
+	}
+	if len(policy.Signatures) == 0 {
+		return nil
+	}
+	repo, _, _ := SplitImage(url)
+	for i := range policy.Signatures {
+		if policy.Signatures[i].Image == repo {
+			return VerifyImageSignature(url, &policy.Signatures[i])
+		}
+	}
+	return ErrImageSignatureNotFound{Image: url}
+}
+
+// VerifyImageSignature verifies cosign-compatible detached signature of the image offline.
+// Image must be pinned by digest, the digest and the repository of the image must match
+// the ones defined in the signed payload
+func VerifyImageSignature(url string, sig *v1alpha1.ImageSignature) error {
+	repo, _, digest := SplitImage(url)
+	if !digestRegexp.MatchString(digest) {
+		return ErrImageNotPinned{Image: url}
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(sig.Payload)
+	if err != nil {
+		return ErrImageSignatureVerification{Image: url, Reason: fmt.Sprintf("unable to decode payload: %v", err)}
+	}
+	signature, err := base64.StdEncoding.DecodeString(sig.Signature)
+	if err != nil {
+		return ErrImageSignatureVerification{Image: url, Reason: fmt.Sprintf("unable to decode signature: %v", err)}
+	}
+	pubKey, err := parsePublicKey(sig.PublicKey)
+	if err != nil {
+		return ErrImageSignatureVerification{Image: url, Reason: err.Error()}
+	}
+
+	if err = verifySignature(pubKey, payload, signature); err != nil {
+		return ErrImageSignatureVerification{Image: url, Reason: err.Error()}
+	}
+
+	p := &cosignPayload{}
+	if err = json.Unmarshal(payload, p); err != nil {
+		return ErrImageSignatureVerification{Image: url, Reason: fmt.Sprintf("unable to parse payload: %v", err)}
+	}
+	switch {
+	case p.Critical.Type != CosignSignatureType:
+		return ErrImageSignatureVerification{Image: url,
+			Reason: fmt.Sprintf("unknown payload type '%s'", p.Critical.Type)}
+	case p.Critical.Image.DockerManifestDigest != digest:
+		return ErrImageSignatureVerification{Image: url,
+			Reason: fmt.Sprintf("signed digest '%s' doesn't match", p.Critical.Image.DockerManifestDigest)}
+	case p.Critical.Identity.DockerReference != repo:
+		return ErrImageSignatureVerification{Image: url,
+			Reason: fmt.Sprintf("signed reference '%s' doesn't match", p.Critical.Identity.DockerReference)}
+	}
+	log.Debugf("Signature of image '%s' is verified", url)
+	return nil
+}
+
+func parsePublicKey(data string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, fmt.Errorf("unable to decode PEM public key")
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+func verifySignature(pubKey crypto.PublicKey, payload, signature []byte) error {
+	hash := sha256.Sum256(payload)
+	switch key := pubKey.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, hash[:], signature) {
+			return fmt.Errorf("invalid signature")
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], signature); err != nil {
+			return fmt.Errorf("invalid signature: %v", err)
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, payload, signature) {
+			return fmt.Errorf("invalid signature")
+		}
+	default:
+		return fmt.Errorf("unsupported public key type %T", pubKey)
+	}
+	return nil
+}
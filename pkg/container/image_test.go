@@ -0,0 +1,238 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package container_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/api/v1alpha1"
+	"opendev.org/airship/airshipctl/pkg/container"
+)
+
+const (
+	testDigest = "sha256:6c3c624b58dbbcd3c0dd82b4c53f04194d1247c6eebdaab7c610cf7d66709b3b"
+	testRepo   = "localhost:5000/airshipit/toolbox"
+)
+
+func TestSplitImage(t *testing.T) {
+	tests := []struct {
+		url    string
+		repo   string
+		tag    string
+		digest string
+	}{
+		{url: "toolbox", repo: "toolbox"},
+		{url: "quay.io/airshipit/toolbox:latest", repo: "quay.io/airshipit/toolbox", tag: "latest"},
+		{url: testRepo, repo: testRepo},
+		{url: testRepo + ":v1", repo: testRepo, tag: "v1"},
+		{url: testRepo + "@" + testDigest, repo: testRepo, digest: testDigest},
+		{url: testRepo + ":v1@" + testDigest, repo: testRepo, tag: "v1", digest: testDigest},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.url, func(t *testing.T) {
+			repo, tag, digest := container.SplitImage(tt.url)
+			assert.Equal(t, tt.repo, repo)
+			assert.Equal(t, tt.tag, tag)
+			assert.Equal(t, tt.digest, digest)
+		})
+	}
+}
+
+func TestResolveImageDigest(t *testing.T) {
+	catalogue := &v1alpha1.VersionsCatalogue{
+		Spec: v1alpha1.VersionsCatalogueSpec{
+			ImageComponents: v1alpha1.ImageComponentSpec{
+				"toolbox": v1alpha1.AirshipctlFunctionImageComponentMap{
+					"toolbox": v1alpha1.ImageRepositorySpec{
+						Repository: "localhost:5000",
+						Name:       "airshipit/toolbox",
+						Tag:        "v1",
+						Digest:     testDigest,
+					},
+				},
+			},
+			ImageRepositories: v1alpha1.AirshipctlFunctionImageComponentMap{
+				"isogen": v1alpha1.ImageRepositorySpec{
+					Repository: "quay.io/airshipit/isogen",
+					Digest:     "6c3c624b58dbbcd3c0dd82b4c53f04194d1247c6eebdaab7c610cf7d66709b3b",
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		url         string
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:     "already pinned",
+			url:      testRepo + "@" + testDigest,
+			expected: testRepo + "@" + testDigest,
+		},
+		{
+			name:     "image component",
+			url:      testRepo + ":v1",
+			expected: testRepo + "@" + testDigest,
+		},
+		{
+			name:     "image repository without tag",
+			url:      "quay.io/airshipit/isogen:latest",
+			expected: "quay.io/airshipit/isogen@" + testDigest,
+		},
+		{
+			name:        "tag mismatch",
+			url:         testRepo + ":v2",
+			expectedErr: container.ErrImageDigestNotFound{Image: testRepo + ":v2"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := container.ResolveImageDigest(tt.url, catalogue)
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func signImage(t *testing.T, repo, digest string) v1alpha1.ImageSignature {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	pub, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	payload := fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"%s"},`+
+		`"image":{"docker-manifest-digest":"%s"},"type":"%s"},"optional":null}`,
+		repo, digest, container.CosignSignatureType)
+	hash := sha256.Sum256([]byte(payload))
+	sig, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+	require.NoError(t, err)
+
+	return v1alpha1.ImageSignature{
+		Image:     repo,
+		PublicKey: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub})),
+		Payload:   base64.StdEncoding.EncodeToString([]byte(payload)),
+		Signature: base64.StdEncoding.EncodeToString(sig),
+	}
+}
+
+func TestVerifyImage(t *testing.T) {
+	pinned := testRepo + "@" + testDigest
+	tests := []struct {
+		name        string
+		url         string
+		policy      *v1alpha1.ImageVerification
+		expectedErr string
+	}{
+		{
+			name: "no policy",
+			url:  testRepo + ":v1",
+		},
+		{
+			name:        "digest required",
+			url:         testRepo + ":v1",
+			policy:      &v1alpha1.ImageVerification{RequireDigest: true},
+			expectedErr: "is not pinned by digest",
+		},
+		{
+			name: "valid signature",
+			url:  pinned,
+			policy: &v1alpha1.ImageVerification{
+				RequireDigest: true,
+				Signatures:    []v1alpha1.ImageSignature{signImage(t, testRepo, testDigest)},
+			},
+		},
+		{
+			name: "signature of each image",
+			url:  pinned,
+			policy: &v1alpha1.ImageVerification{
+				Signatures: []v1alpha1.ImageSignature{
+					signImage(t, "quay.io/airshipit/toolbox", testDigest),
+					signImage(t, testRepo, testDigest),
+				},
+			},
+		},
+		{
+			name: "signature not found",
+			url:  pinned,
+			policy: &v1alpha1.ImageVerification{
+				Signatures: []v1alpha1.ImageSignature{signImage(t, "quay.io/airshipit/toolbox", testDigest)},
+			},
+			expectedErr: "is not found in image verification policy",
+		},
+		{
+			name: "signed digest mismatch",
+			url:  pinned,
+			policy: &v1alpha1.ImageVerification{
+				Signatures: []v1alpha1.ImageSignature{
+					signImage(t, testRepo, "sha256:0000000000000000000000000000000000000000000000000000000000000000"),
+				},
+			},
+			expectedErr: "signed digest",
+		},
+		{
+			name: "signed reference mismatch",
+			url:  pinned,
+			policy: &v1alpha1.ImageVerification{
+				Signatures: []v1alpha1.ImageSignature{func() v1alpha1.ImageSignature {
+					sig := signImage(t, "quay.io/airshipit/toolbox", testDigest)
+					sig.Image = testRepo
+					return sig
+				}()},
+			},
+			expectedErr: "signed reference",
+		},
+		{
+			name: "invalid signature",
+			url:  pinned,
+			policy: &v1alpha1.ImageVerification{
+				Signatures: []v1alpha1.ImageSignature{{
+					Image:     testRepo,
+					PublicKey: signImage(t, testRepo, testDigest).PublicKey,
+					Payload:   signImage(t, testRepo, testDigest).Payload,
+					Signature: signImage(t, testRepo, testDigest).Signature,
+				}},
+			},
+			expectedErr: "invalid signature",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			err := container.VerifyImage(tt.url, tt.policy)
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
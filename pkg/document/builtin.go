@@ -0,0 +1,373 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package document
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/konfig"
+	kustfs "sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/runtimeutil"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
+	"sigs.k8s.io/yaml"
+
+	"opendev.org/airship/airshipctl/pkg/document/plugin/trace"
+	"opendev.org/airship/airshipctl/pkg/fs"
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+const (
+	// BuiltinGeneratorsFile is the name of the file which holds documents generated by built-in functions
+	BuiltinGeneratorsFile = "airshipctl-builtin-generators.yaml"
+	// BuiltinTransformersFile is the name of the file which holds the result of kustomization
+	// rendered with built-in transformers applied
+	BuiltinTransformersFile = "airshipctl-builtin-transformers.yaml"
+	// BuiltinFunctionAnnotation set to "true" on the function config opts in executing
+	// third-party function image in-process, see RegisterThirdPartyFunction
+	BuiltinFunctionAnnotation = "airshipit.org/builtin-function"
+)
+
+// BuiltinFunctionFactory creates KRM function filter configured by the function config object
+type BuiltinFunctionFactory func(map[string]interface{}) (kio.Filter, error)
+
+var (
+	builtinFunctions    = make(map[string]BuiltinFunctionFactory)
+	thirdPartyFunctions = make(map[string]BuiltinFunctionFactory)
+	// builtinTrace enables tracing of built-in functions
+	builtinTrace bool
+)
+
+// Sensitive is implemented by built-in functions which output may hold plaintext secrets, e.g. the ones
+// decrypting documents. Bundles built with such functions are never cached
+type Sensitive interface {
+	Sensitive() bool
+}
+
+// builtinFunction is a configured built-in function
+type builtinFunction struct {
+	filter kio.Filter
+	config *kyaml.RNode
+}
+
+// RegisterBuiltinFunction registers airship-owned KRM function which is executed in-process during bundle
+// build instead of running its container image. Repository is the full repository of the function image
+// without tag and digest, e.g. "quay.io/airshipit/templater" matches "quay.io/airshipit/templater:latest",
+// but doesn't match "example.com/templater", so that images of other vendors are never replaced implicitly
+func RegisterBuiltinFunction(repository string, factory BuiltinFunctionFactory) {
+	builtinFunctions[repository] = factory
+}
+
+// RegisterThirdPartyFunction registers in-process implementation of KRM function image which isn't owned
+// by airship. Unlike the functions registered with RegisterBuiltinFunction, the image is replaced only if
+// the function config opts in with BuiltinFunctionAnnotation, since the implementation may differ from the image
+func RegisterThirdPartyFunction(repository string, factory BuiltinFunctionFactory) {
+	thirdPartyFunctions[repository] = factory
+}
+
+// SetBuiltinTrace makes built-in functions which implement trace.Tracer annotate the documents they
+// modify with trace records. Functions executed in containers are never traced. Bundles are not
+// cached while tracing is enabled, so that trace annotations don't get into the cache
+func SetBuiltinTrace(enabled bool) {
+	builtinTrace = enabled
+}
+
+// BuiltinFunction returns factory of the built-in function referenced by the container image, third-party
+// functions are returned only if annotations of the function config opt in with BuiltinFunctionAnnotation
+func BuiltinFunction(image string, annotations map[string]string) (BuiltinFunctionFactory, bool) {
+	repository := image
+	if i := strings.Index(repository, "@"); i >= 0 {
+		repository = repository[:i]
+	}
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		repository = repository[:i]
+	}
+	if factory, ok := builtinFunctions[repository]; ok {
+		return factory, true
+	}
+	if annotations[BuiltinFunctionAnnotation] != "true" {
+		return nil, false
+	}
+	factory, ok := thirdPartyFunctions[repository]
+	return factory, ok
+}
+
+// builtinFs is a file system overlay used during kustomize build. When kustomization file is read,
+// its built-in generators are executed in-process and replaced with the file containing generated
+// documents. If the transformers list ends with built-in functions, the kustomization is built
+// without them, the functions are applied to the result in-process and the kustomization is
+// replaced with the one which includes rendered documents only
+type builtinFs struct {
+	fs.FileSystem
+
+	files map[string][]byte
+	err   error
+	// sensitive is set once a function implementing Sensitive reports that its output holds secrets
+	sensitive bool
+}
+
+func newBuiltinFs(fSys fs.FileSystem) *builtinFs {
+	return &builtinFs{
+		FileSystem: fSys,
+		files:      make(map[string][]byte),
+	}
+}
+
+// ReadFile returns overlay file content if it exists, kustomization files are expanded on first read
+func (b *builtinFs) ReadFile(path string) ([]byte, error) {
+	if data, ok := b.files[path]; ok {
+		return data, nil
+	}
+	data, err := b.FileSystem.ReadFile(path)
+	if err != nil || !isKustomizationFile(path) {
+		return data, err
+	}
+	expanded, err := b.expand(path, data)
+	if err != nil {
+		// kustomize ignores errors when looking for kustomization file, keep the error to report it
+		if b.err == nil {
+			b.err = err
+		}
+		return nil, err
+	}
+	return expanded, nil
+}
+
+// Exists returns true for overlay files as well
+func (b *builtinFs) Exists(path string) bool {
+	if _, ok := b.files[path]; ok {
+		return true
+	}
+	return b.FileSystem.Exists(path)
+}
+
+// CleanedAbs resolves overlay files which don't exist in the underlying file system
+func (b *builtinFs) CleanedAbs(path string) (kustfs.ConfirmedDir, string, error) {
+	if _, ok := b.files[path]; ok && !b.FileSystem.Exists(path) {
+		dir, _, err := b.FileSystem.CleanedAbs(filepath.Dir(path))
+		return dir, filepath.Base(path), err
+	}
+	return b.FileSystem.CleanedAbs(path)
+}
+
+func (b *builtinFs) expand(path string, data []byte) ([]byte, error) {
+	kustomization := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &kustomization); err != nil {
+		// let kustomize report malformed kustomization
+		return data, nil
+	}
+
+	generated, err := b.expandGenerators(path, kustomization)
+	if err != nil {
+		return nil, err
+	}
+	filters, err := b.expandTransformers(path, kustomization)
+	if err != nil {
+		return nil, err
+	}
+	if !generated && len(filters) == 0 {
+		return data, nil
+	}
+	if err = b.setFile(path, kustomization); err != nil {
+		return nil, err
+	}
+	if len(filters) == 0 {
+		return b.files[path], nil
+	}
+
+	log.Debugf("Executing %d built-in transformer(s) of kustomization %s in-process", len(filters), path)
+	m, err := newKustomizer().Run(b, filepath.Dir(path))
+	if err != nil {
+		delete(b.files, path)
+		return nil, err
+	}
+	for _, fn := range filters {
+		if err = m.ApplyFilter(fn.filter); err != nil {
+			delete(b.files, path)
+			return nil, err
+		}
+		b.checkSensitive(fn)
+	}
+	rendered, err := m.AsYaml()
+	if err != nil {
+		delete(b.files, path)
+		return nil, err
+	}
+	b.files[filepath.Join(filepath.Dir(path), BuiltinTransformersFile)] = rendered
+	if err = b.setFile(path, map[string]interface{}{"resources": []string{BuiltinTransformersFile}}); err != nil {
+		return nil, err
+	}
+	return b.files[path], nil
+}
+
+// expandGenerators executes built-in generators and replaces them with the resource file
+// containing generated documents, true is returned if kustomization was modified
+func (b *builtinFs) expandGenerators(path string, kustomization map[string]interface{}) (bool, error) {
+	generators, ok := kustomization["generators"].([]interface{})
+	if !ok || len(generators) == 0 {
+		return false, nil
+	}
+
+	var remaining []interface{}
+	var nodes []*kyaml.RNode
+	for _, generator := range generators {
+		entry, ok := generator.(string)
+		if !ok {
+			remaining = append(remaining, generator)
+			continue
+		}
+		fns, err := b.builtinFunctions(path, entry)
+		if err != nil {
+			return false, err
+		}
+		if fns == nil {
+			remaining = append(remaining, generator)
+			continue
+		}
+		for _, fn := range fns {
+			// function config is passed as input, the same way kustomize does it for generators,
+			// it's dropped by kustomize when documents are loaded since it's marked as local config
+			if err = fn.config.PipeE(kyaml.SetAnnotation(konfig.IgnoredByKustomizeAnnotation, "true")); err != nil {
+				return false, err
+			}
+			out, err := fn.filter.Filter([]*kyaml.RNode{fn.config})
+			if err != nil {
+				return false, err
+			}
+			b.checkSensitive(fn)
+			nodes = append(nodes, out...)
+		}
+	}
+	if len(remaining) == len(generators) {
+		return false, nil
+	}
+
+	log.Debugf("Executed %d built-in generator(s) of kustomization %s in-process",
+		len(generators)-len(remaining), path)
+	buf := &bytes.Buffer{}
+	if err := (kio.ByteWriter{Writer: buf}).Write(nodes); err != nil {
+		return false, err
+	}
+	b.files[filepath.Join(filepath.Dir(path), BuiltinGeneratorsFile)] = buf.Bytes()
+
+	resources, _ := kustomization["resources"].([]interface{})
+	kustomization["resources"] = append(resources, BuiltinGeneratorsFile)
+	if len(remaining) == 0 {
+		delete(kustomization, "generators")
+	} else {
+		kustomization["generators"] = remaining
+	}
+	return true, nil
+}
+
+// expandTransformers removes built-in transformers from kustomization and returns them. Only the tail
+// of transformers list is taken into account, since kustomize runs transformers in the order they are listed
+func (b *builtinFs) expandTransformers(path string, kustomization map[string]interface{}) ([]builtinFunction, error) {
+	transformers, ok := kustomization["transformers"].([]interface{})
+	if !ok || len(transformers) == 0 {
+		return nil, nil
+	}
+
+	var result []builtinFunction
+	split := len(transformers)
+	for i := len(transformers) - 1; i >= 0; i-- {
+		entry, ok := transformers[i].(string)
+		if !ok {
+			break
+		}
+		fns, err := b.builtinFunctions(path, entry)
+		if err != nil {
+			return nil, err
+		}
+		if fns == nil {
+			break
+		}
+		result = append(fns, result...)
+		split = i
+	}
+
+	if split == 0 {
+		delete(kustomization, "transformers")
+	} else {
+		kustomization["transformers"] = transformers[:split]
+	}
+	return result, nil
+}
+
+// builtinFunctions loads function configs referenced by kustomization entry, functions are returned
+// only if all of the configs refer to built-in functions
+func (b *builtinFs) builtinFunctions(path string, entry string) ([]builtinFunction, error) {
+	if err := b.setFile(path, map[string]interface{}{"resources": []string{entry}}); err != nil {
+		return nil, err
+	}
+	m, err := newKustomizer().Run(b, filepath.Dir(path))
+	delete(b.files, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []builtinFunction
+	for _, node := range m.ToRNodeSlice() {
+		spec := runtimeutil.GetFunctionSpec(node)
+		if spec == nil {
+			return nil, nil
+		}
+		factory, ok := BuiltinFunction(spec.Container.Image, node.GetAnnotations())
+		if !ok {
+			return nil, nil
+		}
+		cfg, err := node.Map()
+		if err != nil {
+			return nil, err
+		}
+		filter, err := factory(cfg)
+		if err != nil {
+			return nil, err
+		}
+		if tracer, ok := filter.(trace.Tracer); ok && builtinTrace {
+			tracer.EnableTrace()
+		}
+		result = append(result, builtinFunction{filter: filter, config: node})
+	}
+	return result, nil
+}
+
+// checkSensitive marks the overlay as sensitive if the executed function reports that its output holds secrets
+func (b *builtinFs) checkSensitive(fn builtinFunction) {
+	if s, ok := fn.filter.(Sensitive); ok && s.Sensitive() {
+		b.sensitive = true
+	}
+}
+
+func (b *builtinFs) setFile(path string, obj interface{}) error {
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	b.files[path] = data
+	return nil
+}
+
+func isKustomizationFile(path string) bool {
+	name := filepath.Base(path)
+	for _, kf := range konfig.RecognizedKustomizationFileNames() {
+		if name == kf {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,214 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package document_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+func testAnnotationFunction(cfg map[string]interface{}) (kio.Filter, error) {
+	key, ok := cfg["key"].(string)
+	if !ok {
+		return nil, fmt.Errorf("key is not defined")
+	}
+	value, _ := cfg["value"].(string)
+	return kio.FilterFunc(func(items []*yaml.RNode) ([]*yaml.RNode, error) {
+		for _, item := range items {
+			if err := item.PipeE(yaml.SetAnnotation(key, value)); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	}), nil
+}
+
+func testConfigMapFunction(cfg map[string]interface{}) (kio.Filter, error) {
+	name, ok := cfg["name"].(string)
+	if !ok {
+		return nil, fmt.Errorf("name is not defined")
+	}
+	return kio.FilterFunc(func(items []*yaml.RNode) ([]*yaml.RNode, error) {
+		cm, err := yaml.Parse(fmt.Sprintf("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: %s\n", name))
+		if err != nil {
+			return nil, err
+		}
+		return append(items, cm), nil
+	}), nil
+}
+
+// testTraceFunction annotates documents with the trace status of the function
+type testTraceFunction struct {
+	traced bool
+}
+
+func (f *testTraceFunction) EnableTrace() {
+	f.traced = true
+}
+
+func (f *testTraceFunction) Filter(items []*yaml.RNode) ([]*yaml.RNode, error) {
+	for _, item := range items {
+		if err := item.PipeE(yaml.SetAnnotation("traced", strconv.FormatBool(f.traced))); err != nil {
+			return nil, err
+		}
+	}
+	return items, nil
+}
+
+// testSensitiveFunction reports whether its output holds secrets
+type testSensitiveFunction struct {
+	sensitive bool
+}
+
+func (f *testSensitiveFunction) Sensitive() bool {
+	return f.sensitive
+}
+
+func (f *testSensitiveFunction) Filter(items []*yaml.RNode) ([]*yaml.RNode, error) {
+	return items, nil
+}
+
+func TestBuiltinFunction(t *testing.T) {
+	document.RegisterBuiltinFunction("quay.io/airshipit/test-annotation", testAnnotationFunction)
+
+	for _, image := range []string{
+		"quay.io/airshipit/test-annotation",
+		"quay.io/airshipit/test-annotation:v1",
+		"quay.io/airshipit/test-annotation@sha256:6c3c624b58dbbcd3c0dd82b4c53f04194d1247c6eebdaab7c610cf7d66709b3b",
+	} {
+		_, ok := document.BuiltinFunction(image, nil)
+		assert.True(t, ok, image)
+	}
+	for _, image := range []string{
+		"test-annotation",
+		"localhost/test-annotation",
+		"example.com/airshipit/test-annotation:v1",
+		"quay.io/airshipit/test-annotation-unknown",
+	} {
+		_, ok := document.BuiltinFunction(image, nil)
+		assert.False(t, ok, image)
+	}
+}
+
+func TestBuiltinFunctionThirdParty(t *testing.T) {
+	document.RegisterThirdPartyFunction("example.com/vendor/test-annotation", testAnnotationFunction)
+
+	image := "example.com/vendor/test-annotation:v1"
+	for _, annotations := range []map[string]string{
+		nil,
+		{document.BuiltinFunctionAnnotation: "false"},
+	} {
+		_, ok := document.BuiltinFunction(image, annotations)
+		assert.False(t, ok, annotations)
+	}
+	_, ok := document.BuiltinFunction(image, map[string]string{document.BuiltinFunctionAnnotation: "true"})
+	assert.True(t, ok)
+}
+
+func TestNewBundleBuiltinFunctions(t *testing.T) {
+	document.RegisterBuiltinFunction("localhost/test-annotation", testAnnotationFunction)
+	document.RegisterBuiltinFunction("quay.io/airshipit/test-annotation", testAnnotationFunction)
+	document.RegisterBuiltinFunction("localhost/test-configmap", testConfigMapFunction)
+
+	bundle, err := document.NewBundleByPath("testdata/builtin")
+	require.NoError(t, err)
+
+	docs, err := bundle.GetAllDocuments()
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	names := []string{}
+	for _, doc := range docs {
+		names = append(names, doc.GetName())
+		assert.Equal(t, map[string]string{
+			"child":  "applied",
+			"parent": "applied",
+		}, doc.GetAnnotations())
+	}
+	assert.ElementsMatch(t, []string{"test-cm", "generated-cm"}, names)
+}
+
+func TestNewBundleBuiltinTrace(t *testing.T) {
+	traceFunction := func(map[string]interface{}) (kio.Filter, error) {
+		return &testTraceFunction{}, nil
+	}
+	document.RegisterBuiltinFunction("localhost/test-annotation", traceFunction)
+	document.RegisterBuiltinFunction("quay.io/airshipit/test-annotation", traceFunction)
+	document.RegisterBuiltinFunction("localhost/test-configmap", testConfigMapFunction)
+	defer document.RegisterBuiltinFunction("localhost/test-annotation", testAnnotationFunction)
+	defer document.RegisterBuiltinFunction("quay.io/airshipit/test-annotation", testAnnotationFunction)
+
+	cacheDir, cleanup := testutil.TempDir(t, "airship-cache")
+	defer cleanup(t)
+	document.SetBundleCacheDir(cacheDir)
+	defer document.SetBundleCacheDir("")
+	defer document.SetBuiltinTrace(false)
+
+	for _, traced := range []bool{true, false} {
+		document.SetBuiltinTrace(traced)
+		bundle, err := document.NewBundleByPath("testdata/builtin")
+		require.NoError(t, err)
+		docs, err := bundle.GetAllDocuments()
+		require.NoError(t, err)
+		require.Len(t, docs, 2)
+		for _, doc := range docs {
+			assert.Equal(t, strconv.FormatBool(traced), doc.GetAnnotations()["traced"])
+		}
+
+		// traced documents are never cached
+		cached, err := ioutil.ReadDir(cacheDir)
+		require.NoError(t, err)
+		assert.Equal(t, !traced, len(cached) > 0)
+	}
+}
+
+func TestNewBundleBuiltinSensitive(t *testing.T) {
+	defer document.RegisterBuiltinFunction("localhost/test-annotation", testAnnotationFunction)
+	defer document.RegisterBuiltinFunction("quay.io/airshipit/test-annotation", testAnnotationFunction)
+	document.RegisterBuiltinFunction("localhost/test-configmap", testConfigMapFunction)
+
+	for _, sensitive := range []bool{true, false} {
+		sensitiveFunction := func(map[string]interface{}) (kio.Filter, error) {
+			return &testSensitiveFunction{sensitive: sensitive}, nil
+		}
+		document.RegisterBuiltinFunction("localhost/test-annotation", sensitiveFunction)
+		document.RegisterBuiltinFunction("quay.io/airshipit/test-annotation", sensitiveFunction)
+
+		cacheDir, cleanup := testutil.TempDir(t, "airship-cache")
+		document.SetBundleCacheDir(cacheDir)
+		bundle, err := document.NewBundleByPath("testdata/builtin")
+		document.SetBundleCacheDir("")
+		require.NoError(t, err)
+		docs, err := bundle.GetAllDocuments()
+		require.NoError(t, err)
+		assert.Len(t, docs, 2)
+
+		// documents which may hold plaintext secrets are never cached
+		cached, err := ioutil.ReadDir(cacheDir)
+		require.NoError(t, err)
+		assert.Equal(t, !sensitive, len(cached) > 0)
+		cleanup(t)
+	}
+}
@@ -57,6 +57,8 @@ type Bundle interface {
 	GetByLabel(labelSelector string) ([]Document, error)
 	GetAllDocuments() ([]Document, error)
 	Append(Document) error
+	Replace(Document) error
+	Delete(Document) error
 }
 
 // DocFactoryFunc is a type of function which returns (Document, error) and can be used on demand
@@ -84,9 +86,13 @@ func BundleFactoryFromDocRoot(docRootFunc func() (string, error)) BundleFactoryF
 	}
 }
 
-// NewBundleByPath is a function which builds new document.Bundle from kustomize rootPath using default FS object
+// NewBundleByPath is a function which builds new document.Bundle from kustomize rootPath using default FS object,
+// the bundle is taken from the cache if it's enabled by SetBundleCacheDir and built-in functions are not traced
 // example: document.NewBundleByPath("path/to/phase-root")
 func NewBundleByPath(rootPath string) (Bundle, error) {
+	if bundleCacheDir != "" && !builtinTrace {
+		return newCachedBundle(fs.NewDocumentFs(), rootPath)
+	}
 	return NewBundle(fs.NewDocumentFs(), rootPath)
 }
 
@@ -109,6 +115,16 @@ func NewBundleFromBytes(data []byte) (Bundle, error) {
 // Over time, it will evolve to support allowing more control
 // for kustomize plugins
 func NewBundle(fSys fs.FileSystem, kustomizePath string) (Bundle, error) {
+	bundle, _, err := buildBundle(fSys, kustomizePath)
+	if err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// buildBundle builds the bundle from kustomize path, true is returned if any of the executed
+// built-in functions reported that the documents may hold plaintext secrets
+func buildBundle(fSys fs.FileSystem, kustomizePath string) (*BundleFactory, bool, error) {
 	var options = KustomizeBuildOptions{
 		KustomizationPath: kustomizePath,
 		LoadRestrictions:  types.LoadRestrictionsRootOnly,
@@ -119,29 +135,44 @@ func NewBundle(fSys fs.FileSystem, kustomizePath string) (Bundle, error) {
 
 	// set the fs and build options we will use
 	if err := bundle.SetFileSystem(fSys); err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	if err := bundle.SetKustomizeBuildOptions(options); err != nil {
-		return nil, err
+		return nil, false, err
+	}
+
+	var buildFs fs.FileSystem = fSys
+	var overlay *builtinFs
+	if len(builtinFunctions) > 0 {
+		// built-in KRM functions are executed in-process through file system overlay
+		overlay = newBuiltinFs(fSys)
+		buildFs = overlay
 	}
 
-	var o = krusty.Options{
+	m, err := newKustomizer().Run(buildFs, kustomizePath)
+	if overlay != nil && overlay.err != nil {
+		return nil, false, overlay.err
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if err = bundle.SetKustomizeResourceMap(m); err != nil {
+		return nil, false, err
+	}
+	return bundle, overlay != nil && overlay.sensitive, nil
+}
+
+// newKustomizer returns kustomizer configured with airshipctl build options
+func newKustomizer() *krusty.Kustomizer {
+	return krusty.MakeKustomizer(&krusty.Options{
 		DoLegacyResourceSort: true, // Default and what we want
-		LoadRestrictions:     options.LoadRestrictions,
+		LoadRestrictions:     types.LoadRestrictionsRootOnly,
 		DoPrune:              false, // Default
 		PluginConfig: &types.PluginConfig{
 			PluginRestrictions: types.PluginRestrictionsNone,
 			BpLoadingOptions:   types.BploUseStaticallyLinked,
 		},
-	}
-
-	kustomizer := krusty.MakeKustomizer(&o)
-	m, err := kustomizer.Run(fSys, kustomizePath)
-	if err != nil {
-		return nil, err
-	}
-	err = bundle.SetKustomizeResourceMap(m)
-	return bundle, err
+	})
 }
 
 // GetKustomizeResourceMap returns a Kustomize Resource Map for this bundle
@@ -396,6 +427,61 @@ func (b *BundleFactory) Append(doc Document) error {
 	return b.ResMap.Append(res)
 }
 
+// Replace substitutes the document of the bundle having the same group, version, kind,
+// namespace and name with the passed one
+func (b *BundleFactory) Replace(doc Document) error {
+	res, err := documentResource(doc)
+	if err != nil {
+		return err
+	}
+	if err = b.checkDocumentExists(doc, res); err != nil {
+		return err
+	}
+	_, err = b.ResMap.Replace(res)
+	return err
+}
+
+// Delete removes the document having the same group, version, kind, namespace and name
+// as the passed one from the bundle
+func (b *BundleFactory) Delete(doc Document) error {
+	res, err := documentResource(doc)
+	if err != nil {
+		return err
+	}
+	if err = b.checkDocumentExists(doc, res); err != nil {
+		return err
+	}
+	return b.ResMap.Remove(res.CurId())
+}
+
+func (b *BundleFactory) checkDocumentExists(doc Document, res *resource.Resource) error {
+	i, err := b.ResMap.GetIndexOfCurrentId(res.CurId())
+	if err != nil {
+		return err
+	}
+	if i < 0 {
+		return ErrDocNotFound{Selector: NewSelector().
+			ByGvk(doc.GetGroup(), doc.GetVersion(), doc.GetKind()).
+			ByNamespace(doc.GetNamespace()).
+			ByName(doc.GetName())}
+	}
+	return nil
+}
+
+// documentResource returns kustomize resource of the document, documents implemented by this
+// package are copied as is to keep yaml comments, others are converted using their yaml
+func documentResource(doc Document) (*resource.Resource, error) {
+	if f, ok := doc.(*Factory); ok {
+		res := f.GetKustomizeResource()
+		return res.DeepCopy(), nil
+	}
+	yaml, err := doc.AsYAML()
+	if err != nil {
+		return nil, err
+	}
+	return resource.NewFactory(&hasher.Hasher{}).FromBytes(yaml)
+}
+
 // Write will write out the entire bundle resource map
 func (b *BundleFactory) Write(out io.Writer) error {
 	for _, res := range b.ResMap.Resources() {
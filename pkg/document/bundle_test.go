@@ -293,3 +293,31 @@ func TestSelectBundle(t *testing.T) {
 		assert.Len(t, docs, tt.expectedDocs)
 	}
 }
+
+func TestBundleReplaceDelete(t *testing.T) {
+	bundle := testutil.NewTestBundle(t, "testdata/common")
+
+	doc, err := bundle.GetByName("some-random-deployment-we-will-filter")
+	require.NoError(t, err)
+	data, err := doc.AsYAML()
+	require.NoError(t, err)
+	newDoc, err := document.NewDocumentFromBytes(data)
+	require.NoError(t, err)
+	require.NoError(t, newDoc.SetField("metadata.labels.replaced", "true"))
+
+	require.NoError(t, bundle.Replace(newDoc))
+	doc, err = bundle.GetByName("some-random-deployment-we-will-filter")
+	require.NoError(t, err)
+	assert.Equal(t, "true", doc.GetLabels()["replaced"])
+
+	require.NoError(t, bundle.Delete(newDoc))
+	_, err = bundle.GetByName("some-random-deployment-we-will-filter")
+	assert.Error(t, err)
+
+	expectedErr := document.ErrDocNotFound{Selector: document.NewSelector().
+		ByGvk(newDoc.GetGroup(), newDoc.GetVersion(), newDoc.GetKind()).
+		ByNamespace(newDoc.GetNamespace()).
+		ByName(newDoc.GetName())}
+	assert.Equal(t, expectedErr, bundle.Delete(newDoc))
+	assert.Equal(t, expectedErr, bundle.Replace(newDoc))
+}
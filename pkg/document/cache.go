@@ -0,0 +1,459 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package document
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/hasher"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/runtimeutil"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/yaml"
+
+	"opendev.org/airship/airshipctl/pkg/fs"
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/version"
+)
+
+const (
+	// BundleCacheDir is the name of the work dir subdirectory which holds rendered bundles
+	BundleCacheDir = "cache"
+
+	cacheIndexExt = ".json"
+	cacheDataExt  = ".yaml"
+
+	cacheDirMode  = 0700
+	cacheFileMode = 0600
+)
+
+// bundleCacheDir is the directory used to cache bundles, empty value disables the cache
+var bundleCacheDir string
+
+// BundleCachePath returns the bundle cache directory located in the airshipctl work dir
+func BundleCachePath(workDir string) string {
+	return filepath.Join(workDir, BundleCacheDir)
+}
+
+// SetBundleCacheDir enables caching of bundles built by NewBundleByPath in the given directory,
+// the cache is disabled if dir is empty
+func SetBundleCacheDir(dir string) {
+	bundleCacheDir = dir
+}
+
+// cacheIndex describes cached bundle of the kustomize entrypoint. Key is the hash of all files
+// read during the build or bind-mounted to the functions, function images referenced by them,
+// values of the environment variables exported to the functions and airshipctl version, it's used
+// as a name of the file containing rendered documents
+type cacheIndex struct {
+	Entrypoint string            `json:"entrypoint"`
+	Key        string            `json:"key"`
+	Files      map[string]string `json:"files"`
+	Images     []string          `json:"images,omitempty"`
+	Env        map[string]string `json:"env,omitempty"`
+}
+
+// bundleCache stores rendered bundles in the directory
+type bundleCache struct {
+	dir  string
+	fSys fs.FileSystem
+}
+
+// newCachedBundle returns bundle from the cache if none of the files used to build
+// it has changed, otherwise the bundle is built and stored in the cache. Bundles which
+// may hold plaintext secrets, e.g. the ones built by functions implementing Sensitive or
+// containing Secret documents, are never stored
+func newCachedBundle(fSys fs.FileSystem, kustomizePath string) (Bundle, error) {
+	cache := &bundleCache{dir: bundleCacheDir, fSys: fSys}
+	if bundle, ok := cache.get(kustomizePath); ok {
+		log.Debugf("Using cached documents of %s", kustomizePath)
+		return bundle, nil
+	}
+
+	recorder := &recordingFs{FileSystem: fSys, files: make(map[string][]byte)}
+	bundle, sensitive, err := buildBundle(recorder, kustomizePath)
+	if err != nil {
+		return nil, err
+	}
+	if err = bundle.SetFileSystem(fSys); err != nil {
+		return nil, err
+	}
+	if sensitive {
+		log.Debugf("Documents of %s may hold plaintext secrets, they are not cached", kustomizePath)
+		return bundle, nil
+	}
+	// failure to store the bundle must not fail the build
+	if err = cache.put(kustomizePath, recorder.files, bundle); err != nil {
+		log.Debugf("Unable to cache documents of %s: %v", kustomizePath, err)
+	}
+	return bundle, nil
+}
+
+func (c *bundleCache) indexPath(kustomizePath string) string {
+	abs, err := filepath.Abs(kustomizePath)
+	if err != nil {
+		abs = kustomizePath
+	}
+	return filepath.Join(c.dir, hash([]byte(abs))+cacheIndexExt)
+}
+
+func (c *bundleCache) get(kustomizePath string) (Bundle, bool) {
+	index, err := readCacheIndex(c.fSys, c.indexPath(kustomizePath))
+	if err != nil || !index.valid(c.fSys) {
+		return nil, false
+	}
+	data, err := c.fSys.ReadFile(filepath.Join(c.dir, index.Key+cacheDataExt))
+	if err != nil {
+		return nil, false
+	}
+	m, err := resmap.NewFactory(resource.NewFactory(&hasher.Hasher{})).NewResMapFromBytes(data)
+	if err != nil {
+		return nil, false
+	}
+	return &BundleFactory{
+		KustomizeBuildOptions: KustomizeBuildOptions{
+			KustomizationPath: kustomizePath,
+			LoadRestrictions:  types.LoadRestrictionsRootOnly,
+		},
+		ResMap:     m,
+		FileSystem: c.fSys,
+	}, true
+}
+
+func (c *bundleCache) put(kustomizePath string, files map[string][]byte, bundle Bundle) error {
+	b, ok := bundle.(*BundleFactory)
+	if !ok {
+		return nil
+	}
+	for _, r := range b.ResMap.Resources() {
+		if r.GetKind() == SecretKind {
+			log.Debugf("Documents of %s contain Secret %s, they are not cached", kustomizePath, r.GetName())
+			return nil
+		}
+	}
+	inputs, reason := cacheInputs(c.fSys, files)
+	if reason != "" {
+		log.Debugf("Documents of %s are not cached: %s", kustomizePath, reason)
+		return nil
+	}
+	data, err := b.ResMap.AsYaml()
+	if err != nil {
+		return err
+	}
+
+	index := &cacheIndex{
+		Entrypoint: kustomizePath,
+		Files:      make(map[string]string, len(inputs.files)),
+		Images:     inputs.images,
+		Env:        make(map[string]string, len(inputs.envs)),
+	}
+	for path, content := range inputs.files {
+		index.Files[path] = hash(content)
+	}
+	for _, name := range inputs.envs {
+		index.Env[name] = envHash(name)
+	}
+	if index.Key, err = index.key(); err != nil {
+		return err
+	}
+
+	// cached documents are readable by the owner only
+	if err = c.fSys.MkdirAll(c.dir); err != nil {
+		return err
+	}
+	if err = c.fSys.Chmod(c.dir, cacheDirMode); err != nil {
+		return err
+	}
+	if err = c.writeFile(filepath.Join(c.dir, index.Key+cacheDataExt), data); err != nil {
+		return err
+	}
+	indexData, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	return c.writeFile(c.indexPath(kustomizePath), indexData)
+}
+
+func (c *bundleCache) writeFile(path string, data []byte) error {
+	if err := c.fSys.WriteFile(path, data); err != nil {
+		return err
+	}
+	return c.fSys.Chmod(path, cacheFileMode)
+}
+
+// key calculates content hash of the cache entry
+func (i *cacheIndex) key() (string, error) {
+	paths := make([]string, 0, len(i.Files))
+	for path := range i.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	h := sha256.New()
+	for _, s := range []string{version.Get().GitVersion, i.Entrypoint} {
+		if _, err := h.Write([]byte(s + "\n")); err != nil {
+			return "", err
+		}
+	}
+	for _, path := range paths {
+		if _, err := h.Write([]byte(path + " " + i.Files[path] + "\n")); err != nil {
+			return "", err
+		}
+	}
+	for _, image := range i.Images {
+		if _, err := h.Write([]byte(image + "\n")); err != nil {
+			return "", err
+		}
+	}
+	names := make([]string, 0, len(i.Env))
+	for name := range i.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, err := h.Write([]byte(name + " " + i.Env[name] + "\n")); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// valid returns true if none of the files used to build the bundle and none of the environment
+// variables exported to the functions has changed
+func (i *cacheIndex) valid(fSys fs.FileSystem) bool {
+	if i.Key == "" {
+		return false
+	}
+	for path, sum := range i.Files {
+		data, err := fSys.ReadFile(path)
+		if err != nil || hash(data) != sum {
+			return false
+		}
+	}
+	for name, sum := range i.Env {
+		if envHash(name) != sum {
+			return false
+		}
+	}
+	return true
+}
+
+func readCacheIndex(fSys fs.FileSystem, path string) (*cacheIndex, error) {
+	data, err := fSys.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	index := &cacheIndex{}
+	return index, json.Unmarshal(data, index)
+}
+
+// PruneBundleCache removes outdated entries from the bundle cache directory, i.e. the ones
+// which files have changed since the bundle was built. All entries are removed if all is true.
+// Number of removed entries is returned
+func PruneBundleCache(fSys fs.FileSystem, dir string, all bool) (int, error) {
+	indexes, err := fSys.Glob(filepath.Join(dir, "*"+cacheIndexExt))
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	keep := make(map[string]bool)
+	for _, path := range indexes {
+		index, readErr := readCacheIndex(fSys, path)
+		if readErr == nil && !all && index.valid(fSys) {
+			keep[filepath.Join(dir, index.Key+cacheDataExt)] = true
+			continue
+		}
+		if err = fSys.RemoveAll(path); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+
+	data, err := fSys.Glob(filepath.Join(dir, "*"+cacheDataExt))
+	if err != nil {
+		return removed, err
+	}
+	for _, path := range data {
+		if keep[path] {
+			continue
+		}
+		if err = fSys.RemoveAll(path); err != nil {
+			return removed, err
+		}
+	}
+	return removed, nil
+}
+
+// bundleInputs are the inputs of the bundle build which are tracked by the cache
+type bundleInputs struct {
+	// files are the files read during the build and the files bind-mounted to the functions
+	files map[string][]byte
+	// images are KRM function images referenced by the files
+	images []string
+	// envs are the names of the environment variables exported to the functions from the host
+	envs []string
+}
+
+// cacheInputs collects the inputs of the bundle build from the files read during the build.
+// Non-empty reason is returned if the bundle depends on the inputs which can't be tracked:
+// function images which are neither built-in nor pinned by digest, since their tags may be
+// moved to other images, volumes mounted to the functions and remote kustomize resources
+func cacheInputs(fSys fs.FileSystem, files map[string][]byte) (*bundleInputs, string) {
+	inputs := &bundleInputs{files: make(map[string][]byte, len(files))}
+	for path, content := range files {
+		inputs.files[path] = content
+	}
+	imageSet := make(map[string]struct{})
+	envSet := make(map[string]struct{})
+	for path, content := range files {
+		if isKustomizationFile(path) {
+			if remote := remoteResource(fSys, path, content); remote != "" {
+				return nil, "remote resource " + remote + " is used"
+			}
+			continue
+		}
+		nodes, err := kio.FromBytes(content)
+		if err != nil {
+			continue
+		}
+		for _, node := range nodes {
+			spec := runtimeutil.GetFunctionSpec(node)
+			if spec == nil {
+				continue
+			}
+			if image := spec.Container.Image; image != "" {
+				if _, builtin := BuiltinFunction(image, node.GetAnnotations()); !builtin && !strings.Contains(image, "@sha256:") {
+					return nil, "function image " + image + " is not pinned by digest"
+				}
+				imageSet[image] = struct{}{}
+			}
+			// variables defined as KEY=VALUE are the part of the file content
+			for _, env := range spec.Container.Env {
+				if !strings.Contains(env, "=") {
+					envSet[env] = struct{}{}
+				}
+			}
+			for _, mount := range spec.Container.StorageMounts {
+				if reason := readMount(fSys, filepath.Dir(path), mount, inputs.files); reason != "" {
+					return nil, reason
+				}
+			}
+		}
+	}
+	inputs.images = sortedKeys(imageSet)
+	inputs.envs = sortedKeys(envSet)
+	return inputs, ""
+}
+
+// readMount reads the files bind-mounted to the function, relative source path is resolved
+// against the directory of the function config
+func readMount(fSys fs.FileSystem, dir string, mount runtimeutil.StorageMount, files map[string][]byte) string {
+	switch mount.MountType {
+	case "tmpfs":
+		return ""
+	case "bind":
+	default:
+		return "storage mount of type " + mount.MountType + " is used"
+	}
+	src := mount.Src
+	if !filepath.IsAbs(src) {
+		src = filepath.Join(dir, src)
+	}
+	err := fSys.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		data, err := fSys.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files[path] = data
+		return nil
+	})
+	if err != nil {
+		return "unable to read mounted path " + src + ": " + err.Error()
+	}
+	return ""
+}
+
+// remoteResource returns the first resource of the kustomization which can't be found
+// in the file system, i.e. the one fetched by kustomize from remote location
+func remoteResource(fSys fs.FileSystem, path string, content []byte) string {
+	k := &types.Kustomization{}
+	if err := yaml.Unmarshal(content, k); err != nil {
+		return ""
+	}
+	dir := filepath.Dir(path)
+	for _, list := range [][]string{k.Resources, k.Bases, k.Components} {
+		for _, resource := range list {
+			path := resource
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(dir, path)
+			}
+			if strings.Contains(resource, "://") || !fSys.Exists(path) {
+				return resource
+			}
+		}
+	}
+	return ""
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// envHash returns hash of the environment variable value, unset variable differs from the empty one
+func envHash(name string) string {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return ""
+	}
+	return hash([]byte(value))
+}
+
+func hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// recordingFs keeps content of the files read during kustomize build
+type recordingFs struct {
+	fs.FileSystem
+
+	files map[string][]byte
+}
+
+// ReadFile reads the file and records its content
+func (r *recordingFs) ReadFile(path string) ([]byte, error) {
+	data, err := r.FileSystem.ReadFile(path)
+	if err == nil {
+		r.files[path] = data
+	}
+	return data, err
+}
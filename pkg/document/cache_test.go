@@ -0,0 +1,221 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package document_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/fs"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+func writeCacheTestFiles(t *testing.T, dir, name string) {
+	t.Helper()
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "kustomization.yaml"),
+		[]byte("resources:\n  - configmap.yaml\n"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "configmap.yaml"),
+		[]byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: "+name+"\n"), 0600))
+}
+
+// tamperCachedBundle replaces cached documents, so that it's possible to tell whether the cache is used
+func tamperCachedBundle(t *testing.T, cacheDir, name string) {
+	t.Helper()
+	data, err := filepath.Glob(filepath.Join(cacheDir, "*.yaml"))
+	require.NoError(t, err)
+	require.Len(t, data, 1)
+	require.NoError(t, ioutil.WriteFile(data[0],
+		[]byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: "+name+"\n"), 0600))
+}
+
+func TestBundleCache(t *testing.T) {
+	cacheDir, cleanupCache := testutil.TempDir(t, "airship-cache")
+	defer cleanupCache(t)
+	entrypoint, cleanupEntrypoint := testutil.TempDir(t, "airship-entrypoint")
+	defer cleanupEntrypoint(t)
+
+	document.SetBundleCacheDir(cacheDir)
+	defer document.SetBundleCacheDir("")
+
+	writeCacheTestFiles(t, entrypoint, "cm-1")
+	bundle, err := document.NewBundleByPath(entrypoint)
+	require.NoError(t, err)
+	_, err = bundle.GetByName("cm-1")
+	require.NoError(t, err)
+
+	cached, err := filepath.Glob(filepath.Join(cacheDir, "*"))
+	require.NoError(t, err)
+	assert.Len(t, cached, 2)
+	// cached documents are readable by the owner only
+	for _, path := range append(cached, cacheDir) {
+		info, statErr := os.Stat(path)
+		require.NoError(t, statErr)
+		if info.IsDir() {
+			assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+		} else {
+			assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+		}
+	}
+
+	// cached bundle is used while the files stay the same
+	tamperCachedBundle(t, cacheDir, "cm-cached")
+	bundle, err = document.NewBundleByPath(entrypoint)
+	require.NoError(t, err)
+	_, err = bundle.GetByName("cm-cached")
+	require.NoError(t, err)
+
+	// outdated entry is pruned and the bundle is rebuilt after the files are changed
+	writeCacheTestFiles(t, entrypoint, "cm-2")
+	removed, err := document.PruneBundleCache(fs.NewDocumentFs(), cacheDir, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	bundle, err = document.NewBundleByPath(entrypoint)
+	require.NoError(t, err)
+	_, err = bundle.GetByName("cm-2")
+	require.NoError(t, err)
+
+	removed, err = document.PruneBundleCache(fs.NewDocumentFs(), cacheDir, false)
+	require.NoError(t, err)
+	assert.Equal(t, 0, removed)
+
+	removed, err = document.PruneBundleCache(fs.NewDocumentFs(), cacheDir, true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	cached, err = filepath.Glob(filepath.Join(cacheDir, "*"))
+	require.NoError(t, err)
+	assert.Empty(t, cached)
+}
+
+func TestBundleCacheFunctionEnv(t *testing.T) {
+	cacheDir, cleanupCache := testutil.TempDir(t, "airship-cache")
+	defer cleanupCache(t)
+	entrypoint, cleanupEntrypoint := testutil.TempDir(t, "airship-entrypoint")
+	defer cleanupEntrypoint(t)
+
+	document.SetBundleCacheDir(cacheDir)
+	defer document.SetBundleCacheDir("")
+
+	const envName = "AIRSHIP_CACHE_TEST_ENV"
+	require.NoError(t, os.Setenv(envName, "one"))
+	defer os.Unsetenv(envName)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(entrypoint, "kustomization.yaml"),
+		[]byte("resources:\n  - function.yaml\n"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(entrypoint, "function.yaml"), []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: function
+  annotations:
+    config.kubernetes.io/function: |
+      container:
+        image: localhost/function@sha256:`+strings.Repeat("0", 64)+`
+        envs:
+        - `+envName+`
+`), 0600))
+
+	_, err := document.NewBundleByPath(entrypoint)
+	require.NoError(t, err)
+	tamperCachedBundle(t, cacheDir, "cm-cached")
+
+	bundle, err := document.NewBundleByPath(entrypoint)
+	require.NoError(t, err)
+	_, err = bundle.GetByName("cm-cached")
+	require.NoError(t, err)
+
+	// bundle is rebuilt once the variable exported to the function changes
+	require.NoError(t, os.Setenv(envName, "two"))
+	bundle, err = document.NewBundleByPath(entrypoint)
+	require.NoError(t, err)
+	_, err = bundle.GetByName("function")
+	require.NoError(t, err)
+}
+
+func TestBundleCacheSkipped(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+	}{
+		{
+			name: "secret",
+			document: `apiVersion: v1
+kind: Secret
+metadata:
+  name: credentials
+stringData:
+  password: plaintext
+`,
+		},
+		{
+			name: "function image not pinned by digest",
+			document: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: function
+  annotations:
+    config.kubernetes.io/function: |
+      container:
+        image: localhost/function:latest
+`,
+		},
+		{
+			name: "function volume mount",
+			document: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: function
+  annotations:
+    config.kubernetes.io/function: |
+      container:
+        image: localhost/function@sha256:` + strings.Repeat("0", 64) + `
+        mounts:
+        - type: volume
+          src: data
+          dst: /data
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			cacheDir, cleanupCache := testutil.TempDir(t, "airship-cache")
+			defer cleanupCache(t)
+			entrypoint, cleanupEntrypoint := testutil.TempDir(t, "airship-entrypoint")
+			defer cleanupEntrypoint(t)
+
+			document.SetBundleCacheDir(cacheDir)
+			defer document.SetBundleCacheDir("")
+
+			require.NoError(t, ioutil.WriteFile(filepath.Join(entrypoint, "kustomization.yaml"),
+				[]byte("resources:\n  - document.yaml\n"), 0600))
+			require.NoError(t, ioutil.WriteFile(filepath.Join(entrypoint, "document.yaml"),
+				[]byte(tt.document), 0600))
+
+			_, err := document.NewBundleByPath(entrypoint)
+			require.NoError(t, err)
+			cached, err := filepath.Glob(filepath.Join(cacheDir, "*"))
+			require.NoError(t, err)
+			assert.Empty(t, cached)
+		})
+	}
+}
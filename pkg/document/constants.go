@@ -37,15 +37,6 @@ const (
 	ClusterctlMetadataVersion = "v1alpha3"
 	ClusterctlMetadataGroup   = "clusterctl.cluster.x-k8s.io"
 
-	// ValidatorGroup defines Group for document-validation container
-	ValidatorGroup = "airshipit.org"
-	// ValidatorVersion defines Version for document-validation container
-	ValidatorVersion = "v1alpha1"
-	// ValidatorKind defines Kind for document-validation container
-	ValidatorKind = "GenericContainer"
-	// ValidatorName defines Name for document-validation container
-	ValidatorName = "document-validation"
-
 	// CRDKind is a kind for custom resource definition documents
 	CRDKind = "CustomResourceDefinition"
 
@@ -0,0 +1,326 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package diff
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+)
+
+// Action describes what happened to the object or to the field between two bundles
+type Action string
+
+const (
+	// Added means that the object or the field exists only in the second bundle
+	Added Action = "added"
+	// Removed means that the object or the field exists only in the first bundle
+	Removed Action = "removed"
+	// Changed means that the object or the value of the field differs between the bundles
+	Changed Action = "changed"
+
+	// MaskedValue replaces the values which are not compared unless secrets are requested
+	MaskedValue = "<masked>"
+
+	sopsMetadataKey = "sops"
+)
+
+var (
+	encryptedValue = regexp.MustCompile(`^ENC\[AES256_GCM,`)
+	simpleKey      = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+// FieldChange is a difference of a single field of the object
+type FieldChange struct {
+	Action Action      `json:"action"`
+	Path   string      `json:"path"`
+	From   interface{} `json:"from,omitempty"`
+	To     interface{} `json:"to,omitempty"`
+}
+
+// Change is a difference of a single object, fields are set only for changed objects
+type Change struct {
+	Action     Action        `json:"action"`
+	APIVersion string        `json:"apiVersion"`
+	Kind       string        `json:"kind"`
+	Namespace  string        `json:"namespace,omitempty"`
+	Name       string        `json:"name"`
+	Fields     []FieldChange `json:"fields,omitempty"`
+}
+
+// Options control which parts of the documents are compared
+type Options struct {
+	// ShowSecrets enables comparison of Secret data and SOPS encrypted values, which are masked
+	// by default since they are regenerated or re-encrypted on every change of the secrets
+	ShowSecrets bool
+}
+
+type object struct {
+	id      string
+	header  Change
+	content map[string]interface{}
+}
+
+// Diff compares documents of two bundles. Documents are matched by group, kind, namespace and
+// name, so neither the order of the documents nor the order of the keyed lists affect the result
+func Diff(from, to document.Bundle, opts Options) ([]Change, error) {
+	fromObjects, err := objects(from, opts)
+	if err != nil {
+		return nil, err
+	}
+	toObjects, err := objects(to, opts)
+	if err != nil {
+		return nil, err
+	}
+	return diffObjects(fromObjects, toObjects), nil
+}
+
+func objects(bundle document.Bundle, opts Options) (map[string]object, error) {
+	docs, err := bundle.GetAllDocuments()
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]object, len(docs))
+	for _, doc := range docs {
+		content := map[string]interface{}{}
+		if err = doc.ToObject(&content); err != nil {
+			return nil, err
+		}
+		if !opts.ShowSecrets {
+			content = mask(doc.GetKind(), content)
+		}
+		obj := object{
+			id: strings.Join([]string{doc.GetKind(), doc.GetNamespace(), doc.GetName(), doc.GetGroup()}, "/"),
+			header: Change{
+				APIVersion: document.APIVersion(doc),
+				Kind:       doc.GetKind(),
+				Namespace:  doc.GetNamespace(),
+				Name:       doc.GetName(),
+			},
+			content: content,
+		}
+		result[obj.id] = obj
+	}
+	return result, nil
+}
+
+func diffObjects(from, to map[string]object) []Change {
+	ids := make([]string, 0, len(from)+len(to))
+	for id := range from {
+		ids = append(ids, id)
+	}
+	for id := range to {
+		if _, exists := from[id]; !exists {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	var changes []Change
+	for _, id := range ids {
+		fromObj, inFrom := from[id]
+		toObj, inTo := to[id]
+		switch {
+		case !inTo:
+			change := fromObj.header
+			change.Action = Removed
+			changes = append(changes, change)
+		case !inFrom:
+			change := toObj.header
+			change.Action = Added
+			changes = append(changes, change)
+		default:
+			var fields []FieldChange
+			compare("", fromObj.content, toObj.content, &fields)
+			if len(fields) == 0 {
+				continue
+			}
+			change := toObj.header
+			change.Action = Changed
+			change.Fields = fields
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// compare appends the differences between two values to the list of field changes
+func compare(path string, from, to interface{}, fields *[]FieldChange) {
+	switch fromValue := from.(type) {
+	case map[string]interface{}:
+		if toValue, ok := to.(map[string]interface{}); ok {
+			compareMaps(path, fromValue, toValue, fields)
+			return
+		}
+	case []interface{}:
+		if toValue, ok := to.([]interface{}); ok {
+			compareLists(path, fromValue, toValue, fields)
+			return
+		}
+	}
+	if !reflect.DeepEqual(from, to) {
+		*fields = append(*fields, FieldChange{Action: Changed, Path: path, From: from, To: to})
+	}
+}
+
+func compareMaps(path string, from, to map[string]interface{}, fields *[]FieldChange) {
+	keys := make([]string, 0, len(from)+len(to))
+	for key := range from {
+		keys = append(keys, key)
+	}
+	for key := range to {
+		if _, exists := from[key]; !exists {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fromValue, inFrom := from[key]
+		toValue, inTo := to[key]
+		keyPath := fieldPath(path, key)
+		switch {
+		case !inTo:
+			*fields = append(*fields, FieldChange{Action: Removed, Path: keyPath, From: fromValue})
+		case !inFrom:
+			*fields = append(*fields, FieldChange{Action: Added, Path: keyPath, To: toValue})
+		default:
+			compare(keyPath, fromValue, toValue, fields)
+		}
+	}
+}
+
+// compareLists matches elements of the lists by name if every element of both lists is a map with
+// unique name, e.g. containers or volumes, otherwise elements are compared by index
+func compareLists(path string, from, to []interface{}, fields *[]FieldChange) {
+	fromNamed, fromKeyed := namedElements(from)
+	toNamed, toKeyed := namedElements(to)
+	if fromKeyed && toKeyed {
+		names := make([]string, 0, len(fromNamed)+len(toNamed))
+		for name := range fromNamed {
+			names = append(names, name)
+		}
+		for name := range toNamed {
+			if _, exists := fromNamed[name]; !exists {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			fromValue, inFrom := fromNamed[name]
+			toValue, inTo := toNamed[name]
+			namePath := fmt.Sprintf("%s[name=%s]", path, name)
+			switch {
+			case !inTo:
+				*fields = append(*fields, FieldChange{Action: Removed, Path: namePath, From: fromValue})
+			case !inFrom:
+				*fields = append(*fields, FieldChange{Action: Added, Path: namePath, To: toValue})
+			default:
+				compare(namePath, fromValue, toValue, fields)
+			}
+		}
+		return
+	}
+
+	for i := 0; i < len(from) || i < len(to); i++ {
+		indexPath := fmt.Sprintf("%s[%d]", path, i)
+		switch {
+		case i >= len(to):
+			*fields = append(*fields, FieldChange{Action: Removed, Path: indexPath, From: from[i]})
+		case i >= len(from):
+			*fields = append(*fields, FieldChange{Action: Added, Path: indexPath, To: to[i]})
+		default:
+			compare(indexPath, from[i], to[i], fields)
+		}
+	}
+}
+
+// namedElements indexes elements of the list by name, false is returned if the list is empty or
+// if any of its elements isn't a map with unique string name
+func namedElements(list []interface{}) (map[string]interface{}, bool) {
+	if len(list) == 0 {
+		return nil, false
+	}
+	named := make(map[string]interface{}, len(list))
+	for _, elem := range list {
+		m, ok := elem.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		name, ok := m["name"].(string)
+		if !ok {
+			return nil, false
+		}
+		if _, exists := named[name]; exists {
+			return nil, false
+		}
+		named[name] = elem
+	}
+	return named, true
+}
+
+func fieldPath(parent, key string) string {
+	if !simpleKey.MatchString(key) {
+		return parent + "[" + strconv.Quote(key) + "]"
+	}
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// mask drops SOPS metadata and replaces encrypted values and the data of the Secrets with the
+// placeholder, since they differ on each generation or encryption even if the secrets are the same
+func mask(kind string, content map[string]interface{}) map[string]interface{} {
+	delete(content, sopsMetadataKey)
+	masked := maskEncrypted(content).(map[string]interface{})
+	if kind != document.SecretKind {
+		return masked
+	}
+	for _, key := range []string{"data", "stringData"} {
+		data, ok := masked[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for name := range data {
+			data[name] = MaskedValue
+		}
+	}
+	return masked
+}
+
+func maskEncrypted(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, elem := range v {
+			v[key] = maskEncrypted(elem)
+		}
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = maskEncrypted(elem)
+		}
+	case string:
+		if encryptedValue.MatchString(v) {
+			return MaskedValue
+		}
+	}
+	return value
+}
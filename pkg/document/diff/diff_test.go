@@ -0,0 +1,383 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package diff_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/document/diff"
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+const (
+	fromDocs = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: frontend
+  annotations:
+    airshipit.org/owner: ui
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.20
+      - name: envoy
+        image: envoy:1.18
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: frontend
+data:
+  mode: production
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: credentials
+  namespace: frontend
+data:
+  password: Zmlyc3Q=
+`
+	toDocs = `apiVersion: v1
+kind: Secret
+metadata:
+  name: credentials
+  namespace: frontend
+data:
+  password: c2Vjb25k
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: frontend
+  labels:
+    app: web
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: envoy
+        image: envoy:1.18
+      - name: nginx
+        image: nginx:1.21
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cluster-settings
+data:
+  mode: production
+`
+)
+
+func newBundle(t *testing.T, data string) document.Bundle {
+	bundle, err := document.NewBundleFromBytes([]byte(data))
+	require.NoError(t, err)
+	return bundle
+}
+
+func TestDiff(t *testing.T) {
+	from := newBundle(t, fromDocs)
+	to := newBundle(t, toDocs)
+
+	changes, err := diff.Diff(from, to, diff.Options{})
+	require.NoError(t, err)
+	assert.Equal(t, []diff.Change{
+		{Action: diff.Added, APIVersion: "v1", Kind: "ConfigMap", Name: "cluster-settings"},
+		{Action: diff.Removed, APIVersion: "v1", Kind: "ConfigMap", Namespace: "frontend", Name: "settings"},
+		{
+			Action:     diff.Changed,
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Namespace:  "frontend",
+			Name:       "web",
+			Fields: []diff.FieldChange{
+				{Action: diff.Removed, Path: "metadata.annotations", From: map[string]interface{}{
+					"airshipit.org/owner": "ui"}},
+				{Action: diff.Added, Path: "metadata.labels", To: map[string]interface{}{"app": "web"}},
+				{Action: diff.Changed, Path: "spec.replicas", From: float64(1), To: float64(2)},
+				{Action: diff.Changed, Path: "spec.template.spec.containers[name=nginx].image",
+					From: "nginx:1.20", To: "nginx:1.21"},
+			},
+		},
+	}, changes)
+
+	changes, err = diff.Diff(from, to, diff.Options{ShowSecrets: true})
+	require.NoError(t, err)
+	require.Len(t, changes, 4)
+	assert.Equal(t, []diff.FieldChange{
+		{Action: diff.Changed, Path: "data.password", From: "Zmlyc3Q=", To: "c2Vjb25k"},
+	}, changes[3].Fields)
+}
+
+func TestDiffEncrypted(t *testing.T) {
+	encrypted := `apiVersion: airshipit.org/v1alpha1
+kind: VariableCatalogue
+metadata:
+  name: generated
+password: ENC[AES256_GCM,data:%s,iv:abc,tag:def,type:str]
+list:
+- ENC[AES256_GCM,data:%s,iv:abc,tag:def,type:str]
+sops:
+  lastmodified: "%s"
+`
+	from := newBundle(t, fmt.Sprintf(encrypted, "first", "first", "2021-01-01T00:00:00Z"))
+	to := newBundle(t, fmt.Sprintf(encrypted, "second", "second", "2021-02-01T00:00:00Z"))
+
+	changes, err := diff.Diff(from, to, diff.Options{})
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	changes, err = diff.Diff(from, to, diff.Options{ShowSecrets: true})
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Len(t, changes[0].Fields, 3)
+}
+
+func TestWriteChanges(t *testing.T) {
+	changes := []diff.Change{
+		{Action: diff.Added, APIVersion: "v1", Kind: "ConfigMap", Name: "cluster-settings"},
+		{
+			Action:     diff.Changed,
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Namespace:  "frontend",
+			Name:       "web",
+			Fields: []diff.FieldChange{
+				{Action: diff.Added, Path: "metadata.labels", To: map[string]interface{}{"app": "web"}},
+				{Action: diff.Changed, Path: "spec.replicas", From: 1, To: 2},
+				{Action: diff.Removed, Path: "spec.paused", From: true},
+			},
+		},
+	}
+
+	testCases := []struct {
+		format   string
+		changes  []diff.Change
+		expected string
+	}{
+		{
+			format:  diff.TextOutputFormat,
+			changes: changes,
+			expected: `+ v1 ConfigMap cluster-settings
+~ apps/v1 Deployment frontend/web
+    + metadata.labels: {"app":"web"}
+    ~ spec.replicas: 1 -> 2
+    - spec.paused: true
+`,
+		},
+		{
+			format:  diff.YAMLOutputFormat,
+			changes: changes[:1],
+			expected: `- action: added
+  apiVersion: v1
+  kind: ConfigMap
+  name: cluster-settings
+`,
+		},
+		{
+			format:  diff.JSONOutputFormat,
+			changes: changes[1:],
+			expected: `[
+  {
+    "action": "changed",
+    "apiVersion": "apps/v1",
+    "kind": "Deployment",
+    "namespace": "frontend",
+    "name": "web",
+    "fields": [
+      {
+        "action": "added",
+        "path": "metadata.labels",
+        "to": {
+          "app": "web"
+        }
+      },
+      {
+        "action": "changed",
+        "path": "spec.replicas",
+        "from": 1,
+        "to": 2
+      },
+      {
+        "action": "removed",
+        "path": "spec.paused",
+        "from": true
+      }
+    ]
+  }
+]
+`,
+		},
+		{
+			format:   diff.JSONOutputFormat,
+			expected: "[]\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.format, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			require.NoError(t, diff.WriteChanges(buf, tc.format, tc.changes))
+			assert.Equal(t, tc.expected, buf.String())
+		})
+	}
+
+	assert.Equal(t, diff.ErrInvalidFormat{RequestedFormat: "xml"}, diff.WriteChanges(&bytes.Buffer{}, "xml", nil))
+}
+
+func TestRender(t *testing.T) {
+	targetPath, cleanup := testutil.TempDir(t, "airshipctl-diff-test")
+	defer cleanup(t)
+	repoPath := filepath.Join(targetPath, "phases")
+	entrypoint := filepath.Join("site", "web")
+
+	r, err := git.PlainInit(repoPath, false)
+	require.NoError(t, err)
+	tree, err := r.Worktree()
+	require.NoError(t, err)
+	commit := func(docs string) {
+		require.NoError(t, os.MkdirAll(filepath.Join(repoPath, entrypoint), 0700))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(repoPath, entrypoint, "kustomization.yaml"),
+			[]byte("resources:\n- docs.yaml\n"), 0600))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(repoPath, entrypoint, "docs.yaml"), []byte(docs), 0600))
+		_, err = tree.Add(".")
+		require.NoError(t, err)
+		_, err = tree.Commit("update documents", &git.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+		})
+		require.NoError(t, err)
+	}
+	commit(fromDocs)
+	commit(toDocs)
+	// uncommitted changes are used only if revision is not set
+	require.NoError(t, ioutil.WriteFile(filepath.Join(repoPath, entrypoint, "docs.yaml"),
+		[]byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: local\n"), 0600))
+
+	workDir, cleanupWorkDir := testutil.TempDir(t, "airshipctl-diff-work")
+	defer cleanupWorkDir(t)
+
+	testCases := []struct {
+		revision string
+		names    []string
+	}{
+		{revision: "HEAD~1", names: []string{"web", "settings", "credentials"}},
+		{revision: "master", names: []string{"credentials", "web", "cluster-settings"}},
+		{revision: "", names: []string{"local"}},
+	}
+	for i, tc := range testCases {
+		bundle, err := diff.Render(repoPath, tc.revision, entrypoint, filepath.Join(workDir, string(rune('a'+i))))
+		require.NoError(t, err)
+		docs, err := bundle.GetAllDocuments()
+		require.NoError(t, err)
+		names := make([]string, 0, len(docs))
+		for _, doc := range docs {
+			names = append(names, doc.GetName())
+		}
+		assert.ElementsMatch(t, tc.names, names, tc.revision)
+	}
+
+	_, err = diff.Render(repoPath, "no-such-branch", entrypoint, filepath.Join(workDir, "missing"))
+	assert.IsType(t, diff.ErrRevisionNotFound{}, err)
+}
+
+func TestRenderSiblings(t *testing.T) {
+	targetPath, cleanup := testutil.TempDir(t, "airshipctl-diff-test")
+	defer cleanup(t)
+	writeFile := func(path, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	}
+	commit := func(repoPath, tag string) {
+		r, err := git.PlainOpen(repoPath)
+		require.NoError(t, err)
+		tree, err := r.Worktree()
+		require.NoError(t, err)
+		_, err = tree.Add(".")
+		require.NoError(t, err)
+		hash, err := tree.Commit("update documents", &git.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+		})
+		require.NoError(t, err)
+		_, err = r.CreateTag(tag, hash, nil)
+		require.NoError(t, err)
+	}
+
+	// phase repository refers to the documents of the sibling repository
+	repoPath := filepath.Join(targetPath, "phases")
+	entrypoint := filepath.Join("site", "web")
+	_, err := git.PlainInit(repoPath, false)
+	require.NoError(t, err)
+	writeFile(filepath.Join(repoPath, entrypoint, "kustomization.yaml"), "resources:\n- ../../../shared/docs\n")
+	commit(repoPath, "v1")
+
+	sharedPath := filepath.Join(targetPath, "shared")
+	_, err = git.PlainInit(sharedPath, false)
+	require.NoError(t, err)
+	writeFile(filepath.Join(sharedPath, "docs", "kustomization.yaml"), "resources:\n- docs.yaml\n")
+	configMap := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: %s\n"
+	writeFile(filepath.Join(sharedPath, "docs", "docs.yaml"), fmt.Sprintf(configMap, "tagged"))
+	commit(sharedPath, "v1")
+	writeFile(filepath.Join(sharedPath, "docs", "docs.yaml"), fmt.Sprintf(configMap, "current"))
+
+	workDir, cleanupWorkDir := testutil.TempDir(t, "airshipctl-diff-work")
+	defer cleanupWorkDir(t)
+	buf := &bytes.Buffer{}
+	log.Init(false, buf)
+	defer log.Init(false, os.Stderr)
+
+	testCases := []struct {
+		revision string
+		name     string
+		warning  bool
+	}{
+		// sibling repository is checked out at the tag with the same name
+		{revision: "v1", name: "tagged"},
+		// revision which is specific to the phase repository, sibling is used as is
+		{revision: "HEAD", name: "current", warning: true},
+	}
+	for i, tc := range testCases {
+		buf.Reset()
+		bundle, err := diff.Render(repoPath, tc.revision, entrypoint, filepath.Join(workDir, string(rune('a'+i))))
+		require.NoError(t, err)
+		docs, err := bundle.GetAllDocuments()
+		require.NoError(t, err)
+		require.Len(t, docs, 1)
+		assert.Equal(t, tc.name, docs[0].GetName(), tc.revision)
+		assert.Equal(t, tc.warning, strings.Contains(buf.String(), "Revision HEAD is not found in shared"), buf.String())
+	}
+}
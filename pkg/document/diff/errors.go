@@ -0,0 +1,61 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package diff
+
+import (
+	"fmt"
+)
+
+// ErrInvalidFormat is returned when unsupported output format is requested
+type ErrInvalidFormat struct {
+	RequestedFormat string
+}
+
+func (e ErrInvalidFormat) Error() string {
+	return fmt.Sprintf("invalid output format specified %s. Allowed values are text|yaml|json",
+		e.RequestedFormat)
+}
+
+// ErrMissingRevision is returned when the revision to compare from is not set
+type ErrMissingRevision struct{}
+
+func (e ErrMissingRevision) Error() string {
+	return "revision to compare from is required, specify it with --from flag"
+}
+
+// ErrRevisionNotFound is returned when revision can't be resolved in the phase repository
+type ErrRevisionNotFound struct {
+	Revision string
+	Err      error
+}
+
+func (e ErrRevisionNotFound) Error() string {
+	return fmt.Sprintf("failed to resolve revision %s: %v", e.Revision, e.Err)
+}
+
+// Unwrap returns the error of revision resolution
+func (e ErrRevisionNotFound) Unwrap() error {
+	return e.Err
+}
+
+// ErrEntrypointOutsideRepo is returned when the phase document entrypoint is not a part of the phase repository
+type ErrEntrypointOutsideRepo struct {
+	Entrypoint string
+	RepoPath   string
+}
+
+func (e ErrEntrypointOutsideRepo) Error() string {
+	return fmt.Sprintf("entrypoint %s is outside of the phase repository %s", e.Entrypoint, e.RepoPath)
+}
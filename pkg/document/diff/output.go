@@ -0,0 +1,113 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// TextOutputFormat prints changes in a human readable form similar to unified diff
+	TextOutputFormat = "text"
+	// YAMLOutputFormat prints changes as a YAML list
+	YAMLOutputFormat = "yaml"
+	// JSONOutputFormat prints changes as a JSON list
+	JSONOutputFormat = "json"
+)
+
+var actionMarks = map[Action]string{
+	Added:   "+",
+	Removed: "-",
+	Changed: "~",
+}
+
+// WriteChanges prints changes in the requested format
+func WriteChanges(w io.Writer, format string, changes []Change) error {
+	if changes == nil {
+		changes = []Change{}
+	}
+	switch format {
+	case TextOutputFormat:
+		return writeText(w, changes)
+	case YAMLOutputFormat:
+		out, err := yaml.Marshal(changes)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(out)
+		return err
+	case JSONOutputFormat:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(changes)
+	default:
+		return ErrInvalidFormat{RequestedFormat: format}
+	}
+}
+
+func writeText(w io.Writer, changes []Change) error {
+	for _, change := range changes {
+		name := change.Name
+		if change.Namespace != "" {
+			name = change.Namespace + "/" + name
+		}
+		if _, err := fmt.Fprintf(w, "%s %s %s %s\n",
+			actionMarks[change.Action], change.APIVersion, change.Kind, name); err != nil {
+			return err
+		}
+		for _, field := range change.Fields {
+			line, err := fieldLine(field)
+			if err != nil {
+				return err
+			}
+			if _, err = fmt.Fprintf(w, "    %s %s\n", actionMarks[field.Action], line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func fieldLine(field FieldChange) (string, error) {
+	from, err := textValue(field.From)
+	if err != nil {
+		return "", err
+	}
+	to, err := textValue(field.To)
+	if err != nil {
+		return "", err
+	}
+	switch field.Action {
+	case Added:
+		return fmt.Sprintf("%s: %s", field.Path, to), nil
+	case Removed:
+		return fmt.Sprintf("%s: %s", field.Path, from), nil
+	default:
+		return fmt.Sprintf("%s: %s -> %s", field.Path, from, to), nil
+	}
+}
+
+// textValue prints strings as is and other values as a single line JSON
+func textValue(value interface{}) (string, error) {
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	out, err := json.Marshal(value)
+	return string(out), err
+}
@@ -0,0 +1,174 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package diff
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/document/repo"
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+// sourceRefSpecs fetch all refs of the phase repository to the temporary clone, so that revisions
+// reachable only from remote branches or detached HEAD of the phase repository can be checked out
+var sourceRefSpecs = []gitconfig.RefSpec{
+	"+refs/*:refs/source/*",
+	"+HEAD:refs/source/HEAD",
+}
+
+// Render builds the bundle of the entrypoint at the given revision of the repository. The revision
+// is checked out to a clone of the repository inside of the work directory, the other directories
+// located next to the repository are added to the work directory as well, so that kustomizations
+// may refer to them. The repository is used as is if the revision is empty
+func Render(repoPath, revision, entrypoint, workDir string) (document.Bundle, error) {
+	if revision == "" {
+		return document.NewBundleByPath(filepath.Join(repoPath, entrypoint))
+	}
+	repoPath, err := filepath.Abs(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	source, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := source.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return nil, ErrRevisionNotFound{Revision: revision, Err: err}
+	}
+	if err = os.MkdirAll(workDir, 0700); err != nil {
+		return nil, err
+	}
+	if err = addSiblings(repoPath, revision, workDir); err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Checking out revision %s (%s) of repository %s", revision, hash, repoPath)
+	name, err := checkout(repoPath, *hash, workDir)
+	if err != nil {
+		return nil, err
+	}
+	return document.NewBundleByPath(filepath.Join(workDir, name, entrypoint))
+}
+
+// addSiblings adds the directories located next to the repository to the work directory. Sibling
+// git repositories which have the tag or the branch named as the revision are checked out at it,
+// the rest of the siblings are linked, so their current content is used and a warning is printed
+func addSiblings(repoPath, revision, workDir string) error {
+	parent, repoDir := filepath.Split(filepath.Clean(repoPath))
+	siblings, err := ioutil.ReadDir(parent)
+	if err != nil {
+		return err
+	}
+	var linked []string
+	for _, sibling := range siblings {
+		if sibling.Name() == repoDir {
+			continue
+		}
+		path := filepath.Join(parent, sibling.Name())
+		if sibling.IsDir() {
+			if hash, ok := matchingRevision(path, revision); ok {
+				log.Debugf("Checking out revision %s (%s) of repository %s", revision, hash, path)
+				if _, err = checkout(path, hash, workDir); err != nil {
+					return err
+				}
+				continue
+			}
+			linked = append(linked, sibling.Name())
+		}
+		if err = os.Symlink(path, filepath.Join(workDir, sibling.Name())); err != nil {
+			return err
+		}
+	}
+	if len(linked) > 0 {
+		log.Printf("Revision %s is not found in %s located next to the phase repository, "+
+			"their current content is used", revision, strings.Join(linked, ", "))
+	}
+	return nil
+}
+
+// matchingRevision resolves the revision in the git repository if it's the name of the tag or the branch,
+// other revisions, e.g. commit hashes or HEAD~1, are specific to the phase repository
+func matchingRevision(repoPath, revision string) (plumbing.Hash, bool) {
+	r, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return plumbing.ZeroHash, false
+	}
+	for _, ref := range []plumbing.ReferenceName{
+		plumbing.NewTagReferenceName(revision),
+		plumbing.NewBranchReferenceName(revision),
+		plumbing.NewRemoteReferenceName(git.DefaultRemoteName, revision),
+	} {
+		if hash, err := r.ResolveRevision(plumbing.Revision(ref)); err == nil {
+			return *hash, true
+		}
+	}
+	return plumbing.ZeroHash, false
+}
+
+// checkout clones the local repository to the work directory and checks out the commit,
+// name of the clone directory is returned
+func checkout(repoPath string, hash plumbing.Hash, workDir string) (string, error) {
+	clone, err := repo.NewRepository(workDir, &revisionOptions{url: repoPath, hash: hash})
+	if err != nil {
+		return "", err
+	}
+	return clone.Name, clone.Download(false)
+}
+
+// revisionOptions clones the local repository and checks out the commit
+type revisionOptions struct {
+	url  string
+	hash plumbing.Hash
+}
+
+func (o *revisionOptions) ToAuth() (transport.AuthMethod, error) {
+	return nil, nil
+}
+
+func (o *revisionOptions) ToCloneOptions(auth transport.AuthMethod) *git.CloneOptions {
+	return &git.CloneOptions{
+		Auth:       auth,
+		URL:        o.url,
+		NoCheckout: true,
+	}
+}
+
+func (o *revisionOptions) ToCheckoutOptions() *git.CheckoutOptions {
+	return &git.CheckoutOptions{
+		Hash:  o.hash,
+		Force: true,
+	}
+}
+
+func (o *revisionOptions) ToFetchOptions(auth transport.AuthMethod) *git.FetchOptions {
+	return &git.FetchOptions{
+		Auth:     auth,
+		RefSpecs: sourceRefSpecs,
+	}
+}
+
+func (o *revisionOptions) URL() string {
+	return o.url
+}
@@ -66,6 +66,27 @@ func TestDocHelpers(t *testing.T) {
 		assert.Equal(bmcUsername, "username")
 		assert.Equal(bmcPassword, "password")
 	})
+
+	t.Run("ResourceName", func(t *testing.T) {
+		selector := document.NewSelector().ByKind("Secret").ByName("master-0-bmc")
+		doc, err := bundle.SelectOne(selector)
+		require.NoError(err)
+		assert.Equal("Secret/metal3/master-0-bmc", document.ResourceName(doc))
+
+		doc, err = bundle.SelectOne(document.NewSelector().ByKind("BareMetalHost"))
+		require.NoError(err)
+		assert.Equal("BareMetalHost/master-0", document.ResourceName(doc))
+	})
+
+	t.Run("APIVersion", func(t *testing.T) {
+		doc, err := bundle.SelectOne(document.NewSelector().ByKind("Secret").ByName("master-0-bmc"))
+		require.NoError(err)
+		assert.Equal("v1", document.APIVersion(doc))
+
+		doc, err = bundle.SelectOne(document.NewSelector().ByKind("BareMetalHost"))
+		require.NoError(err)
+		assert.Equal("metal3.io/v1alpha1", document.APIVersion(doc))
+	})
 }
 
 func TestDocHelpersNegativeCases(t *testing.T) {
@@ -16,8 +16,26 @@ package document
 
 import (
 	b64 "encoding/base64"
+	"fmt"
 )
 
+// ResourceName returns kind/namespace/name reference of the document, or kind/name
+// for cluster scoped documents, which is used to point at it in validation messages
+func ResourceName(doc Document) string {
+	if doc.GetNamespace() == "" {
+		return fmt.Sprintf("%s/%s", doc.GetKind(), doc.GetName())
+	}
+	return fmt.Sprintf("%s/%s/%s", doc.GetKind(), doc.GetNamespace(), doc.GetName())
+}
+
+// APIVersion returns apiVersion of the document, i.e. group/version, or version only for the core group
+func APIVersion(doc Document) string {
+	if doc.GetGroup() == "" {
+		return doc.GetVersion()
+	}
+	return doc.GetGroup() + "/" + doc.GetVersion()
+}
+
 // GetSecretDataKey understands how to retrieve a specific top level key from a secret
 // that may have the data stored under a data or stringData field in which
 // case the key may be base64 encoded or it may be plain text
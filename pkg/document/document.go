@@ -16,13 +16,19 @@ package document
 
 import (
 	"fmt"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer/json"
 
+	"sigs.k8s.io/kustomize/api/filters/patchjson6902"
+	"sigs.k8s.io/kustomize/api/filters/patchstrategicmerge"
 	"sigs.k8s.io/kustomize/api/hasher"
 	"sigs.k8s.io/kustomize/api/resource"
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
 	"sigs.k8s.io/yaml"
+
+	"opendev.org/airship/airshipctl/pkg/document/plugin/kyamlutils"
 )
 
 // Factory holds document data
@@ -34,6 +40,7 @@ type Factory struct {
 type Document interface {
 	Annotate(map[string]string)
 	AsYAML() ([]byte, error)
+	DeleteField(path string) error
 	GetAnnotations() map[string]string
 	GetBool(path string) (bool, error)
 	GetFloat64(path string) (float64, error)
@@ -50,8 +57,12 @@ type Document interface {
 	GetStringMap(path string) (map[string]string, error)
 	GetStringSlice(path string) ([]string, error)
 	GetVersion() string
+	JSONPatch(patch []byte) error
 	Label(map[string]string)
 	MarshalJSON() ([]byte, error)
+	MergePatch(patch []byte) error
+	SetField(path string, value interface{}) error
+	StrategicMergePatch(patch []byte) error
 	ToObject(interface{}) error
 	ToAPIObject(runtime.Object, *runtime.Scheme) error
 }
@@ -97,8 +108,19 @@ func (d *Factory) GetNamespace() string {
 
 // GetString returns the string value at path.
 func (d *Factory) GetString(path string) (string, error) {
-	r := d.GetKustomizeResource()
-	return r.GetString(path)
+	if !isJSONPath(path) {
+		r := d.GetKustomizeResource()
+		return r.GetString(path)
+	}
+	val, err := d.GetFieldValue(path)
+	if err != nil {
+		return "", err
+	}
+	result, ok := val.(string)
+	if !ok {
+		return "", ErrBadValueFormat{Value: path, Expected: "string", Actual: fmt.Sprintf("%T", val)}
+	}
+	return result, nil
 }
 
 // GetStringSlice returns a string slice at path.
@@ -166,8 +188,19 @@ func (d *Factory) GetInt64(path string) (int64, error) {
 
 // GetSlice returns a slice at path.
 func (d *Factory) GetSlice(path string) ([]interface{}, error) {
-	r := d.GetKustomizeResource()
-	return r.GetSlice(path)
+	if !isJSONPath(path) {
+		r := d.GetKustomizeResource()
+		return r.GetSlice(path)
+	}
+	val, err := d.GetFieldValue(path)
+	if err != nil {
+		return nil, err
+	}
+	result, ok := val.([]interface{})
+	if !ok {
+		return nil, ErrBadValueFormat{Value: path, Expected: "[]interface{}", Actual: fmt.Sprintf("%T", val)}
+	}
+	return result, nil
 }
 
 // GetStringMap returns a string map at path.
@@ -273,10 +306,176 @@ func (d *Factory) ToAPIObject(obj runtime.Object, scheme *runtime.Scheme) error
 	return err
 }
 
-// GetFieldValue get object at path
+// GetFieldValue get object at path. Paths enclosed in curly braces are JSON path queries,
+// e.g. {.spec.containers[?(@.name =~ /^nginx/)].image}, see kyamlutils.JSONPathFilter
 func (d *Factory) GetFieldValue(path string) (interface{}, error) {
-	r := d.GetKustomizeResource()
-	return r.GetFieldValue(path)
+	if !isJSONPath(path) {
+		r := d.GetKustomizeResource()
+		return r.GetFieldValue(path)
+	}
+	data, err := d.AsYAML()
+	if err != nil {
+		return nil, err
+	}
+	rn, err := kyaml.Parse(string(data))
+	if err != nil {
+		return nil, err
+	}
+	node, err := rn.Pipe(kyamlutils.JSONPathFilter{Path: path})
+	if err != nil {
+		return nil, err
+	}
+	if node == nil {
+		return nil, ErrDocumentDataKeyNotFound{DocName: d.GetName(), Key: path}
+	}
+	var val interface{}
+	return val, node.YNode().Decode(&val)
+}
+
+// SetField sets the value of the fields identified by path, missing fields are created unless
+// path contains wildcards or filters. Path has the same format as for GetFieldValue, value is
+// any yaml compatible object. Comments of the replaced fields are kept
+func (d *Factory) SetField(path string, value interface{}) error {
+	node, err := newNode(value)
+	if err != nil {
+		return err
+	}
+	updated := 0
+	_, err = d.RNode.Pipe(kyamlutils.JSONPathFilter{
+		Path: path,
+		Mutator: func(rns []*kyaml.RNode) error {
+			for _, rn := range rns {
+				setNode(rn.YNode(), copyNode(node))
+			}
+			updated += len(rns)
+			return nil
+		},
+		// fields can't be created for all elements matched by the wildcard or filter
+		Create: !strings.ContainsAny(path, "*?="),
+	})
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return ErrDocumentDataKeyNotFound{DocName: d.GetName(), Key: path}
+	}
+	return nil
+}
+
+// DeleteField removes the fields or list elements identified by path from the document
+func (d *Factory) DeleteField(path string) error {
+	targets, err := d.queryNodes(path)
+	if err != nil {
+		return err
+	}
+	parentPath, isRoot := parentFieldPath(path)
+	parents := []*kyaml.Node{d.RNode.YNode()}
+	if !isRoot {
+		if parents, err = d.queryNodes(parentPath); err != nil {
+			return err
+		}
+	}
+
+	isTarget := make(map[*kyaml.Node]bool, len(targets))
+	for _, target := range targets {
+		isTarget[target] = true
+	}
+	deleted := 0
+	for _, parent := range parents {
+		var content []*kyaml.Node
+		switch parent.Kind {
+		case kyaml.MappingNode:
+			for i := 0; i+1 < len(parent.Content); i += 2 {
+				if isTarget[parent.Content[i+1]] {
+					deleted++
+					continue
+				}
+				content = append(content, parent.Content[i], parent.Content[i+1])
+			}
+		case kyaml.SequenceNode:
+			for _, item := range parent.Content {
+				if isTarget[item] {
+					deleted++
+					continue
+				}
+				content = append(content, item)
+			}
+		default:
+			continue
+		}
+		parent.Content = content
+	}
+	if deleted == 0 {
+		return ErrDocumentDataKeyNotFound{DocName: d.GetName(), Key: path}
+	}
+	return nil
+}
+
+// queryNodes returns all yaml nodes of the document identified by path
+func (d *Factory) queryNodes(path string) ([]*kyaml.Node, error) {
+	var nodes []*kyaml.Node
+	_, err := d.RNode.Pipe(kyamlutils.JSONPathFilter{
+		Path: path,
+		Mutator: func(rns []*kyaml.RNode) error {
+			for _, rn := range rns {
+				nodes = append(nodes, rn.YNode())
+			}
+			return nil
+		},
+	})
+	return nodes, err
+}
+
+// MergePatch applies JSON merge patch (RFC 7386) to the document, patch can be either JSON
+// or YAML. Fields which are not changed by the patch keep their comments
+func (d *Factory) MergePatch(patch []byte) error {
+	rn, err := kyaml.Parse(string(patch))
+	if err != nil {
+		return err
+	}
+	if rn.YNode().Kind != kyaml.MappingNode {
+		return ErrBadPatch{DocName: d.GetName(), Type: MergePatchType, Message: "patch must be a map"}
+	}
+	mergePatch(d.RNode.YNode(), rn.YNode())
+	return nil
+}
+
+// StrategicMergePatch applies strategic merge patch to the document the same way kustomize
+// patchesStrategicMerge does, patch can be either JSON or YAML
+func (d *Factory) StrategicMergePatch(patch []byte) error {
+	rn, err := kyaml.Parse(string(patch))
+	if err != nil {
+		return err
+	}
+	result, err := patchstrategicmerge.Filter{Patch: rn}.Filter([]*kyaml.RNode{d.RNode.Copy()})
+	if err != nil {
+		return err
+	}
+	if len(result) == 0 || result[0].IsNilOrEmpty() {
+		return ErrBadPatch{
+			DocName: d.GetName(),
+			Type:    StrategicMergePatchType,
+			Message: "patch deletes the document, remove it from the bundle instead",
+		}
+	}
+	d.RNode.SetYNode(result[0].YNode())
+	return nil
+}
+
+// JSONPatch applies JSON patch (RFC 6902) to the document the same way kustomize patchesJson6902 does,
+// patch can be either JSON or YAML. The document is converted to JSON to apply the patch, so comments
+// and order of the fields are not kept. Document stays intact if any of the operations fails
+func (d *Factory) JSONPatch(patch []byte) error {
+	root := d.RNode.Copy()
+	if _, err := (patchjson6902.Filter{Patch: string(patch)}).Filter([]*kyaml.RNode{root}); err != nil {
+		return ErrBadPatch{DocName: d.GetName(), Type: JSONPatchType, Message: err.Error()}
+	}
+	d.RNode.SetYNode(root.YNode())
+	return nil
+}
+
+func isJSONPath(path string) bool {
+	return strings.HasPrefix(strings.TrimSpace(path), "{")
 }
 
 // NewDocument is a convenience method to construct a new Document.  Although
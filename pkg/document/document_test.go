@@ -244,6 +244,21 @@ func TestDocument(t *testing.T) {
 		require.NoError(err, "Unexpected error trying to GetMap")
 		assert.NotNil(gotMap)
 	})
+	t.Run("GetFieldValueJSONPath", func(t *testing.T) {
+		doc, err := bundle.GetByName("some-random-deployment-we-will-filter")
+		require.NoError(err, "Unexpected error trying to GetByName")
+		name, err := doc.GetString("{.metadata.name}")
+		require.NoError(err, "Unexpected error trying to GetString")
+		assert.Equal("some-random-deployment-we-will-filter", name)
+		args, err := doc.GetSlice("{.spec.template.spec.containers[?(length(@.args) == 1)].args}")
+		require.NoError(err, "Unexpected error trying to GetSlice")
+		assert.Equal([]interface{}{"foobar"}, args)
+		_, err = doc.GetFieldValue("{.spec.missing}")
+		assert.Equal(document.ErrDocumentDataKeyNotFound{
+			DocName: "some-random-deployment-we-will-filter",
+			Key:     "{.spec.missing}",
+		}, err)
+	})
 }
 
 func TestNewDocumentFromBytes(t *testing.T) {
@@ -288,3 +303,227 @@ stringData:
 		})
 	}
 }
+
+const mutationTestDocument = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  # labels of the deployment
+  labels:
+    app: nginx
+spec:
+  replicas: 1 # single replica
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.19
+      - name: sidecar
+        image: busybox
+`
+
+func TestDocumentMutation(t *testing.T) {
+	tests := []struct {
+		name        string
+		mutate      func(document.Document) error
+		expected    map[string]interface{}
+		missing     []string
+		expectedErr error
+	}{
+		{
+			name: "set existing field",
+			mutate: func(doc document.Document) error {
+				return doc.SetField("spec.replicas", 3)
+			},
+			expected: map[string]interface{}{"spec.replicas": 3},
+		},
+		{
+			name: "set missing field",
+			mutate: func(doc document.Document) error {
+				return doc.SetField("{.metadata.annotations.owner}", "airship")
+			},
+			expected: map[string]interface{}{"metadata.annotations": map[string]interface{}{"owner": "airship"}},
+		},
+		{
+			name: "set field of list element",
+			mutate: func(doc document.Document) error {
+				return doc.SetField("spec.template.spec.containers[name=sidecar].image", "busybox:1.33")
+			},
+			expected: map[string]interface{}{
+				"{.spec.template.spec.containers[?(@.name == 'sidecar')].image}": "busybox:1.33",
+				"{.spec.template.spec.containers[?(@.name == 'nginx')].image}":   "nginx:1.19",
+			},
+		},
+		{
+			name: "set map",
+			mutate: func(doc document.Document) error {
+				return doc.SetField("metadata.labels", map[string]string{"tier": "web"})
+			},
+			expected: map[string]interface{}{"metadata.labels": map[string]interface{}{"tier": "web"}},
+		},
+		{
+			name: "set field of missing list element",
+			mutate: func(doc document.Document) error {
+				return doc.SetField("spec.template.spec.containers[name=missing].image", "busybox")
+			},
+			expectedErr: document.ErrDocumentDataKeyNotFound{
+				DocName: "nginx",
+				Key:     "spec.template.spec.containers[name=missing].image",
+			},
+		},
+		{
+			name: "delete field",
+			mutate: func(doc document.Document) error {
+				return doc.DeleteField("metadata.labels.app")
+			},
+			expected: map[string]interface{}{"metadata.labels": map[string]interface{}{}},
+		},
+		{
+			name: "delete list element",
+			mutate: func(doc document.Document) error {
+				return doc.DeleteField("{.spec.template.spec.containers[?(@.name == 'sidecar')]}")
+			},
+			expected: map[string]interface{}{
+				"{.spec.template.spec.containers[*].name}": "nginx",
+			},
+		},
+		{
+			name: "delete top level field",
+			mutate: func(doc document.Document) error {
+				return doc.DeleteField("spec")
+			},
+			missing: []string{"spec"},
+		},
+		{
+			name: "delete missing field",
+			mutate: func(doc document.Document) error {
+				return doc.DeleteField("metadata.annotations")
+			},
+			expectedErr: document.ErrDocumentDataKeyNotFound{DocName: "nginx", Key: "metadata.annotations"},
+		},
+		{
+			name: "merge patch",
+			mutate: func(doc document.Document) error {
+				return doc.MergePatch([]byte(`{"metadata": {"labels": {"app": null, "tier": "web"}}, "spec": {"replicas": 2}}`))
+			},
+			expected: map[string]interface{}{
+				"metadata.labels": map[string]interface{}{"tier": "web"},
+				"spec.replicas":   2,
+				"{.spec.template.spec.containers[?(@.name == 'nginx')].image}": "nginx:1.19",
+			},
+		},
+		{
+			name: "merge patch is not a map",
+			mutate: func(doc document.Document) error {
+				return doc.MergePatch([]byte(`[]`))
+			},
+			expectedErr: document.ErrBadPatch{DocName: "nginx", Type: document.MergePatchType, Message: "patch must be a map"},
+		},
+		{
+			name: "strategic merge patch",
+			mutate: func(doc document.Document) error {
+				return doc.StrategicMergePatch([]byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  template:
+    spec:
+      containers:
+      - name: sidecar
+        image: busybox:1.33
+`))
+			},
+			expected: map[string]interface{}{
+				"{.spec.template.spec.containers[?(@.name == 'sidecar')].image}": "busybox:1.33",
+				"{.spec.template.spec.containers[?(@.name == 'nginx')].image}":   "nginx:1.19",
+			},
+		},
+		{
+			name: "json patch",
+			mutate: func(doc document.Document) error {
+				return doc.JSONPatch([]byte(`[
+{"op": "replace", "path": "/spec/replicas", "value": 5},
+{"op": "add", "path": "/metadata/labels/tier", "value": "web"},
+{"op": "remove", "path": "/spec/template/spec/containers/1"},
+{"op": "copy", "from": "/metadata/name", "path": "/metadata/labels/name"},
+{"op": "move", "from": "/metadata/labels/app", "path": "/metadata/labels/application"},
+{"op": "test", "path": "/metadata/labels/application", "value": "nginx"}
+]`))
+			},
+			expected: map[string]interface{}{
+				"spec.replicas": 5,
+				"metadata.labels": map[string]interface{}{
+					"tier":        "web",
+					"name":        "nginx",
+					"application": "nginx",
+				},
+				"{.spec.template.spec.containers[*].name}": "nginx",
+			},
+		},
+		{
+			name: "json patch failed test keeps document intact",
+			mutate: func(doc document.Document) error {
+				return doc.JSONPatch([]byte(`
+- op: replace
+  path: /spec/replicas
+  value: 5
+- op: test
+  path: /spec/replicas
+  value: 6
+`))
+			},
+			expected: map[string]interface{}{"spec.replicas": 1},
+			expectedErr: document.ErrBadPatch{
+				DocName: "nginx",
+				Type:    document.JSONPatchType,
+				Message: "testing value /spec/replicas failed: test failed",
+			},
+		},
+		{
+			name: "json patch missing path",
+			mutate: func(doc document.Document) error {
+				return doc.JSONPatch([]byte(`[{"op": "remove", "path": "/spec/missing"}]`))
+			},
+			expectedErr: document.ErrBadPatch{
+				DocName: "nginx",
+				Type:    document.JSONPatchType,
+				Message: "error in remove for path: '/spec/missing': Unable to remove nonexistent key: missing: missing value",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := document.NewDocumentFromBytes([]byte(mutationTestDocument))
+			require.NoError(t, err)
+
+			err = tt.mutate(doc)
+			assert.Equal(t, tt.expectedErr, err)
+			for path, expected := range tt.expected {
+				actual, err := doc.GetFieldValue(path)
+				require.NoError(t, err)
+				assert.Equal(t, expected, actual, path)
+			}
+			for _, path := range tt.missing {
+				_, err := doc.GetFieldValue(path)
+				assert.Error(t, err, path)
+			}
+		})
+	}
+}
+
+func TestDocumentMutationKeepsComments(t *testing.T) {
+	doc, err := document.NewDocumentFromBytes([]byte(mutationTestDocument))
+	require.NoError(t, err)
+
+	require.NoError(t, doc.SetField("spec.replicas", 2))
+	require.NoError(t, doc.MergePatch([]byte(`{"metadata": {"labels": {"tier": "web"}}}`)))
+
+	factory, ok := doc.(*document.Factory)
+	require.True(t, ok)
+	out := factory.RNode.MustString()
+	assert.Contains(t, out, "# labels of the deployment\n")
+	assert.Contains(t, out, "replicas: 2 # single replica\n")
+}
@@ -56,6 +56,18 @@ type ErrBadValueFormat struct {
 	Actual   string
 }
 
+// ErrDuplicateDocumentPath returned if two documents of the bundle are written to the same file
+type ErrDuplicateDocumentPath struct {
+	Path string
+}
+
+// ErrBadPatch returned if patch can't be applied to the document
+type ErrBadPatch struct {
+	DocName string
+	Type    string
+	Message string
+}
+
 func (e ErrDocNotFound) Error() string {
 	return fmt.Sprintf("document filtered by selector %v found no documents", e.Selector)
 }
@@ -79,3 +91,11 @@ func (e ErrRuntimeObjectKind) Error() string {
 func (e ErrBadValueFormat) Error() string {
 	return fmt.Sprintf("value of %s expected to have %s type, got %s", e.Value, e.Expected, e.Actual)
 }
+
+func (e ErrDuplicateDocumentPath) Error() string {
+	return fmt.Sprintf("more than one document of the bundle is written to %s", e.Path)
+}
+
+func (e ErrBadPatch) Error() string {
+	return fmt.Sprintf("unable to apply %s patch to document %q: %s", e.Type, e.DocName, e.Message)
+}
@@ -0,0 +1,161 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package document
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"opendev.org/airship/airshipctl/pkg/fs"
+)
+
+// KustomEdge is a reference from the kustomization directory to one of its sources
+type KustomEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Type is the kind of the source, e.g. Resources, Components or Generators
+	Type string `json:"type"`
+}
+
+// KustomGraph is a dependency graph of kustomize entrypoints. Nodes of the graph are kustomization
+// directories and the files they refer to, edges point from the kustomization to its sources
+type KustomGraph struct {
+	fs fs.FileSystem
+	// nodes maps path of the node to true if the node is a kustomization directory
+	nodes map[string]bool
+	edges map[string][]KustomEdge
+}
+
+// NewKustomGraph returns an empty dependency graph which reads kustomizations from the filesystem
+func NewKustomGraph(fSys fs.FileSystem) *KustomGraph {
+	return &KustomGraph{
+		fs:    fSys,
+		nodes: map[string]bool{},
+		edges: map[string][]KustomEdge{},
+	}
+}
+
+// Add adds the kustomization directory and all its sources to the graph recursively
+func (g *KustomGraph) Add(dir string) error {
+	dir = filepath.Clean(dir)
+	if _, exists := g.nodes[dir]; exists {
+		return nil
+	}
+	g.nodes[dir] = true
+
+	resMap, err := MakeResMap(g.fs, filepath.Join(dir, KustomizationFile))
+	if err != nil {
+		return err
+	}
+	sourceTypes := make([]string, 0, len(resMap))
+	for sourceType := range resMap {
+		sourceTypes = append(sourceTypes, sourceType)
+	}
+	sort.Strings(sourceTypes)
+
+	for _, sourceType := range sourceTypes {
+		for _, source := range resMap[sourceType] {
+			g.edges[dir] = append(g.edges[dir], KustomEdge{From: dir, To: source, Type: sourceType})
+			if !g.fs.Exists(filepath.Join(source, KustomizationFile)) {
+				if _, exists := g.nodes[source]; !exists {
+					g.nodes[source] = false
+				}
+				continue
+			}
+			if err = g.Add(source); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Nodes returns sorted paths of all nodes of the graph
+func (g *KustomGraph) Nodes() []string {
+	nodes := make([]string, 0, len(g.nodes))
+	for node := range g.nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// IsKustomization returns true if the node is a kustomization directory
+func (g *KustomGraph) IsKustomization(node string) bool {
+	return g.nodes[filepath.Clean(node)]
+}
+
+// Edges returns all edges of the graph ordered by the source node
+func (g *KustomGraph) Edges() []KustomEdge {
+	var edges []KustomEdge
+	for _, node := range g.Nodes() {
+		edges = append(edges, g.edges[node]...)
+	}
+	return edges
+}
+
+// Dependencies returns sorted paths of the nodes reachable from the node including the node itself
+func (g *KustomGraph) Dependencies(node string) []string {
+	node = filepath.Clean(node)
+	if _, exists := g.nodes[node]; !exists {
+		return nil
+	}
+	return g.walk([]string{node}, func(n string) []string {
+		next := make([]string, 0, len(g.edges[n]))
+		for _, edge := range g.edges[n] {
+			next = append(next, edge.To)
+		}
+		return next
+	})
+}
+
+// Dependents returns sorted paths of the nodes which depend on the path including the matched nodes.
+// The path is either the path of the node or the directory containing nodes, e.g. if the path is
+// manifests/function/capi, nodes depending on any version of capi function are returned
+func (g *KustomGraph) Dependents(path string) []string {
+	path = filepath.Clean(path)
+	reverse := map[string][]string{}
+	var matched []string
+	for _, node := range g.Nodes() {
+		if node == path || strings.HasPrefix(node, path+string(filepath.Separator)) {
+			matched = append(matched, node)
+		}
+		for _, edge := range g.edges[node] {
+			reverse[edge.To] = append(reverse[edge.To], edge.From)
+		}
+	}
+	return g.walk(matched, func(n string) []string { return reverse[n] })
+}
+
+func (g *KustomGraph) walk(start []string, next func(string) []string) []string {
+	visited := map[string]bool{}
+	queue := append([]string{}, start...)
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		if visited[node] {
+			continue
+		}
+		visited[node] = true
+		queue = append(queue, next(node)...)
+	}
+	result := make([]string, 0, len(visited))
+	for node := range visited {
+		result = append(result, node)
+	}
+	sort.Strings(result)
+	return result
+}
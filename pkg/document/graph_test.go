@@ -0,0 +1,94 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package document_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/fs"
+)
+
+func TestKustomGraph(t *testing.T) {
+	graph := document.NewKustomGraph(fs.NewDocumentFs())
+	require.NoError(t, graph.Add("testdata/workers-targetphase/"))
+	// adding the same entrypoint again doesn't change the graph
+	require.NoError(t, graph.Add("testdata/workers-targetphase"))
+
+	assert.Equal(t, []string{
+		"testdata/workers-targetphase",
+		"testdata/workers-targetphase/hostgenerator",
+		"testdata/workers-targetphase/hostgenerator/host-generation.yaml",
+		"testdata/workers-targetphase/nodes",
+	}, graph.Nodes())
+	assert.True(t, graph.IsKustomization("testdata/workers-targetphase/nodes"))
+	assert.False(t, graph.IsKustomization("testdata/workers-targetphase/hostgenerator/host-generation.yaml"))
+	assert.Equal(t, []document.KustomEdge{
+		{
+			From: "testdata/workers-targetphase",
+			To:   "testdata/workers-targetphase/hostgenerator",
+			Type: "Generators",
+		},
+		{
+			From: "testdata/workers-targetphase",
+			To:   "testdata/workers-targetphase/nodes",
+			Type: "Resources",
+		},
+		{
+			From: "testdata/workers-targetphase/hostgenerator",
+			To:   "testdata/workers-targetphase/hostgenerator/host-generation.yaml",
+			Type: "Resources",
+		},
+	}, graph.Edges())
+
+	assert.Equal(t, []string{
+		"testdata/workers-targetphase/hostgenerator",
+		"testdata/workers-targetphase/hostgenerator/host-generation.yaml",
+	}, graph.Dependencies("testdata/workers-targetphase/hostgenerator"))
+	assert.Nil(t, graph.Dependencies("testdata/unknown"))
+
+	testCases := []struct {
+		path     string
+		expected []string
+	}{
+		{
+			path: "testdata/workers-targetphase/nodes",
+			expected: []string{
+				"testdata/workers-targetphase",
+				"testdata/workers-targetphase/nodes",
+			},
+		},
+		{
+			path: "testdata/workers-targetphase/hostgenerator/",
+			expected: []string{
+				"testdata/workers-targetphase",
+				"testdata/workers-targetphase/hostgenerator",
+				"testdata/workers-targetphase/hostgenerator/host-generation.yaml",
+			},
+		},
+		{
+			path:     "testdata/workers",
+			expected: []string{},
+		},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, graph.Dependents(tc.path), tc.path)
+	}
+
+	assert.Error(t, document.NewKustomGraph(fs.NewDocumentFs()).Add("testdata/not-found"))
+}
@@ -0,0 +1,68 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lint
+
+import (
+	"fmt"
+)
+
+// ErrUnknownRule is returned when lint config refers to the rule which doesn't exist
+type ErrUnknownRule struct {
+	RuleID string
+}
+
+func (e ErrUnknownRule) Error() string {
+	return fmt.Sprintf("unknown lint rule %s", e.RuleID)
+}
+
+// ErrInvalidSeverity is returned when lint config defines unsupported severity of the rule
+type ErrInvalidSeverity struct {
+	RuleID   string
+	Severity string
+}
+
+func (e ErrInvalidSeverity) Error() string {
+	return fmt.Sprintf("invalid severity %s of lint rule %s. Allowed values are error|warning|note",
+		e.Severity, e.RuleID)
+}
+
+// ErrInvalidFormat is returned when unsupported output format is requested
+type ErrInvalidFormat struct {
+	RequestedFormat string
+}
+
+func (e ErrInvalidFormat) Error() string {
+	return fmt.Sprintf("invalid output format specified %s. Allowed values are table|json|sarif",
+		e.RequestedFormat)
+}
+
+// ErrLintFailed is returned when lint finds problems with error severity
+type ErrLintFailed struct {
+	Errors int
+}
+
+func (e ErrLintFailed) Error() string {
+	return fmt.Sprintf("lint failed with %d error(s)", e.Errors)
+}
+
+// ErrInvalidPattern is returned when placeholder pattern of the lint config can't be compiled
+type ErrInvalidPattern struct {
+	Pattern string
+	Err     error
+}
+
+func (e ErrInvalidPattern) Error() string {
+	return fmt.Sprintf("invalid placeholder pattern %s: %v", e.Pattern, e.Err)
+}
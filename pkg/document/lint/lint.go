@@ -0,0 +1,179 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lint
+
+import (
+	"fmt"
+	"sort"
+
+	"opendev.org/airship/airshipctl/pkg/api/v1alpha1"
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/fs"
+)
+
+// Severity is a severity of the lint finding
+type Severity string
+
+const (
+	// SeverityError is used for findings which fail the lint
+	SeverityError Severity = "error"
+	// SeverityWarning is used for findings which should be reviewed
+	SeverityWarning Severity = "warning"
+	// SeverityNote is used for informational findings
+	SeverityNote Severity = "note"
+)
+
+// Resource identifies the document the finding refers to
+type Resource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+}
+
+// String returns resource identifier in the form of Kind/namespace/name
+func (r Resource) String() string {
+	if r.Namespace == "" {
+		return fmt.Sprintf("%s/%s", r.Kind, r.Name)
+	}
+	return fmt.Sprintf("%s/%s/%s", r.Kind, r.Namespace, r.Name)
+}
+
+// Finding is a problem reported by the lint rule
+type Finding struct {
+	RuleID   string   `json:"ruleId"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	Resource Resource `json:"resource"`
+}
+
+// Target is the kustomize entrypoint checked by the rules
+type Target struct {
+	// Entrypoint is the path to the kustomize entrypoint
+	Entrypoint string
+	// FileSystem is used to read and render documents of the entrypoint
+	FileSystem fs.FileSystem
+
+	bundle document.Bundle
+	err    error
+}
+
+// NewTarget returns lint target of the kustomize entrypoint
+func NewTarget(fSys fs.FileSystem, entrypoint string) *Target {
+	return &Target{Entrypoint: entrypoint, FileSystem: fSys}
+}
+
+// Bundle returns rendered documents of the entrypoint, documents are rendered on the first call
+func (t *Target) Bundle() (document.Bundle, error) {
+	if t.bundle == nil && t.err == nil {
+		t.bundle, t.err = document.NewBundle(t.FileSystem, t.Entrypoint)
+	}
+	return t.bundle, t.err
+}
+
+// Rule is a check performed against the rendered or unrendered documents of the entrypoint
+type Rule interface {
+	// ID returns unique rule identifier which is used to configure the rule
+	ID() string
+	// Description returns short description of the rule
+	Description() string
+	// Severity returns default severity of the rule findings
+	Severity() Severity
+	// Rendered returns true if the rule checks rendered documents. Rules which check unrendered
+	// documents are run first, so that their findings are reported even if rendering fails
+	Rendered() bool
+	// Check returns findings for the target, RuleID and Severity of the findings are set by the linter
+	Check(*Target, *v1alpha1.LintConfigSpec) ([]Finding, error)
+}
+
+var rules = make(map[string]Rule)
+
+// RegisterRule adds the rule to the set of rules used by Lint
+func RegisterRule(rule Rule) {
+	rules[rule.ID()] = rule
+}
+
+// Rules returns registered rules sorted by ID
+func Rules() []Rule {
+	result := make([]Rule, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, rule)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID() < result[j].ID() })
+	return result
+}
+
+// Lint runs enabled rules against the target and returns their findings. If documents of the target
+// can't be rendered, findings of the rules checking unrendered documents are returned along with the error
+func Lint(target *Target, cfg *v1alpha1.LintConfigSpec) ([]Finding, error) {
+	if cfg == nil {
+		cfg = &v1alpha1.LintConfigSpec{}
+	}
+	for id, ruleCfg := range cfg.Rules {
+		if _, ok := rules[id]; !ok {
+			return nil, ErrUnknownRule{RuleID: id}
+		}
+		if ruleCfg.Severity != "" && !validSeverity(Severity(ruleCfg.Severity)) {
+			return nil, ErrInvalidSeverity{RuleID: id, Severity: ruleCfg.Severity}
+		}
+	}
+
+	findings := []Finding{}
+	for _, rendered := range []bool{false, true} {
+		for _, rule := range Rules() {
+			ruleCfg := cfg.Rules[rule.ID()]
+			if ruleCfg.Disabled || rule.Rendered() != rendered {
+				continue
+			}
+			severity := rule.Severity()
+			if ruleCfg.Severity != "" {
+				severity = Severity(ruleCfg.Severity)
+			}
+
+			ruleFindings, err := rule.Check(target, cfg)
+			if err != nil {
+				return findings, err
+			}
+			for _, f := range ruleFindings {
+				f.RuleID = rule.ID()
+				f.Severity = severity
+				findings = append(findings, f)
+			}
+		}
+	}
+	return findings, nil
+}
+
+func validSeverity(severity Severity) bool {
+	switch severity {
+	case SeverityError, SeverityWarning, SeverityNote:
+		return true
+	default:
+		return false
+	}
+}
+
+func resourceOf(doc document.Document) Resource {
+	apiVersion := doc.GetVersion()
+	if doc.GetGroup() != "" {
+		apiVersion = doc.GetGroup() + "/" + apiVersion
+	}
+	return Resource{
+		APIVersion: apiVersion,
+		Kind:       doc.GetKind(),
+		Name:       doc.GetName(),
+		Namespace:  doc.GetNamespace(),
+	}
+}
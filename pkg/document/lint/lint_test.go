@@ -0,0 +1,153 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lint_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/api/v1alpha1"
+	"opendev.org/airship/airshipctl/pkg/document/lint"
+	_ "opendev.org/airship/airshipctl/pkg/document/plugin"
+	"opendev.org/airship/airshipctl/pkg/fs"
+)
+
+type result struct {
+	ruleID   string
+	severity lint.Severity
+	resource string
+}
+
+func results(findings []lint.Finding) []result {
+	res := []result{}
+	for _, f := range findings {
+		res = append(res, result{ruleID: f.RuleID, severity: f.Severity, resource: f.Resource.String()})
+	}
+	return res
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         *v1alpha1.LintConfigSpec
+		expected    []result
+		expectedErr error
+	}{
+		{
+			name: "default rules",
+			expected: []result{
+				{lint.DeprecatedAPIVersionRule, lint.SeverityWarning, "Deployment/default/web"},
+				{lint.DuplicateResourceRule, lint.SeverityError, "Deployment/default/web"},
+				{lint.UncataloguedImageRule, lint.SeverityWarning, "Deployment/default/web"},
+				{lint.MissingNamespaceRule, lint.SeverityWarning, "ConfigMap/no-namespace"},
+				{lint.PlaceholderSecretRule, lint.SeverityError, "Secret/default/credentials"},
+			},
+		},
+		{
+			name: "configured rules",
+			cfg: &v1alpha1.LintConfigSpec{
+				Rules: map[string]v1alpha1.LintRule{
+					lint.DeprecatedAPIVersionRule:  {Disabled: true},
+					lint.DuplicateResourceRule:     {Disabled: true},
+					lint.UnresolvedReplacementRule: {Disabled: true},
+					lint.PlaceholderSecretRule:     {Severity: "warning"},
+				},
+				ClusterScopedKinds:  []string{"ConfigMap"},
+				PlaceholderPatterns: []string{`^c2VjcmV0`},
+			},
+			expected: []result{
+				{lint.UncataloguedImageRule, lint.SeverityWarning, "Deployment/default/web"},
+				{lint.PlaceholderSecretRule, lint.SeverityWarning, "Secret/default/credentials"},
+				{lint.PlaceholderSecretRule, lint.SeverityWarning, "Secret/default/credentials"},
+			},
+		},
+		{
+			name: "unknown rule",
+			cfg: &v1alpha1.LintConfigSpec{
+				Rules: map[string]v1alpha1.LintRule{"unknown": {}},
+			},
+			expectedErr: lint.ErrUnknownRule{RuleID: "unknown"},
+		},
+		{
+			name: "invalid severity",
+			cfg: &v1alpha1.LintConfigSpec{
+				Rules: map[string]v1alpha1.LintRule{lint.MissingNamespaceRule: {Severity: "fatal"}},
+			},
+			expectedErr: lint.ErrInvalidSeverity{RuleID: lint.MissingNamespaceRule, Severity: "fatal"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			findings, err := lint.Lint(lint.NewTarget(fs.NewDocumentFs(), "testdata"), tt.cfg)
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, results(findings))
+		})
+	}
+}
+
+func TestLintUnresolvedReplacement(t *testing.T) {
+	// replacement transformer fails to render the entrypoint, the finding explains the failure
+	findings, err := lint.Lint(lint.NewTarget(fs.NewDocumentFs(), "testdata/unresolved"), nil)
+	require.Error(t, err)
+	assert.Equal(t, []result{
+		{lint.UnresolvedReplacementRule, lint.SeverityError, "ReplacementTransformer/replacements"},
+	}, results(findings))
+
+	findings, err = lint.Lint(lint.NewTarget(fs.NewDocumentFs(), "testdata/unresolved"), &v1alpha1.LintConfigSpec{
+		Rules: map[string]v1alpha1.LintRule{lint.UnresolvedReplacementRule: {Disabled: true}},
+	})
+	require.Error(t, err)
+	assert.Empty(t, findings)
+}
+
+func TestWriteFindings(t *testing.T) {
+	findings := []lint.Finding{
+		{
+			RuleID:   lint.MissingNamespaceRule,
+			Severity: lint.SeverityWarning,
+			Message:  "namespaced resource doesn't define namespace",
+			Resource: lint.Resource{APIVersion: "v1", Kind: "ConfigMap", Name: "cm"},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, lint.WriteFindings(buf, lint.TableOutputFormat, findings))
+	assert.Contains(t, buf.String(), "ConfigMap/cm")
+
+	buf.Reset()
+	require.NoError(t, lint.WriteFindings(buf, lint.JSONOutputFormat, findings))
+	actual := []lint.Finding{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &actual))
+	assert.Equal(t, findings, actual)
+
+	buf.Reset()
+	require.NoError(t, lint.WriteFindings(buf, lint.SARIFOutputFormat, findings))
+	sarif := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &sarif))
+	assert.Equal(t, "2.1.0", sarif["version"])
+	assert.Len(t, sarif["runs"], 1)
+
+	assert.Equal(t, lint.ErrInvalidFormat{RequestedFormat: "yaml"},
+		lint.WriteFindings(buf, "yaml", findings))
+}
@@ -0,0 +1,156 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"opendev.org/airship/airshipctl/pkg/util"
+	"opendev.org/airship/airshipctl/pkg/version"
+)
+
+const (
+	// TableOutputFormat prints findings as a table
+	TableOutputFormat = "table"
+	// JSONOutputFormat prints findings as a JSON list
+	JSONOutputFormat = "json"
+	// SARIFOutputFormat prints findings as a SARIF 2.1.0 log
+	SARIFOutputFormat = "sarif"
+
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	toolName     = "airshipctl"
+	toolURI      = "https://docs.airshipit.org/airshipctl/"
+)
+
+// WriteFindings prints findings in the requested format
+func WriteFindings(w io.Writer, format string, findings []Finding) error {
+	switch format {
+	case TableOutputFormat:
+		return writeTable(w, findings)
+	case JSONOutputFormat:
+		return writeJSON(w, findings)
+	case SARIFOutputFormat:
+		return writeJSON(w, newSARIFLog(findings))
+	default:
+		return ErrInvalidFormat{RequestedFormat: format}
+	}
+}
+
+func writeTable(w io.Writer, findings []Finding) error {
+	tw := util.NewTabWriter(w)
+	fmt.Fprintln(tw, "SEVERITY\tRULE\tRESOURCE\tMESSAGE")
+	for _, f := range findings {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", f.Severity, f.RuleID, f.Resource, f.Message)
+	}
+	return tw.Flush()
+}
+
+func writeJSON(w io.Writer, obj interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(obj)
+}
+
+// SARIF log structures, only the subset of the format used by the linter is defined
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level Severity `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     Severity        `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+func newSARIFLog(findings []Finding) sarifLog {
+	driver := sarifDriver{
+		Name:           toolName,
+		Version:        version.Get().GitVersion,
+		InformationURI: toolURI,
+		Rules:          []sarifRule{},
+	}
+	for _, rule := range Rules() {
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   rule.ID(),
+			ShortDescription:     sarifMessage{Text: rule.Description()},
+			DefaultConfiguration: sarifConfiguration{Level: rule.Severity()},
+		})
+	}
+
+	results := make([]sarifResult, 0, len(findings))
+	for _, f := range findings {
+		results = append(results, sarifResult{
+			RuleID:  f.RuleID,
+			Level:   f.Severity,
+			Message: sarifMessage{Text: f.Message},
+			Locations: []sarifLocation{{
+				LogicalLocations: []sarifLogicalLocation{{
+					FullyQualifiedName: f.Resource.APIVersion + "/" + f.Resource.String(),
+					Kind:               "resource",
+				}},
+			}},
+		})
+	}
+	return sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}
+}
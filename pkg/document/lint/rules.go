@@ -0,0 +1,606 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lint
+
+import (
+	"encoding/base64"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+
+	"opendev.org/airship/airshipctl/pkg/api/v1alpha1"
+	"opendev.org/airship/airshipctl/pkg/container"
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/fs"
+)
+
+// Built-in rule IDs
+const (
+	DuplicateResourceRule     = "duplicate-resource-id"
+	MissingNamespaceRule      = "missing-namespace"
+	UnresolvedReplacementRule = "unresolved-replacement-source"
+	UncataloguedImageRule     = "image-not-in-catalogue"
+	DeprecatedAPIVersionRule  = "deprecated-api-version"
+	PlaceholderSecretRule     = "placeholder-secret-value"
+)
+
+// defaultClusterScopedKinds are kinds of the well-known cluster scoped resources
+var defaultClusterScopedKinds = []string{
+	"APIService",
+	"CSIDriver",
+	"CSINode",
+	"CertificateSigningRequest",
+	"ClusterRole",
+	"ClusterRoleBinding",
+	"ComponentStatus",
+	"CustomResourceDefinition",
+	"IngressClass",
+	"MutatingWebhookConfiguration",
+	"Namespace",
+	"Node",
+	"PersistentVolume",
+	"PodSecurityPolicy",
+	"PriorityClass",
+	"RuntimeClass",
+	"StorageClass",
+	"ValidatingWebhookConfiguration",
+	"VolumeAttachment",
+}
+
+// defaultDeprecatedAPIVersions maps deprecated apiVersion/Kind or apiVersion to its replacement
+var defaultDeprecatedAPIVersions = map[string]string{
+	"extensions/v1beta1/DaemonSet":            "apps/v1",
+	"extensions/v1beta1/Deployment":           "apps/v1",
+	"extensions/v1beta1/Ingress":              "networking.k8s.io/v1",
+	"extensions/v1beta1/NetworkPolicy":        "networking.k8s.io/v1",
+	"extensions/v1beta1/ReplicaSet":           "apps/v1",
+	"apps/v1beta1":                            "apps/v1",
+	"apps/v1beta2":                            "apps/v1",
+	"admissionregistration.k8s.io/v1beta1":    "admissionregistration.k8s.io/v1",
+	"apiextensions.k8s.io/v1beta1":            "apiextensions.k8s.io/v1",
+	"apiregistration.k8s.io/v1beta1":          "apiregistration.k8s.io/v1",
+	"batch/v1beta1/CronJob":                   "batch/v1",
+	"certificates.k8s.io/v1beta1":             "certificates.k8s.io/v1",
+	"coordination.k8s.io/v1beta1":             "coordination.k8s.io/v1",
+	"networking.k8s.io/v1beta1":               "networking.k8s.io/v1",
+	"policy/v1beta1/PodDisruptionBudget":      "policy/v1",
+	"rbac.authorization.k8s.io/v1beta1":       "rbac.authorization.k8s.io/v1",
+	"scheduling.k8s.io/v1beta1":               "scheduling.k8s.io/v1",
+	"storage.k8s.io/v1beta1/CSIDriver":        "storage.k8s.io/v1",
+	"storage.k8s.io/v1beta1/CSINode":          "storage.k8s.io/v1",
+	"storage.k8s.io/v1beta1/StorageClass":     "storage.k8s.io/v1",
+	"storage.k8s.io/v1beta1/VolumeAttachment": "storage.k8s.io/v1",
+}
+
+// defaultPlaceholderPatterns match secret values which are obviously not real secrets
+var defaultPlaceholderPatterns = []string{
+	`(?i)^(change[-_]?me|replace[-_]?me|todo|tbd|fixme|placeholder|dummy|secret|password)$`,
+	`(?i)^x{3,}$`,
+	`^<.*>$`,
+	`^\$\{.*\}$`,
+}
+
+// containerFields are the fields of pod spec which hold lists of containers
+var containerFields = []string{"containers", "initContainers", "ephemeralContainers"}
+
+func init() {
+	RegisterRule(&rule{
+		id:          DuplicateResourceRule,
+		description: "Resources must have unique group, kind, namespace and name regardless of API version",
+		severity:    SeverityError,
+		check:       checkDuplicateResources,
+	})
+	RegisterRule(&rule{
+		id:          MissingNamespaceRule,
+		description: "Namespaced resources must define namespace explicitly",
+		severity:    SeverityWarning,
+		check:       checkMissingNamespace,
+	})
+	RegisterRule(&rule{
+		id:          UnresolvedReplacementRule,
+		description: "Source objects of replacement transformers must exist in the transformer input",
+		severity:    SeverityError,
+		checkSource: checkReplacementSources,
+	})
+	RegisterRule(&rule{
+		id:          UncataloguedImageRule,
+		description: "Container images must be defined in the versions catalogue",
+		severity:    SeverityWarning,
+		check:       checkCatalogueImages,
+	})
+	RegisterRule(&rule{
+		id:          DeprecatedAPIVersionRule,
+		description: "Resources must not use deprecated API versions",
+		severity:    SeverityWarning,
+		check:       checkDeprecatedAPIVersions,
+	})
+	RegisterRule(&rule{
+		id:          PlaceholderSecretRule,
+		description: "Secrets must not contain placeholder values",
+		severity:    SeverityError,
+		check:       checkPlaceholderSecrets,
+	})
+}
+
+// rule implements Rule interface with check function of rendered documents
+// or checkSource function of unrendered ones
+type rule struct {
+	id          string
+	description string
+	severity    Severity
+	check       func(document.Bundle, *v1alpha1.LintConfigSpec) ([]Finding, error)
+	checkSource func(*Target, *v1alpha1.LintConfigSpec) ([]Finding, error)
+}
+
+func (r *rule) ID() string          { return r.id }
+func (r *rule) Description() string { return r.description }
+func (r *rule) Severity() Severity  { return r.severity }
+func (r *rule) Rendered() bool      { return r.checkSource == nil }
+
+func (r *rule) Check(target *Target, cfg *v1alpha1.LintConfigSpec) ([]Finding, error) {
+	if r.checkSource != nil {
+		return r.checkSource(target, cfg)
+	}
+	bundle, err := target.Bundle()
+	if err != nil {
+		return nil, err
+	}
+	return r.check(bundle, cfg)
+}
+
+func checkDuplicateResources(bundle document.Bundle, _ *v1alpha1.LintConfigSpec) ([]Finding, error) {
+	docs, err := bundle.GetAllDocuments()
+	if err != nil {
+		return nil, err
+	}
+	var findings []Finding
+	seen := make(map[string]document.Document)
+	for _, doc := range docs {
+		id := strings.Join([]string{doc.GetGroup(), doc.GetKind(), doc.GetNamespace(), doc.GetName()}, "|")
+		if prev, ok := seen[id]; ok {
+			findings = append(findings, Finding{
+				Message: fmt.Sprintf("resource is defined more than once, API versions %s and %s",
+					resourceOf(prev).APIVersion, resourceOf(doc).APIVersion),
+				Resource: resourceOf(doc),
+			})
+			continue
+		}
+		seen[id] = doc
+	}
+	return findings, nil
+}
+
+func checkMissingNamespace(bundle document.Bundle, cfg *v1alpha1.LintConfigSpec) ([]Finding, error) {
+	clusterScoped := make(map[string]bool)
+	for _, kind := range append(defaultClusterScopedKinds, cfg.ClusterScopedKinds...) {
+		clusterScoped[kind] = true
+	}
+	crds, err := bundle.Select(document.NewCRDSelector())
+	if err != nil {
+		return nil, err
+	}
+	for _, crd := range crds {
+		scope, scopeErr := crd.GetString("spec.scope")
+		kind, kindErr := crd.GetString("spec.names.kind")
+		if scopeErr == nil && kindErr == nil && scope == "Cluster" {
+			clusterScoped[kind] = true
+		}
+	}
+
+	docs, err := bundle.GetAllDocuments()
+	if err != nil {
+		return nil, err
+	}
+	var findings []Finding
+	for _, doc := range docs {
+		// airshipctl configuration documents aren't applied to the cluster
+		if doc.GetGroup() == v1alpha1.GroupVersion.Group || clusterScoped[doc.GetKind()] || doc.GetNamespace() != "" {
+			continue
+		}
+		findings = append(findings, Finding{
+			Message:  "namespaced resource doesn't define namespace",
+			Resource: resourceOf(doc),
+		})
+	}
+	return findings, nil
+}
+
+// checkReplacementSources walks kustomizations of the entrypoint and checks that the sources of
+// their replacement transformers exist in the transformer input, i.e. in the documents of the
+// kustomization built without transformers
+func checkReplacementSources(target *Target, _ *v1alpha1.LintConfigSpec) ([]Finding, error) {
+	return checkKustomizationReplacements(target.FileSystem,
+		filepath.Join(target.Entrypoint, document.KustomizationFile), make(map[string]bool))
+}
+
+func checkKustomizationReplacements(fSys fs.FileSystem, kfile string, visited map[string]bool) ([]Finding, error) {
+	if visited[kfile] {
+		return nil, nil
+	}
+	visited[kfile] = true
+	resMap, err := document.MakeResMap(fSys, kfile)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+	for _, sourceType := range []string{"Resources", "Bases", "Components"} {
+		for _, path := range resMap[sourceType] {
+			if !fSys.IsDir(path) {
+				continue
+			}
+			childFindings, childErr := checkKustomizationReplacements(fSys,
+				filepath.Join(path, document.KustomizationFile), visited)
+			if childErr != nil {
+				return nil, childErr
+			}
+			findings = append(findings, childFindings...)
+		}
+	}
+
+	var transformers []document.Document
+	for _, path := range resMap["Transformers"] {
+		docs, docsErr := replacementTransformers(fSys, path)
+		if docsErr != nil {
+			return nil, docsErr
+		}
+		transformers = append(transformers, docs...)
+	}
+	if len(transformers) == 0 {
+		return findings, nil
+	}
+
+	input, err := buildWithoutTransformers(fSys, kfile)
+	if err != nil {
+		return nil, err
+	}
+	for _, doc := range transformers {
+		docFindings, docErr := unresolvedReplacementSources(input, doc)
+		if docErr != nil {
+			return nil, docErr
+		}
+		findings = append(findings, docFindings...)
+	}
+	return findings, nil
+}
+
+// replacementTransformers returns ReplacementTransformer documents of the transformers entry,
+// which is either a file or a kustomization directory
+func replacementTransformers(fSys fs.FileSystem, path string) ([]document.Document, error) {
+	var bundle document.Bundle
+	var err error
+	if fSys.IsDir(path) {
+		bundle, err = document.NewBundle(fSys, path)
+	} else {
+		var data []byte
+		if data, err = fSys.ReadFile(path); err != nil {
+			return nil, err
+		}
+		bundle, err = document.NewBundleFromBytes(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return bundle.Select(document.NewSelector().ByGvk(v1alpha1.GroupVersion.Group,
+		v1alpha1.GroupVersion.Version, "ReplacementTransformer"))
+}
+
+// buildWithoutTransformers renders the kustomization with transformers removed
+func buildWithoutTransformers(fSys fs.FileSystem, kfile string) (document.Bundle, error) {
+	data, err := fSys.ReadFile(kfile)
+	if err != nil {
+		return nil, err
+	}
+	kustomization := make(map[string]interface{})
+	if err = yaml.Unmarshal(data, &kustomization); err != nil {
+		return nil, err
+	}
+	delete(kustomization, "transformers")
+	if data, err = yaml.Marshal(kustomization); err != nil {
+		return nil, err
+	}
+	abs, err := filepath.Abs(kfile)
+	if err != nil {
+		return nil, err
+	}
+	return document.NewBundle(&overlayFs{FileSystem: fSys, path: abs, data: data}, filepath.Dir(kfile))
+}
+
+func unresolvedReplacementSources(input document.Bundle, doc document.Document) ([]Finding, error) {
+	transformer := &v1alpha1.ReplacementTransformer{}
+	if err := doc.ToAPIObject(transformer, v1alpha1.Scheme); err != nil {
+		return nil, err
+	}
+	var findings []Finding
+	for i, r := range transformer.Replacements {
+		if r.Source == nil || r.Source.ObjRef == nil {
+			continue
+		}
+		ref := r.Source.ObjRef
+		gvk := schema.GroupVersionKind{Group: ref.Group, Version: ref.Version, Kind: ref.Kind}
+		if ref.APIVersion != "" {
+			gv, err := schema.ParseGroupVersion(ref.APIVersion)
+			if err != nil {
+				return nil, err
+			}
+			gvk.Group, gvk.Version = gv.Group, gv.Version
+		}
+		sources, err := input.Select(document.NewSelector().
+			ByGvk(gvk.Group, gvk.Version, gvk.Kind).
+			ByName(ref.Name).
+			ByNamespace(ref.Namespace))
+		if err != nil {
+			return nil, err
+		}
+		if len(sources) == 0 {
+			findings = append(findings, Finding{
+				Message: fmt.Sprintf("source %s/%s of replacement #%d is not found in the transformer input",
+					ref.Kind, ref.Name, i),
+				Resource: resourceOf(doc),
+			})
+		}
+	}
+	return findings, nil
+}
+
+// overlayFs replaces content of the single file of the underlying file system
+type overlayFs struct {
+	fs.FileSystem
+
+	path string
+	data []byte
+}
+
+// ReadFile returns overlay content for the replaced file
+func (o *overlayFs) ReadFile(path string) ([]byte, error) {
+	if abs, err := filepath.Abs(path); err == nil && abs == o.path {
+		return o.data, nil
+	}
+	return o.FileSystem.ReadFile(path)
+}
+
+// catalogueImage is an image allowed by the versions catalogue, empty tag or digest matches any value
+type catalogueImage struct {
+	repo   string
+	tag    string
+	digest string
+}
+
+func checkCatalogueImages(bundle document.Bundle, _ *v1alpha1.LintConfigSpec) ([]Finding, error) {
+	catalogues, err := bundle.Select(document.NewSelector().ByGvk(v1alpha1.GroupVersion.Group,
+		v1alpha1.GroupVersion.Version, "VersionsCatalogue"))
+	if err != nil || len(catalogues) == 0 {
+		// nothing to check against
+		return nil, err
+	}
+
+	var allowed []catalogueImage
+	for _, doc := range catalogues {
+		catalogue := &v1alpha1.VersionsCatalogue{}
+		if err = doc.ToObject(catalogue); err != nil {
+			return nil, err
+		}
+		allowed = append(allowed, catalogueImages(catalogue)...)
+	}
+
+	docs, err := bundle.GetAllDocuments()
+	if err != nil {
+		return nil, err
+	}
+	var findings []Finding
+	for _, doc := range docs {
+		if doc.GetGroup() == v1alpha1.GroupVersion.Group {
+			continue
+		}
+		obj := map[string]interface{}{}
+		if err = doc.ToObject(&obj); err != nil {
+			return nil, err
+		}
+		for _, image := range containerImages(obj) {
+			if !imageAllowed(image, allowed) {
+				findings = append(findings, Finding{
+					Message:  fmt.Sprintf("image %s is not defined in versions catalogue", image),
+					Resource: resourceOf(doc),
+				})
+			}
+		}
+	}
+	return findings, nil
+}
+
+func catalogueImages(catalogue *v1alpha1.VersionsCatalogue) []catalogueImage {
+	var result []catalogueImage
+	for _, resources := range catalogue.Spec.Images {
+		for _, images := range resources {
+			for _, image := range images {
+				repo, tag, digest := container.SplitImage(image.Image)
+				result = append(result, catalogueImage{repo: repo, tag: tag, digest: digest})
+			}
+		}
+	}
+	for _, capi := range catalogue.Spec.CAPIImages {
+		for _, image := range []v1alpha1.ImageURLSpec{capi.Manager, capi.AuthProxy, capi.IPAMManager} {
+			if image.Repository != "" {
+				result = append(result, catalogueImage{repo: image.Repository, tag: image.Tag})
+			}
+		}
+	}
+	specs := []v1alpha1.ImageRepositorySpec{}
+	for _, component := range catalogue.Spec.ImageComponents {
+		for _, spec := range component {
+			specs = append(specs, spec)
+		}
+	}
+	for _, spec := range catalogue.Spec.ImageRepositories {
+		specs = append(specs, spec)
+	}
+	for _, spec := range specs {
+		repo := spec.Repository
+		if spec.Name != "" {
+			repo = strings.TrimSuffix(repo, "/") + "/" + spec.Name
+		}
+		digest := spec.Digest
+		if digest != "" && !strings.Contains(digest, ":") {
+			digest = "sha256:" + digest
+		}
+		result = append(result, catalogueImage{repo: repo, tag: spec.Tag, digest: digest})
+	}
+	return result
+}
+
+func imageAllowed(image string, allowed []catalogueImage) bool {
+	repo, tag, digest := container.SplitImage(image)
+	for _, a := range allowed {
+		if a.repo != repo {
+			continue
+		}
+		if a.tag != "" && tag != "" && a.tag != tag {
+			continue
+		}
+		if a.digest != "" && digest != "" && a.digest != digest {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// containerImages returns images of all containers found in the object
+func containerImages(obj interface{}) []string {
+	var images []string
+	switch val := obj.(type) {
+	case map[string]interface{}:
+		for _, field := range containerFields {
+			containers, ok := val[field].([]interface{})
+			if !ok {
+				continue
+			}
+			for _, c := range containers {
+				if container, ok := c.(map[string]interface{}); ok {
+					if image, ok := container["image"].(string); ok && image != "" {
+						images = append(images, image)
+					}
+				}
+			}
+		}
+		keys := make([]string, 0, len(val))
+		for key := range val {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			images = append(images, containerImages(val[key])...)
+		}
+	case []interface{}:
+		for _, item := range val {
+			images = append(images, containerImages(item)...)
+		}
+	}
+	return images
+}
+
+func checkDeprecatedAPIVersions(bundle document.Bundle, cfg *v1alpha1.LintConfigSpec) ([]Finding, error) {
+	deprecated := make(map[string]string, len(defaultDeprecatedAPIVersions))
+	for k, v := range defaultDeprecatedAPIVersions {
+		deprecated[k] = v
+	}
+	for k, v := range cfg.DeprecatedAPIVersions {
+		deprecated[k] = v
+	}
+
+	docs, err := bundle.GetAllDocuments()
+	if err != nil {
+		return nil, err
+	}
+	var findings []Finding
+	for _, doc := range docs {
+		res := resourceOf(doc)
+		replacement, ok := deprecated[res.APIVersion+"/"+res.Kind]
+		if !ok {
+			replacement, ok = deprecated[res.APIVersion]
+		}
+		if !ok {
+			continue
+		}
+		findings = append(findings, Finding{
+			Message:  fmt.Sprintf("API version %s is deprecated, use %s instead", res.APIVersion, replacement),
+			Resource: res,
+		})
+	}
+	return findings, nil
+}
+
+func checkPlaceholderSecrets(bundle document.Bundle, cfg *v1alpha1.LintConfigSpec) ([]Finding, error) {
+	var patterns []*regexp.Regexp
+	for _, p := range append(defaultPlaceholderPatterns, cfg.PlaceholderPatterns...) {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, ErrInvalidPattern{Pattern: p, Err: err}
+		}
+		patterns = append(patterns, re)
+	}
+
+	secrets, err := bundle.Select(document.NewSelector().ByGvk("", "v1", document.SecretKind))
+	if err != nil {
+		return nil, err
+	}
+	var findings []Finding
+	for _, secret := range secrets {
+		values := make(map[string]string)
+		if data, dataErr := secret.GetStringMap("data"); dataErr == nil {
+			for key, encoded := range data {
+				decoded, decodeErr := base64.StdEncoding.DecodeString(encoded)
+				if decodeErr != nil {
+					continue
+				}
+				values["data."+key] = string(decoded)
+			}
+		}
+		if stringData, dataErr := secret.GetStringMap("stringData"); dataErr == nil {
+			for key, value := range stringData {
+				values["stringData."+key] = value
+			}
+		}
+
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if matchesAny(strings.TrimSpace(values[key]), patterns) {
+				findings = append(findings, Finding{
+					Message:  fmt.Sprintf("value of %s looks like a placeholder", key),
+					Resource: resourceOf(secret),
+				})
+			}
+		}
+	}
+	return findings, nil
+}
+
+func matchesAny(value string, patterns []*regexp.Regexp) bool {
+	for _, re := range patterns {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
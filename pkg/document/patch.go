@@ -0,0 +1,136 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package document
+
+import (
+	"strings"
+
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// Patch types reported by ErrBadPatch
+const (
+	JSONPatchType           = "json"
+	MergePatchType          = "merge"
+	StrategicMergePatchType = "strategic merge"
+)
+
+// newNode converts the value to yaml node, RNode and Node values are copied as is
+func newNode(value interface{}) (*kyaml.Node, error) {
+	switch v := value.(type) {
+	case *kyaml.RNode:
+		return copyNode(v.YNode()), nil
+	case *kyaml.Node:
+		return copyNode(v), nil
+	}
+	data, err := kyaml.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	rn, err := kyaml.Parse(string(data))
+	if err != nil {
+		return nil, err
+	}
+	return rn.YNode(), nil
+}
+
+func copyNode(node *kyaml.Node) *kyaml.Node {
+	return kyaml.NewRNode(node).Copy().YNode()
+}
+
+// setNode replaces the content of the node with the value, comments of the node are kept
+// unless the value has its own ones
+func setNode(node, value *kyaml.Node) {
+	head, line, foot := node.HeadComment, node.LineComment, node.FootComment
+	*node = *value
+	if node.HeadComment == "" && node.LineComment == "" && node.FootComment == "" {
+		node.HeadComment, node.LineComment, node.FootComment = head, line, foot
+	}
+}
+
+func isNull(node *kyaml.Node) bool {
+	return node.Kind == kyaml.ScalarNode && node.Tag == kyaml.NodeTagNull
+}
+
+// fieldIndex returns the index of the key node of the field within mapping node content
+func fieldIndex(node *kyaml.Node, name string) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// mergePatch applies RFC 7386 JSON merge patch to the node in place, so fields which aren't
+// changed by the patch keep their comments and order
+func mergePatch(node, patch *kyaml.Node) {
+	if patch.Kind != kyaml.MappingNode {
+		setNode(node, copyNode(patch))
+		return
+	}
+	if node.Kind != kyaml.MappingNode {
+		setNode(node, &kyaml.Node{Kind: kyaml.MappingNode, Tag: kyaml.NodeTagMap})
+	}
+	for i := 0; i+1 < len(patch.Content); i += 2 {
+		key, value := patch.Content[i], patch.Content[i+1]
+		idx := fieldIndex(node, key.Value)
+		if isNull(value) {
+			if idx >= 0 {
+				node.Content = append(node.Content[:idx], node.Content[idx+2:]...)
+			}
+			continue
+		}
+		if idx < 0 {
+			idx = len(node.Content)
+			node.Content = append(node.Content, copyNode(key), &kyaml.Node{})
+		}
+		mergePatch(node.Content[idx+1], value)
+	}
+}
+
+// parentFieldPath splits the path to the path of the parent node and reports if the parent is
+// the document itself, e.g. spec.containers[0] -> spec.containers, {.metadata.name} -> {.metadata}
+func parentFieldPath(path string) (string, bool) {
+	path = strings.TrimSpace(path)
+	jsonPath := isJSONPath(path)
+	inner := path
+	if jsonPath {
+		inner = strings.TrimSuffix(strings.TrimPrefix(path, "{"), "}")
+	}
+	depth, last := 0, -1
+	for i, c := range inner {
+		switch c {
+		case '[', '(':
+			if c == '[' && depth == 0 {
+				last = i
+			}
+			depth++
+		case ']', ')':
+			depth--
+		case '.':
+			if depth == 0 {
+				last = i
+			}
+		}
+	}
+	if last <= 0 {
+		return "", true
+	}
+	if jsonPath {
+		return "{" + inner[:last] + "}", false
+	}
+	return inner[:last], false
+}
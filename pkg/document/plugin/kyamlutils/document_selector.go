@@ -96,6 +96,26 @@ func (f DocumentSelector) ByLabel(labelSelector string) DocumentSelector {
 	return f
 }
 
+// ByAnnotation adds filter to filter by annotationSelector, the syntax is the same as for labelSelector
+func (f DocumentSelector) ByAnnotation(annotationSelector string) DocumentSelector {
+	if annotationSelector != "" {
+		f.filters = append(f.filters, &AnnotationFilter{MatchExpression: annotationSelector})
+	}
+	return f
+}
+
+// ByNameRegex adds filter by 'metadata.name' field value matching the regular expression,
+// the whole name must match, e.g. "web-.*" matches "web-1", but doesn't match "old-web-1"
+func (f DocumentSelector) ByNameRegex(regex string) DocumentSelector {
+	if regex != "" {
+		f.filters = append(f.filters, filters.GrepFilter{
+			Path:  []string{yaml.MetadataField, yaml.NameField},
+			Value: "^(?:" + regex + ")$",
+		})
+	}
+	return f
+}
+
 // Filter RNode objects
 func (f DocumentSelector) Filter(items []*yaml.RNode) (result []*yaml.RNode, err error) {
 	result = items
@@ -134,3 +154,27 @@ func (lf *LabelFilter) Filter(input []*yaml.RNode) ([]*yaml.RNode, error) {
 
 	return output, nil
 }
+
+var _ kio.Filter = &AnnotationFilter{}
+
+// AnnotationFilter allows to filter annotations based on label Selectors syntax
+type AnnotationFilter struct {
+	MatchExpression string
+}
+
+// Filter implements RNode filter interface
+func (af *AnnotationFilter) Filter(input []*yaml.RNode) ([]*yaml.RNode, error) {
+	var output kio.ResourceNodeSlice
+	selector, err := labels.Parse(af.MatchExpression)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, node := range input {
+		if selector.Matches(labels.Set(node.GetAnnotations())) {
+			output = append(output, node)
+		}
+	}
+
+	return output, nil
+}
@@ -41,6 +41,8 @@ metadata:
 apiVersion: v1
 kind: Pod
 metadata:
+  annotations:
+    owner: team-a
   name: p2
   namespace: capi
 ---
@@ -90,6 +92,8 @@ metadata:
 apiVersion: v1
 kind: Pod
 metadata:
+  annotations:
+    owner: team-a
   name: p2
   namespace: capi
 ---
@@ -128,6 +132,8 @@ metadata:
 apiVersion: v1
 kind: Pod
 metadata:
+  annotations:
+    owner: team-a
   name: p2
   namespace: capi`,
 		},
@@ -172,6 +178,8 @@ metadata:
 apiVersion: v1
 kind: Pod
 metadata:
+  annotations:
+    owner: team-a
   name: p2
   namespace: capi
 ---
@@ -186,6 +194,8 @@ metadata:
 			expectedDocs: `apiVersion: v1
 kind: Pod
 metadata:
+  annotations:
+    owner: team-a
   name: p2
   namespace: capi
 ---
@@ -205,6 +215,36 @@ metadata:
   name: p1
   namespace: capi`,
 		},
+		{
+			name:     "Get by name regex",
+			selector: kyamlutils.DocumentSelector{}.ByNameRegex("p[2-9]"),
+			expectedDocs: `apiVersion: v1
+kind: Pod
+metadata:
+  annotations:
+    owner: team-a
+  name: p2
+  namespace: capi`,
+		},
+		{
+			name:     "Get by name regex matching part of the name",
+			selector: kyamlutils.DocumentSelector{}.ByNameRegex("p"),
+		},
+		{
+			name:     "Get by annotation",
+			selector: kyamlutils.DocumentSelector{}.ByAnnotation("owner=team-a"),
+			expectedDocs: `apiVersion: v1
+kind: Pod
+metadata:
+  annotations:
+    owner: team-a
+  name: p2
+  namespace: capi`,
+		},
+		{
+			name:     "Get by annotation no match",
+			selector: kyamlutils.DocumentSelector{}.ByAnnotation("app=pod1"),
+		},
 	}
 	for _, tc := range testCases {
 		tc := tc
@@ -67,3 +67,13 @@ type ErrQueryConversion struct {
 func (e ErrQueryConversion) Error() string {
 	return fmt.Sprintf("failed to convert v1 path '%s' to jsonpath. %s", e.Query, e.Msg)
 }
+
+// ErrFilterExpression returned if filter expression of JSON path can't be parsed
+type ErrFilterExpression struct {
+	Expr string
+	Msg  string
+}
+
+func (e ErrFilterExpression) Error() string {
+	return fmt.Sprintf("invalid filter expression '%s': %s", e.Expr, e.Msg)
+}
@@ -0,0 +1,530 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package kyamlutils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// filterMarker is the name of the field which replaces filter expressions in the query. k8s jsonpath
+// parser supports only a single comparison in the filter, so expressions like
+// [?(@.a == 1 && @.b =~ /x/)] are parsed by the filter itself and replaced with [?(@.__airshipctl_filter_0)]
+const filterMarker = "__airshipctl_filter_"
+
+// filterExpr is a parsed filter expression evaluated against each element of the array
+type filterExpr interface {
+	eval(l JSONPathFilter, item *yaml.RNode) (bool, error)
+}
+
+// operand of the filter expression returns the list of nodes, empty list means that there is no value
+type operand interface {
+	value(item *yaml.RNode) ([]*yaml.RNode, error)
+}
+
+type logicalExpr struct {
+	and         bool
+	left, right filterExpr
+}
+
+func (e logicalExpr) eval(l JSONPathFilter, item *yaml.RNode) (bool, error) {
+	pass, err := e.left.eval(l, item)
+	if err != nil || pass != e.and {
+		return pass, err
+	}
+	return e.right.eval(l, item)
+}
+
+type notExpr struct {
+	expr filterExpr
+}
+
+func (e notExpr) eval(l JSONPathFilter, item *yaml.RNode) (bool, error) {
+	pass, err := e.expr.eval(l, item)
+	return !pass && err == nil, err
+}
+
+// existsExpr passes if the operand has a value, e.g. [?(@.name)]
+type existsExpr struct {
+	operand operand
+}
+
+func (e existsExpr) eval(_ JSONPathFilter, item *yaml.RNode) (bool, error) {
+	val, err := e.operand.value(item)
+	return len(val) > 0, err
+}
+
+type compareExpr struct {
+	op          string
+	left, right operand
+}
+
+func (e compareExpr) eval(l JSONPathFilter, item *yaml.RNode) (bool, error) {
+	left, err := singleValue(e.left, item)
+	if left == nil || err != nil {
+		return false, err
+	}
+	right, err := singleValue(e.right, item)
+	if right == nil || err != nil {
+		return false, err
+	}
+	return l.compare(e.op, left, right)
+}
+
+type regexExpr struct {
+	left operand
+	re   *regexp.Regexp
+}
+
+func (e regexExpr) eval(_ JSONPathFilter, item *yaml.RNode) (bool, error) {
+	left, err := singleValue(e.left, item)
+	if left == nil || err != nil {
+		return false, err
+	}
+	if err = yaml.ErrorIfInvalid(left, yaml.ScalarNode); err != nil {
+		return false, err
+	}
+	return e.re.MatchString(left.YNode().Value), nil
+}
+
+func singleValue(o operand, item *yaml.RNode) (*yaml.RNode, error) {
+	val, err := o.value(item)
+	if len(val) == 0 || err != nil {
+		return nil, err
+	}
+	if len(val) > 1 {
+		return nil, ErrBadQueryFormat{Msg: "can only compare one element at a time"}
+	}
+	return val[0], nil
+}
+
+type pathOperand string
+
+func (o pathOperand) value(item *yaml.RNode) ([]*yaml.RNode, error) {
+	return JSONPathFilter{Path: "{" + string(o) + "}"}.query(item)
+}
+
+type literalOperand struct {
+	node *yaml.RNode
+}
+
+func (o literalOperand) value(_ *yaml.RNode) ([]*yaml.RNode, error) {
+	return []*yaml.RNode{o.node}, nil
+}
+
+// lengthOperand returns number of elements of the array or the map, or number of characters of the string
+type lengthOperand struct {
+	path pathOperand
+}
+
+func (o lengthOperand) value(item *yaml.RNode) ([]*yaml.RNode, error) {
+	val, err := o.path.value(item)
+	if len(val) == 0 || err != nil {
+		return nil, err
+	}
+	length := len(val)
+	if len(val) == 1 {
+		node := val[0].YNode()
+		switch node.Kind {
+		case yaml.SequenceNode:
+			length = len(node.Content)
+		case yaml.MappingNode:
+			length = len(node.Content) / 2
+		default:
+			length = utf8.RuneCountInString(node.Value)
+		}
+	}
+	return []*yaml.RNode{intNode(length)}, nil
+}
+
+func intNode(i int) *yaml.RNode {
+	return yaml.NewRNode(&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(i)})
+}
+
+// extractFilters replaces filter expressions of the query with markers and returns the parsed expressions
+func extractFilters(query string) (string, []filterExpr, error) {
+	var b strings.Builder
+	var exprs []filterExpr
+	for i := 0; i < len(query); {
+		switch {
+		case strings.HasPrefix(query[i:], "[?("):
+			start := i + len("[?(")
+			end, found := filterEnd(query, start)
+			if !found {
+				// let jsonpath parser report the error
+				b.WriteString(query[i:])
+				return b.String(), exprs, nil
+			}
+			expr, err := parseFilterExpr(query[start:end])
+			if err != nil {
+				return "", nil, err
+			}
+			fmt.Fprintf(&b, "[?(@.%s%d)]", filterMarker, len(exprs))
+			exprs = append(exprs, expr)
+			i = end + len(")]")
+		case query[i] == '\'' || query[i] == '"':
+			end := skipQuoted(query, i)
+			b.WriteString(query[i:end])
+			i = end
+		default:
+			b.WriteByte(query[i])
+			i++
+		}
+	}
+	return b.String(), exprs, nil
+}
+
+// filterEnd returns the position of the closing parenthesis of the filter expression
+func filterEnd(query string, start int) (int, bool) {
+	depth := 0
+	for i := start; i < len(query); {
+		switch c := query[i]; {
+		case c == '\'' || c == '"':
+			i = skipQuoted(query, i)
+			continue
+		case c == '/' && strings.HasSuffix(strings.TrimSpace(query[start:i]), "=~"):
+			i = skipQuoted(query, i)
+			continue
+		case c == '(':
+			depth++
+		case c == ')' && depth > 0:
+			depth--
+		case c == ')' && strings.HasPrefix(query[i:], ")]"):
+			return i, true
+		}
+		i++
+	}
+	return 0, false
+}
+
+// skipQuoted returns the position after the closing quote of the text started at the given position
+func skipQuoted(s string, start int) int {
+	quote := s[start]
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+type tokenKind int
+
+const (
+	tokPath tokenKind = iota
+	tokString
+	tokRegex
+	tokNumber
+	tokIdent
+	tokOp
+	tokAnd
+	tokOr
+	tokNot
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// filterParser is a recursive descent parser of filter expressions:
+//
+//	or      := and ('||' and)*
+//	and     := unary ('&&' unary)*
+//	unary   := '!' unary | '(' or ')' | operand [op operand]
+//	operand := path | string | number | true | false | length(path)
+type filterParser struct {
+	expr   string
+	tokens []token
+	pos    int
+}
+
+func parseFilterExpr(expr string) (filterExpr, error) {
+	tokens, err := lexFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{expr: expr, tokens: tokens}
+	res, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.tokens) {
+		return nil, p.errorf("unexpected %s", p.tokens[p.pos].text)
+	}
+	return res, nil
+}
+
+func (p *filterParser) errorf(format string, args ...interface{}) error {
+	return ErrFilterExpression{Expr: p.expr, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *filterParser) peek(kind tokenKind) bool {
+	return p.pos < len(p.tokens) && p.tokens[p.pos].kind == kind
+}
+
+func (p *filterParser) next() (token, error) {
+	if p.pos >= len(p.tokens) {
+		return token{}, p.errorf("unexpected end of expression")
+	}
+	p.pos++
+	return p.tokens[p.pos-1], nil
+}
+
+func (p *filterParser) parseOr() (filterExpr, error) {
+	left, err := p.parseAnd()
+	for err == nil && p.peek(tokOr) {
+		p.pos++
+		var right filterExpr
+		if right, err = p.parseAnd(); err == nil {
+			left = logicalExpr{left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *filterParser) parseAnd() (filterExpr, error) {
+	left, err := p.parseUnary()
+	for err == nil && p.peek(tokAnd) {
+		p.pos++
+		var right filterExpr
+		if right, err = p.parseUnary(); err == nil {
+			left = logicalExpr{and: true, left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *filterParser) parseUnary() (filterExpr, error) {
+	switch {
+	case p.peek(tokNot):
+		p.pos++
+		expr, err := p.parseUnary()
+		return notExpr{expr: expr}, err
+	case p.peek(tokLParen):
+		p.pos++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.peek(tokRParen) {
+			return nil, p.errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return expr, nil
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if !p.peek(tokOp) {
+		return existsExpr{operand: left}, nil
+	}
+	op := p.tokens[p.pos].text
+	p.pos++
+	switch op {
+	case "=~":
+		return p.parseRegex(left)
+	case "==", "!=", "<", "<=", ">", ">=":
+	default:
+		return nil, ErrLookup{Msg: fmt.Sprintf("unrecognized filter operator %s", op)}
+	}
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return compareExpr{op: op, left: left, right: right}, nil
+}
+
+func (p *filterParser) parseRegex(left operand) (filterExpr, error) {
+	tok, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if tok.kind != tokRegex && tok.kind != tokString {
+		return nil, p.errorf("regular expression expected after =~, got %s", tok.text)
+	}
+	re, err := regexp.Compile(tok.text)
+	if err != nil {
+		return nil, p.errorf("%v", err)
+	}
+	return regexExpr{left: left, re: re}, nil
+}
+
+func (p *filterParser) parseOperand() (operand, error) {
+	tok, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	switch tok.kind {
+	case tokPath:
+		return pathOperand(tok.text), nil
+	case tokString:
+		return literalOperand{node: yaml.NewScalarRNode(tok.text)}, nil
+	case tokNumber:
+		tag := "!!int"
+		if strings.ContainsAny(tok.text, ".eE") {
+			tag = "!!float"
+		}
+		return literalOperand{node: yaml.NewRNode(&yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: tok.text})}, nil
+	case tokIdent:
+		switch tok.text {
+		case "true", "false":
+			return literalOperand{node: yaml.NewScalarRNode(tok.text)}, nil
+		case "length":
+			return p.parseLength()
+		}
+	}
+	return nil, p.errorf("unexpected %s", tok.text)
+}
+
+func (p *filterParser) parseLength() (operand, error) {
+	if !p.peek(tokLParen) {
+		return nil, p.errorf("length must be called as length(path)")
+	}
+	p.pos++
+	tok, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if tok.kind != tokPath || !p.peek(tokRParen) {
+		return nil, p.errorf("length must be called as length(path)")
+	}
+	p.pos++
+	return lengthOperand{path: pathOperand(tok.text)}, nil
+}
+
+// lexFilter splits the filter expression into tokens
+func lexFilter(expr string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		rest := expr[i:]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+			continue
+		case c == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "("})
+		case c == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")"})
+		case strings.HasPrefix(rest, "&&"):
+			tokens = append(tokens, token{kind: tokAnd, text: "&&"})
+			i++
+		case strings.HasPrefix(rest, "||"):
+			tokens = append(tokens, token{kind: tokOr, text: "||"})
+			i++
+		case strings.ContainsRune(operatorChars, rune(c)):
+			end := i + 1
+			for end < len(expr) && strings.ContainsRune(operatorChars, rune(expr[end])) {
+				end++
+			}
+			kind := tokOp
+			if expr[i:end] == "!" {
+				kind = tokNot
+			}
+			tokens = append(tokens, token{kind: kind, text: expr[i:end]})
+			i = end
+			continue
+		case c == '\'' || c == '"' || (c == '/' && len(tokens) > 0 && tokens[len(tokens)-1].text == "=~"):
+			end := skipQuoted(expr, i)
+			if end > len(expr) || expr[end-1] != c || end-i < 2 {
+				return nil, ErrFilterExpression{Expr: expr, Msg: "unterminated string"}
+			}
+			kind := tokString
+			if c == '/' {
+				kind = tokRegex
+			}
+			tokens = append(tokens, token{kind: kind, text: unquote(expr[i+1:end-1], c)})
+			i = end
+			continue
+		case c == '@' || c == '$' || c == '.' && !(len(rest) > 1 && unicode.IsDigit(rune(rest[1]))):
+			end := pathEnd(expr, i)
+			tokens = append(tokens, token{kind: tokPath, text: expr[i:end]})
+			i = end
+			continue
+		case c == '-' || c == '.' || unicode.IsDigit(rune(c)):
+			end := i + 1
+			for end < len(expr) && strings.ContainsRune("0123456789.eE+-", rune(expr[end])) {
+				end++
+			}
+			if _, err := strconv.ParseFloat(expr[i:end], 64); err != nil {
+				return nil, ErrFilterExpression{Expr: expr, Msg: fmt.Sprintf("invalid number %s", expr[i:end])}
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: expr[i:end]})
+			i = end
+			continue
+		case unicode.IsLetter(rune(c)):
+			end := i + 1
+			for end < len(expr) && (unicode.IsLetter(rune(expr[end])) || expr[end] == '_') {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: expr[i:end]})
+			i = end
+			continue
+		default:
+			return nil, ErrFilterExpression{Expr: expr, Msg: fmt.Sprintf("unexpected character %q", c)}
+		}
+		i++
+	}
+	return tokens, nil
+}
+
+const operatorChars = "=!<>~"
+
+// pathEnd returns the position after the path which may contain brackets, quotes and nested filters
+func pathEnd(expr string, start int) int {
+	depth := 0
+	for i := start; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == '\'' || c == '"':
+			i = skipQuoted(expr, i)
+			continue
+		case c == '[' || c == '(' && depth > 0:
+			depth++
+		case (c == ']' || c == ')') && depth > 0:
+			depth--
+		case depth == 0 && strings.ContainsRune(" \t()=!<>&|", rune(c)):
+			return i
+		}
+		i++
+	}
+	return len(expr)
+}
+
+func unquote(s string, quote byte) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		// keep escape sequences of regular expressions except the escaped delimiter
+		if s[i] == '\\' && i+1 < len(s) && (s[i+1] == quote || quote != '/' && s[i+1] == '\\') {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -27,12 +27,20 @@ import (
 type JSONPathFilter struct {
 	Kind string `yaml:"kind,omitempty"`
 	// Path is jsonpath query. See http://goessner.net/articles/JsonPath/ for
-	// details.
+	// details. In addition to k8s jsonpath syntax filters support logical
+	// operators && and ||, negation !, regex match =~ /regex/, existence check
+	// [?(@.field)], length(path) function and recursive descent ..
 	Path string `yaml:"path,omitempty"`
 	// Mutator is a function which changes filtered node
 	Mutator func([]*yaml.RNode) error
 	// Create empty struct if path element does not exist
 	Create bool
+
+	// filters are parsed filter expressions of the query
+	filters []filterExpr
+	// recursive is set after recursive descent, all arrays are filtered in this case
+	// so elements which can't be evaluated are skipped instead of returning an error
+	recursive bool
 }
 
 type boundaries struct {
@@ -41,25 +49,7 @@ type boundaries struct {
 
 // Filter returns RNode identified by JSON path
 func (l JSONPathFilter) Filter(rn *yaml.RNode) (*yaml.RNode, error) {
-	query, err := convertFromLegacyQuery(l.Path)
-	if err != nil {
-		return nil, err
-	}
-	jp, err := jsonpath.Parse("parser", query)
-	if err != nil {
-		return nil, err
-	}
-	if len(jp.Root.Nodes) != 1 {
-		return nil, ErrBadQueryFormat{Msg: "query must contain one expression"}
-	}
-
-	fieldList, ok := jp.Root.Nodes[0].(*jsonpath.ListNode)
-	if !ok {
-		return nil, ErrBadQueryFormat{Msg: "failed to convert root node to ListNode type"}
-	}
-	// Query result can be a list of values (e.g filter queries) since
-	// walk is recursive we should pass list of RNode even for initial step
-	rns, err := l.walk([]*yaml.RNode{rn}, fieldList)
+	rns, err := l.query(rn)
 	if len(rns) == 0 || err != nil {
 		return nil, err
 	}
@@ -82,9 +72,48 @@ func (l JSONPathFilter) Filter(rn *yaml.RNode) (*yaml.RNode, error) {
 	return yaml.NewRNode(&yaml.Node{Kind: yaml.SequenceNode, Content: nodes}), nil
 }
 
+// Query returns all RNodes identified by JSON path, unlike Filter it doesn't
+// call Mutator and doesn't merge the result into a single SequenceNode
+func (l JSONPathFilter) Query(rn *yaml.RNode) ([]*yaml.RNode, error) {
+	return l.query(rn)
+}
+
+// query returns all RNodes identified by JSON path
+func (l JSONPathFilter) query(rn *yaml.RNode) ([]*yaml.RNode, error) {
+	query, err := convertFromLegacyQuery(l.Path)
+	if err != nil {
+		return nil, err
+	}
+	if query, l.filters, err = extractFilters(query); err != nil {
+		return nil, err
+	}
+	jp, err := jsonpath.Parse("parser", query)
+	if err != nil {
+		return nil, err
+	}
+	if len(jp.Root.Nodes) != 1 {
+		return nil, ErrBadQueryFormat{Msg: "query must contain one expression"}
+	}
+
+	fieldList, ok := jp.Root.Nodes[0].(*jsonpath.ListNode)
+	if !ok {
+		return nil, ErrBadQueryFormat{Msg: "failed to convert root node to ListNode type"}
+	}
+	// Query result can be a list of values (e.g filter queries) since
+	// walk is recursive we should pass list of RNode even for initial step
+	return l.walk([]*yaml.RNode{rn}, fieldList)
+}
+
 func (l JSONPathFilter) walk(nodes []*yaml.RNode, fieldList *jsonpath.ListNode) (res []*yaml.RNode, err error) {
 	res = nodes
-	for _, field := range fieldList.Nodes {
+	for i, field := range fieldList.Nodes {
+		if _, ok := field.(*jsonpath.RecursiveNode); ok {
+			res = descendants(res, fieldList.Nodes[i+1:])
+			// missing fields can't be created in all descendants
+			l.Create = false
+			l.recursive = true
+			continue
+		}
 		res, err = l.getByField(res, field)
 		if err != nil {
 			return nil, err
@@ -235,8 +264,10 @@ func (l JSONPathFilter) defineArrayBoundaries(params [3]jsonpath.ParamsEntry, ma
 }
 
 func (l JSONPathFilter) doFilter(rns []*yaml.RNode, field *jsonpath.FilterNode) ([]*yaml.RNode, error) {
-	// NOTE this cond block was adapted from k8s.io/client-go/util/jsonpath
-	// see evalFilter from from jsonpath.go
+	expr, err := l.filterExpr(field)
+	if err != nil {
+		return nil, err
+	}
 	result := []*yaml.RNode{}
 	for _, rn := range rns {
 		// Elements() will return an error if rn is not SequenceNode
@@ -245,39 +276,9 @@ func (l JSONPathFilter) doFilter(rns []*yaml.RNode, field *jsonpath.FilterNode)
 		if err != nil {
 			return nil, err
 		}
-		var left, right []*yaml.RNode
 		for _, item := range items {
-			pass := false
-			// Evaluate left part of the filter expression against each
-			// array item
-			left, err = l.walk([]*yaml.RNode{item}, field.Left)
-			if err != nil {
-				return nil, err
-			}
-
-			if len(left) == 0 {
-				continue
-			}
-			if len(left) > 1 {
-				return nil, ErrBadQueryFormat{Msg: "can only compare one element at a time"}
-			}
-
-			// Evaluate right part of the filter expression against each
-			// array item
-			right, err = l.walk([]*yaml.RNode{item}, field.Right)
-			if err != nil {
-				return nil, err
-			}
-
-			if len(right) == 0 {
-				continue
-			}
-			if len(right) > 1 {
-				return nil, ErrBadQueryFormat{Msg: "can only compare one element at a time"}
-			}
-
-			pass, err = l.compare(field.Operator, left[0], right[0])
-			if err != nil {
+			pass, err := expr.eval(l, item)
+			if err != nil && !l.recursive {
 				return nil, err
 			}
 			if pass {
@@ -288,6 +289,21 @@ func (l JSONPathFilter) doFilter(rns []*yaml.RNode, field *jsonpath.FilterNode)
 	return result, nil
 }
 
+// filterExpr returns parsed expression of the filter replaced with the marker by extractFilters
+func (l JSONPathFilter) filterExpr(field *jsonpath.FilterNode) (filterExpr, error) {
+	for _, node := range field.Left.Nodes {
+		f, ok := node.(*jsonpath.FieldNode)
+		if !ok || !strings.HasPrefix(f.Value, filterMarker) {
+			continue
+		}
+		i, err := strconv.Atoi(strings.TrimPrefix(f.Value, filterMarker))
+		if err == nil && i < len(l.filters) {
+			return l.filters[i], nil
+		}
+	}
+	return nil, ErrBadQueryFormat{Msg: fmt.Sprintf("unsupported filter expression in %s", l.Path)}
+}
+
 func (l JSONPathFilter) compare(op string, left, right *yaml.RNode) (bool, error) {
 	err := yaml.ErrorIfAnyInvalidAndNonNull(yaml.ScalarNode, left, right)
 	if op != "==" && err != nil {
@@ -339,6 +355,43 @@ func (l JSONPathFilter) doWildcard(rns []*yaml.RNode) ([]*yaml.RNode, error) {
 	return result, nil
 }
 
+// descendants returns the nodes and all nodes nested in them which may be matched by the rest of the path:
+// mappings for fields and sequences for array indexes and filters
+func descendants(rns []*yaml.RNode, rest []jsonpath.Node) []*yaml.RNode {
+	accept := func(kind yaml.Kind) bool { return kind == yaml.MappingNode || kind == yaml.SequenceNode }
+	if len(rest) > 0 {
+		switch rest[0].(type) {
+		case *jsonpath.FieldNode:
+			accept = func(kind yaml.Kind) bool { return kind == yaml.MappingNode }
+		case *jsonpath.ArrayNode, *jsonpath.FilterNode:
+			accept = func(kind yaml.Kind) bool { return kind == yaml.SequenceNode }
+		}
+	}
+
+	var result []*yaml.RNode
+	var visit func(node *yaml.Node)
+	visit = func(node *yaml.Node) {
+		if accept(node.Kind) {
+			result = append(result, yaml.NewRNode(node))
+		}
+		switch node.Kind {
+		case yaml.MappingNode:
+			// values of the mapping are at odd positions
+			for i := 1; i < len(node.Content); i += 2 {
+				visit(node.Content[i])
+			}
+		case yaml.SequenceNode:
+			for _, item := range node.Content {
+				visit(item)
+			}
+		}
+	}
+	for _, rn := range rns {
+		visit(rn.YNode())
+	}
+	return result
+}
+
 func (l JSONPathFilter) updateMapRNodes(rns []*yaml.RNode, name string, node *yaml.RNode) error {
 	for _, rn := range rns {
 		_, err := rn.Pipe(yaml.SetField(name, node))
@@ -494,6 +547,9 @@ func convertFiltering(query string, path []string) []string {
 	case err == nil:
 		// We have detected an integer so an array.
 		result[len(result)-1] = result[len(result)-1] + "[" + query + "]"
+	case query == "*":
+		// All elements of an array, e.g. spec.containers[*]
+		result[len(result)-1] += "[*]"
 	case strings.Contains(query, "="):
 		kvFilterParts := strings.Split(query, "=")
 		q := fmt.Sprintf("[?(.%s == '%s')]",
@@ -410,6 +410,80 @@ value: 10`[1:],
 subkeyWith.dot: val1
 name: obj1`[1:],
 		},
+		{
+			name:  "Array wildcard v1",
+			query: "listOfObjects[*].value",
+			expected: `
+- 10
+- 20
+- 30`[1:],
+		},
+		{
+			name:     "Filter logical and",
+			query:    "{.listOfObjects[?(@.value > 10 && @.name != 'obj3')].name}",
+			expected: "obj2",
+		},
+		{
+			name:  "Filter logical or",
+			query: "{.listOfObjects[?(@.name == 'obj1' || @.value == 30)].name}",
+			expected: `
+- obj1
+- obj3`[1:],
+		},
+		{
+			name:  "Filter negation",
+			query: "{.listOfObjects[?(!(@.value < 20))].name}",
+			expected: `
+- obj2
+- obj3`[1:],
+		},
+		{
+			name:  "Filter regex match",
+			query: "{.listOfObjects[?(@.name =~ /^obj[12]$/)].value}",
+			expected: `
+- 10
+- 20`[1:],
+		},
+		{
+			name:     "Filter regex match quoted",
+			query:    "{.listOfObjects[?(@.name =~ 'obj3')].value}",
+			expected: "30",
+		},
+		{
+			name:     "Filter length",
+			query:    "{.listOfComplexObjects[?(length(@.value) == 3)].name}",
+			expected: "o2",
+		},
+		{
+			name:     "Filter field exists",
+			query:    "{.listOfComplexObjects[?(@.value1)].name}",
+			expected: "o1",
+		},
+		{
+			name:  "Recursive descent",
+			query: "{..someKey1}",
+			expected: `
+- valO11
+- valO11
+- valO21`[1:],
+		},
+		{
+			name:  "Recursive descent with filter",
+			query: "{..[?(@.name == 'o1')].val}",
+			expected: `
+- val1
+- val1`[1:],
+		},
+		{
+			name:        "Filter invalid regex",
+			query:       "{.listOfObjects[?(@.name =~ /[/)]}",
+			expectedErr: "invalid filter expression '@.name =~ /[/': error parsing regexp: missing closing ]: `[`",
+		},
+		{
+			name:        "Filter incomplete expression",
+			query:       "{.listOfObjects[?(@.name == 'obj1' &&)]}",
+			expectedErr: "invalid filter expression '@.name == 'obj1' &&': unexpected end of expression",
+		},
 		{
 			name:        "Nested parentheses",
 			query:       "spec[path[0]]",
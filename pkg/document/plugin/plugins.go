@@ -0,0 +1,57 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package plugin registers airshipctl KRM functions as built-in ones, so that kustomize
+// transformers referencing templater and replacement-transformer images are executed
+// in-process during bundle build instead of running containers. The third-party sops image
+// is executed in-process only if its function config opts in with document.BuiltinFunctionAnnotation
+package plugin
+
+import (
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/document/plugin/replacement"
+	"opendev.org/airship/airshipctl/pkg/document/plugin/sops"
+	"opendev.org/airship/airshipctl/pkg/document/plugin/templater"
+)
+
+var (
+	// TemplaterRepositories are the image repositories of templater KRM function
+	TemplaterRepositories = []string{
+		"localhost/templater",
+		"quay.io/airshipit/templater",
+	}
+	// ReplacementTransformerRepositories are the image repositories of replacement transformer KRM function
+	ReplacementTransformerRepositories = []string{
+		"localhost/replacement-transformer",
+		"quay.io/airshipit/replacement-transformer",
+	}
+	// SopsRepositories are the image repositories of third-party SOPS KRM function
+	SopsRepositories = []string{
+		"gcr.io/kpt-fn-contrib/sops",
+	}
+)
+
+func init() {
+	register(templater.New, TemplaterRepositories)
+	register(replacement.New, ReplacementTransformerRepositories)
+	for _, repository := range SopsRepositories {
+		document.RegisterThirdPartyFunction(repository, sops.New)
+	}
+}
+
+func register(factory document.BuiltinFunctionFactory, repositories []string) {
+	for _, repository := range repositories {
+		document.RegisterBuiltinFunction(repository, factory)
+	}
+}
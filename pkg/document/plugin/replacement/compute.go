@@ -0,0 +1,140 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package replacement
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+
+	airshipv1 "opendev.org/airship/airshipctl/pkg/api/v1alpha1"
+)
+
+// transformations maps transformation type to the function modifying the value
+var transformations = map[string]func(value, param string) (string, error){
+	"base64Encode": func(value, _ string) (string, error) {
+		return encodeString(value), nil
+	},
+	"base64Decode": func(value, _ string) (string, error) {
+		return decodeString(value)
+	},
+	"prefix": func(value, param string) (string, error) {
+		return param + value, nil
+	},
+	"suffix": func(value, param string) (string, error) {
+		return value + param, nil
+	},
+	"nthHost":      nthHost,
+	"network":      network,
+	"netmask":      netmask,
+	"prefixLength": prefixLength,
+}
+
+// transform applies the transformations to the scalar value
+func transform(value *yaml.RNode, ts []airshipv1.ReplTransformation) (*yaml.RNode, error) {
+	if len(ts) == 0 {
+		return value, nil
+	}
+	if err := yaml.ErrorIfInvalid(value, yaml.ScalarNode); err != nil {
+		return nil, ErrTransformation{Type: ts[0].Type, Msg: "value must be a scalar"}
+	}
+	res := yaml.GetValue(value)
+	for _, t := range ts {
+		fn, ok := transformations[t.Type]
+		if !ok {
+			return nil, ErrBadConfiguration{Msg: fmt.Sprintf("unknown transformation type %s", t.Type)}
+		}
+		var err error
+		if res, err = fn(res, t.Value); err != nil {
+			return nil, ErrTransformation{Type: t.Type, Msg: err.Error()}
+		}
+	}
+	return yaml.NewScalarRNode(res), nil
+}
+
+// formatValue substitutes scalar values of the sources into the format string,
+// nil is returned if any of optional sources is not found
+func formatValue(items []*yaml.RNode, format string, sources []airshipv1.ReplSource) (*yaml.RNode, error) {
+	args := make([]interface{}, 0, len(sources))
+	for i := range sources {
+		val, err := getValue(items, &sources[i])
+		if err != nil || val == nil {
+			return nil, err
+		}
+		if err = yaml.ErrorIfInvalid(val, yaml.ScalarNode); err != nil {
+			return nil, ErrBadConfiguration{Msg: fmt.Sprintf("source %d of format %q must be a scalar", i, format)}
+		}
+		args = append(args, yaml.GetValue(val))
+	}
+	res := fmt.Sprintf(format, args...)
+	// fmt reports mismatch of verbs and arguments in the resulting string
+	if strings.Contains(res, "%!") {
+		return nil, ErrBadConfiguration{
+			Msg: fmt.Sprintf("format %q doesn't match %d sources: %s", format, len(sources), res)}
+	}
+	return yaml.NewScalarRNode(res), nil
+}
+
+func nthHost(cidr, param string) (string, error) {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return "", err
+	}
+	n, err := strconv.ParseInt(param, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("host number must be an integer, got %q", param)
+	}
+
+	ip := ipNet.IP
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	ones, bits := ipNet.Mask.Size()
+	size := new(big.Int).Lsh(big.NewInt(1), uint(bits-ones))
+	if n < 0 || big.NewInt(n).Cmp(size) >= 0 {
+		return "", fmt.Errorf("host number %d is out of range of %s", n, cidr)
+	}
+	host := new(big.Int).Add(new(big.Int).SetBytes(ip), big.NewInt(n))
+	return net.IP(host.FillBytes(make([]byte, len(ip)))).String(), nil
+}
+
+func network(cidr, _ string) (string, error) {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return "", err
+	}
+	return ipNet.IP.String(), nil
+}
+
+func netmask(cidr, _ string) (string, error) {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return "", err
+	}
+	return net.IP(ipNet.Mask).String(), nil
+}
+
+func prefixLength(cidr, _ string) (string, error) {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return "", err
+	}
+	ones, _ := ipNet.Mask.Size()
+	return strconv.Itoa(ones), nil
+}
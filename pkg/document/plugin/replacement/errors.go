@@ -49,6 +49,26 @@ func (e ErrSourceNotFound) Error() string {
 	return fmt.Sprintf("failed to find any source resources identified by %s", printFields(e.ObjRef))
 }
 
+// ErrSourceFieldNotFound returned if a replacement source resource doesn't have the field
+type ErrSourceFieldNotFound struct {
+	ObjRef   *airshipv1.Target
+	FieldRef string
+}
+
+func (e ErrSourceFieldNotFound) Error() string {
+	return fmt.Sprintf("failed to find field %s in source resource identified by %s", e.FieldRef, printFields(e.ObjRef))
+}
+
+// ErrTransformation returned if the transformation can't be applied to the replacement value
+type ErrTransformation struct {
+	Type string
+	Msg  string
+}
+
+func (e ErrTransformation) Error() string {
+	return fmt.Sprintf("failed to apply transformation %s: %s", e.Type, e.Msg)
+}
+
 // ErrTargetNotFound returned if a replacement target resource does not exist in the resource map
 type ErrTargetNotFound struct {
 	ObjRef *airshipv1.Selector
@@ -58,6 +78,17 @@ func (e ErrTargetNotFound) Error() string {
 	return fmt.Sprintf("failed to find any target resources identified by %s", printFields(e.ObjRef))
 }
 
+// ErrTargetFieldNotFound returned if none of the fields of replacement target resources were updated
+type ErrTargetFieldNotFound struct {
+	ObjRef    *airshipv1.Selector
+	FieldRefs []string
+}
+
+func (e ErrTargetFieldNotFound) Error() string {
+	return fmt.Sprintf("failed to find any of fields %s in target resources identified by %s",
+		strings.Join(e.FieldRefs, ", "), printFields(e.ObjRef))
+}
+
 // ErrPatternSubstring returned in case of issues with sub-string pattern substitution
 type ErrPatternSubstring struct {
 	Msg string
@@ -26,6 +26,8 @@ import (
 
 	airshipv1 "opendev.org/airship/airshipctl/pkg/api/v1alpha1"
 	"opendev.org/airship/airshipctl/pkg/document/plugin/kyamlutils"
+	"opendev.org/airship/airshipctl/pkg/document/plugin/trace"
+	"opendev.org/airship/airshipctl/pkg/log"
 )
 
 var (
@@ -38,10 +40,14 @@ const (
 	secretData = "data"
 )
 
-var _ kio.Filter = &plugin{}
+var (
+	_ kio.Filter   = &plugin{}
+	_ trace.Tracer = &plugin{}
+)
 
 type plugin struct {
 	*airshipv1.ReplacementTransformer
+	trace bool
 }
 
 // New creates new instance of the plugin
@@ -62,38 +68,172 @@ func New(obj map[string]interface{}) (kio.Filter, error) {
 		if r.Target != nil && r.Targets != nil {
 			return nil, ErrBadConfiguration{Msg: "only target OR targets is allowed in one replacement, not both"}
 		}
-		if r.Source.ObjRef != nil && r.Source.Value != "" {
-			return nil, ErrBadConfiguration{Msg: "only one of fieldref and value is allowed in one replacement"}
+		if err := validateSource(r.Source); err != nil {
+			return nil, err
+		}
+		targets := r.Targets
+		if r.Target != nil {
+			targets = []*airshipv1.ReplTarget{r.Target}
+		}
+		for _, t := range targets {
+			if err := validateTarget(t); err != nil {
+				return nil, err
+			}
 		}
 	}
 	return p, nil
 }
 
+func validateTarget(target *airshipv1.ReplTarget) error {
+	if target.ObjRef == nil {
+		return ErrBadConfiguration{Msg: "objref must be specified in replacement target"}
+	}
+	if target.NameRegex != "" {
+		if target.ObjRef.Name != "" {
+			return ErrBadConfiguration{Msg: "only one of name and nameRegex is allowed in replacement target"}
+		}
+		if _, err := regexp.Compile(target.NameRegex); err != nil {
+			return ErrBadConfiguration{Msg: fmt.Sprintf("invalid nameRegex %q: %v", target.NameRegex, err)}
+		}
+	}
+	// target without name selects all matching objects anyway, allObjects makes it explicit
+	// and protects from selecting resources of all kinds by mistake
+	if target.AllObjects {
+		if target.ObjRef.Kind == "" {
+			return ErrBadConfiguration{Msg: "kind must be specified in objref of replacement target with allObjects"}
+		}
+		if target.ObjRef.Name != "" || target.NameRegex != "" {
+			return ErrBadConfiguration{Msg: "name and nameRegex are not allowed in replacement target with allObjects"}
+		}
+	}
+	return nil
+}
+
+func validateSource(source *airshipv1.ReplSource) error {
+	if source.ObjRef != nil && source.Value != "" {
+		return ErrBadConfiguration{Msg: "only one of fieldref and value is allowed in one replacement"}
+	}
+	if (source.Format == "") != (len(source.Sources) == 0) {
+		return ErrBadConfiguration{Msg: "format and sources must be specified together"}
+	}
+	if len(source.Sources) > 0 && (source.ObjRef != nil || source.Value != "") {
+		return ErrBadConfiguration{Msg: "sources are not allowed together with objref or value"}
+	}
+	if source.Optional && source.Default != "" {
+		return ErrBadConfiguration{Msg: "only one of optional and default is allowed in one replacement"}
+	}
+	for _, t := range source.Transformations {
+		if _, ok := transformations[t.Type]; !ok {
+			return ErrBadConfiguration{Msg: fmt.Sprintf("unknown transformation type %s", t.Type)}
+		}
+	}
+	for i := range source.Sources {
+		if err := validateSource(&source.Sources[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EnableTrace makes the plugin record the source of the replaced fields
+func (p *plugin) EnableTrace() {
+	p.trace = true
+}
+
 func (p *plugin) Filter(items []*yaml.RNode) ([]*yaml.RNode, error) {
-	for _, r := range p.Replacements {
+	report := log.Debugf
+	if p.Report {
+		report = log.Printf
+	}
+	for i, r := range p.Replacements {
 		val, err := getValue(items, r.Source)
 		if err != nil {
 			return nil, err
 		}
+		// optional source is not found
+		if val == nil {
+			log.Debugf("replacement %d skipped: optional source is not found", i)
+			continue
+		}
+		targets := r.Targets
 		if r.Target != nil {
-			if err := replace(items, r.Target, val); err != nil {
-				return nil, err
+			targets = []*airshipv1.ReplTarget{r.Target}
+		}
+		var record *trace.Record
+		if p.trace {
+			record = &trace.Record{
+				Source:      describeSource(r.Source),
+				Transformer: trace.Transformer(p.Kind, p.Name),
 			}
 		}
-		// range handles nil case as empty list
-		for _, t := range r.Targets {
-			if err := replace(items, t, val); err != nil {
+		for _, t := range targets {
+			objects, fields, err := replace(items, t, val, record)
+			if err != nil {
 				return nil, err
 			}
+			report("replacement %d updated %d field(s) in %d resource(s) identified by %s",
+				i, fields, objects, printFields(t.ObjRef))
 		}
 	}
 	return items, nil
 }
 
+// describeSource returns the description of the source used in the trace records
+func describeSource(source *airshipv1.ReplSource) string {
+	switch {
+	case source.Value != "":
+		return "value"
+	case len(source.Sources) > 0:
+		descs := make([]string, 0, len(source.Sources))
+		for i := range source.Sources {
+			descs = append(descs, describeSource(&source.Sources[i]))
+		}
+		return strings.Join(descs, ", ")
+	case source.ObjRef == nil:
+		return ""
+	}
+	id := source.ObjRef.Kind + "/" + source.ObjRef.Name
+	if source.ObjRef.Namespace != "" {
+		id = source.ObjRef.Kind + "/" + source.ObjRef.Namespace + "/" + source.ObjRef.Name
+	}
+	fieldRef := source.FieldRef
+	if fieldRef == "" {
+		fieldRef = yaml.MetadataField + "." + yaml.NameField
+	}
+	return id + " " + fieldRef
+}
+
+// getValue returns the value of the source after all transformations, nil is returned if
+// an optional source is not found
 func getValue(items []*yaml.RNode, source *airshipv1.ReplSource) (*yaml.RNode, error) {
+	val, err := sourceValue(items, source)
+	switch err.(type) {
+	case nil:
+	case ErrSourceNotFound, ErrSourceFieldNotFound:
+		switch {
+		case source.Default != "":
+			val = yaml.NewScalarRNode(source.Default)
+		case source.Optional:
+			return nil, nil
+		default:
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+	if val == nil {
+		return nil, nil
+	}
+	return transform(val, source.Transformations)
+}
+
+func sourceValue(items []*yaml.RNode, source *airshipv1.ReplSource) (*yaml.RNode, error) {
 	if source.Value != "" {
 		return yaml.NewScalarRNode(source.Value), nil
 	}
+	if len(source.Sources) > 0 {
+		return formatValue(items, source.Format, source.Sources)
+	}
 	sources, err := kyamlutils.DocumentSelector{}.
 		ByAPIVersion(source.ObjRef.APIVersion).
 		ByGVK(source.ObjRef.Group, source.ObjRef.Version, source.ObjRef.Kind).
@@ -119,6 +259,9 @@ func getValue(items []*yaml.RNode, source *airshipv1.ReplSource) (*yaml.RNode, e
 	if err != nil {
 		return nil, err
 	}
+	if sourceNode == nil {
+		return nil, ErrSourceFieldNotFound{ObjRef: source.ObjRef, FieldRef: path}
+	}
 
 	// Decoding value if source is `kind: Secret` and
 	// has fieldRef `data`
@@ -137,53 +280,115 @@ func mutateField(rnSource *yaml.RNode) func([]*yaml.RNode) error {
 	}
 }
 
-func replace(items []*yaml.RNode, target *airshipv1.ReplTarget, value *yaml.RNode) error {
+// replace substitutes the value into the fields of all resources selected by the target and
+// returns the number of updated resources and fields. Updated fields are added to the trace
+// annotation of the resources if the record is not nil
+func replace(
+	items []*yaml.RNode,
+	target *airshipv1.ReplTarget,
+	value *yaml.RNode,
+	record *trace.Record,
+) (int, int, error) {
 	targets, err := kyamlutils.DocumentSelector{}.
 		ByGVK(target.ObjRef.Group, target.ObjRef.Version, target.ObjRef.Kind).
 		ByName(target.ObjRef.Name).
+		ByNameRegex(target.NameRegex).
 		ByNamespace(target.ObjRef.Namespace).
 		ByLabel(target.ObjRef.LabelSelector).
+		ByAnnotation(target.ObjRef.AnnotationSelector).
 		Filter(items)
 	if err != nil {
-		return err
+		return 0, 0, err
 	}
 	if len(targets) == 0 {
-		return ErrTargetNotFound{ObjRef: target.ObjRef}
+		return 0, 0, ErrTargetNotFound{ObjRef: target.ObjRef}
 	}
+	objects, fields := 0, 0
 	for _, tgt := range targets {
-		for _, fieldRef := range target.FieldRefs {
-			val := value
-			// Encoding value before replacement if target is `kind: Secret`
-			// and has fieldRef `data`
-			if target.ObjRef.Gvk.Kind == secret && strings.Split(fieldRef, ".")[0] == secretData {
-				val = encodeValue(val)
-			}
-			// fieldref can contain substring pattern for regexp - we need to get it
-			groups := substringPatternRegex.FindStringSubmatch(fieldRef)
-			// if there is no substring pattern
-			if len(groups) != 3 {
-				filter := kyamlutils.JSONPathFilter{Path: fieldRef, Mutator: mutateField(val), Create: true}
-				if _, err := tgt.Pipe(filter); err != nil {
-					return err
-				}
-				continue
-			}
+		updated, err := replaceFields(tgt, target, value, record)
+		if err != nil {
+			return 0, 0, err
+		}
+		if updated > 0 {
+			objects++
+		}
+		fields += updated
+	}
+	if fields == 0 && target.Required {
+		return 0, 0, ErrTargetFieldNotFound{ObjRef: target.ObjRef, FieldRefs: target.FieldRefs}
+	}
+	return objects, fields, nil
+}
 
-			if err := substituteSubstring(tgt, groups[1], groups[2], val); err != nil {
-				return err
-			}
+func replaceFields(
+	tgt *yaml.RNode,
+	target *airshipv1.ReplTarget,
+	value *yaml.RNode,
+	record *trace.Record,
+) (int, error) {
+	updated := 0
+	var records []trace.Record
+	for _, fieldRef := range target.FieldRefs {
+		val := value
+		// Encoding value before replacement if target is `kind: Secret`
+		// and has fieldRef `data`
+		if target.ObjRef.Gvk.Kind == secret && strings.Split(fieldRef, ".")[0] == secretData {
+			val = encodeValue(val)
 		}
+		n, err := replaceField(tgt, fieldRef, val)
+		if err != nil {
+			return 0, err
+		}
+		if n > 0 && record != nil {
+			records = append(records, trace.Record{
+				Field:       fieldRef,
+				Source:      record.Source,
+				Transformer: record.Transformer,
+			})
+		}
+		updated += n
 	}
-	return nil
+	return updated, trace.Add(tgt, records...)
 }
 
-func substituteSubstring(tgt *yaml.RNode, fieldRef, substringPattern string, value *yaml.RNode) error {
+// replaceField sets the value of the field and returns the number of updated fields
+func replaceField(tgt *yaml.RNode, fieldRef string, val *yaml.RNode) (int, error) {
+	// fieldref can contain substring pattern for regexp - we need to get it
+	groups := substringPatternRegex.FindStringSubmatch(fieldRef)
+	// if there is no substring pattern
+	if len(groups) != 3 {
+		updated := 0
+		mutate := mutateField(val)
+		filter := kyamlutils.JSONPathFilter{
+			Path: fieldRef,
+			Mutator: func(rns []*yaml.RNode) error {
+				updated += len(rns)
+				return mutate(rns)
+			},
+			// fields can't be created for all elements matched by the wildcard
+			Create: !strings.Contains(fieldRef, "*"),
+		}
+		if _, err := tgt.Pipe(filter); err != nil {
+			return 0, err
+		}
+		return updated, nil
+	}
+
+	found, err := substituteSubstring(tgt, groups[1], groups[2], val)
+	if err != nil || !found {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// substituteSubstring replaces the substring of the field value and reports if the field is found
+func substituteSubstring(tgt *yaml.RNode, fieldRef, substringPattern string, value *yaml.RNode) (bool, error) {
 	if err := yaml.ErrorIfInvalid(value, yaml.ScalarNode); err != nil {
-		return err
+		return false, err
 	}
 	curVal, err := tgt.Pipe(kyamlutils.JSONPathFilter{Path: fieldRef})
 	if yaml.IsMissingOrError(curVal, err) {
-		return err
+		return false, err
 	}
 	switch curVal.YNode().Kind {
 	case yaml.ScalarNode:
@@ -193,19 +398,19 @@ func substituteSubstring(tgt *yaml.RNode, fieldRef, substringPattern string, val
 	case yaml.SequenceNode:
 		items, err := curVal.Elements()
 		if err != nil {
-			return err
+			return false, err
 		}
 		for _, item := range items {
 			if err := yaml.ErrorIfInvalid(item, yaml.ScalarNode); err != nil {
-				return err
+				return false, err
 			}
 			p := regexp.MustCompile(substringPattern)
 			item.YNode().Value = p.ReplaceAllString(yaml.GetValue(item), yaml.GetValue(value))
 		}
 	default:
-		return ErrPatternSubstring{Msg: fmt.Sprintf("value identified by %s expected to be string", fieldRef)}
+		return false, ErrPatternSubstring{Msg: fmt.Sprintf("value identified by %s expected to be string", fieldRef)}
 	}
-	return nil
+	return true, nil
 }
 
 func decodeValue(source *yaml.RNode) (*yaml.RNode, error) {
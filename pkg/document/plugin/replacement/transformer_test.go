@@ -17,6 +17,7 @@ package replacement_test
 import (
 	"bytes"
 	"fmt"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -26,6 +27,8 @@ import (
 	"sigs.k8s.io/yaml"
 
 	"opendev.org/airship/airshipctl/pkg/document/plugin/replacement"
+	"opendev.org/airship/airshipctl/pkg/document/plugin/trace"
+	"opendev.org/airship/airshipctl/pkg/log"
 )
 
 var testCases = []struct {
@@ -1362,6 +1365,409 @@ spec:
         name: init-alpine
 `,
 	},
+	{
+		cfg: `
+apiVersion: airshipit.org/v1alpha1
+kind: ReplacementTransformer
+metadata:
+  name: optional-and-default
+replacements:
+- source:
+    objref:
+      kind: ConfigMap
+      name: missing
+    fieldref: data.value
+    optional: true
+  target:
+    objref:
+      kind: ConfigMap
+      name: target
+    fieldrefs:
+    - data.optional
+- source:
+    objref:
+      kind: ConfigMap
+      name: source
+    fieldref: data.missing
+    default: fallback
+  target:
+    objref:
+      kind: ConfigMap
+      name: target
+    fieldrefs:
+    - data.default
+`,
+		in: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: source
+data:
+  cidr: 10.23.0.0/16
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: target
+data:
+  optional: unchanged
+`,
+		expectedOut: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: source
+data:
+  cidr: 10.23.0.0/16
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: target
+data:
+  optional: unchanged
+  default: fallback
+`,
+	},
+	{
+		cfg: `
+apiVersion: airshipit.org/v1alpha1
+kind: ReplacementTransformer
+metadata:
+  name: computed
+replacements:
+- source:
+    objref:
+      kind: ConfigMap
+      name: source
+    fieldref: data.cidr
+    transformations:
+    - type: nthHost
+      value: "10"
+  target:
+    objref:
+      kind: ConfigMap
+      name: target
+    fieldrefs:
+    - data.host
+- source:
+    objref:
+      kind: ConfigMap
+      name: source
+    fieldref: data.cidr
+    transformations:
+    - type: prefixLength
+    - type: prefix
+      value: /
+  target:
+    objref:
+      kind: ConfigMap
+      name: target
+    fieldrefs:
+    - data.prefix
+- source:
+    value: admin
+    transformations:
+    - type: base64Encode
+  target:
+    objref:
+      kind: ConfigMap
+      name: target
+    fieldrefs:
+    - data.encoded
+- source:
+    format: https://%s:%s
+    sources:
+    - objref:
+        kind: ConfigMap
+        name: source
+      fieldref: data.host
+    - value: "6443"
+  target:
+    objref:
+      kind: ConfigMap
+      name: target
+    fieldrefs:
+    - data.url
+`,
+		in: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: source
+data:
+  cidr: 10.23.0.0/16
+  host: api.local
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: target
+`,
+		expectedOut: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: source
+data:
+  cidr: 10.23.0.0/16
+  host: api.local
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: target
+data:
+  host: 10.23.0.10
+  prefix: /16
+  encoded: YWRtaW4=
+  url: https://api.local:6443
+`,
+	},
+	{
+		cfg: `
+apiVersion: airshipit.org/v1alpha1
+kind: ReplacementTransformer
+metadata:
+  name: missing-field
+replacements:
+- source:
+    objref:
+      kind: ConfigMap
+      name: source
+    fieldref: data.missing
+  target:
+    objref:
+      kind: ConfigMap
+      name: source
+    fieldrefs:
+    - data.value
+`,
+		in: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: source
+`,
+		expectedErr: "failed to find field data.missing in source resource identified by " +
+			"Gvk: {  ConfigMap} Name: source",
+	},
+	{
+		cfg: `
+apiVersion: airshipit.org/v1alpha1
+kind: ReplacementTransformer
+metadata:
+  name: bad-host
+replacements:
+- source:
+    value: 10.0.0.0/30
+    transformations:
+    - type: nthHost
+      value: "4"
+  target:
+    objref:
+      kind: ConfigMap
+      name: source
+    fieldrefs:
+    - data.value
+`,
+		in: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: source
+`,
+		expectedErr: "failed to apply transformation nthHost: host number 4 is out of range of 10.0.0.0/30",
+	},
+	{
+		cfg: `
+apiVersion: airshipit.org/v1alpha1
+kind: ReplacementTransformer
+metadata:
+  name: wildcard-targets
+replacements:
+- source:
+    value: nginx:1.21
+  target:
+    objref:
+      kind: Deployment
+      labelSelector: app=web
+    nameRegex: web-.*
+    fieldrefs:
+    - spec.template.spec.containers[*].image
+`,
+		in: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web-1
+  labels:
+    app: web
+spec:
+  template:
+    spec:
+      containers:
+      - image: nginx:1.19
+        name: nginx
+      - image: nginx:1.20
+        name: sidecar
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web-2
+  labels:
+    app: web
+spec:
+  template:
+    spec:
+      containers:
+      - image: nginx:1.19
+        name: nginx
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api-1
+  labels:
+    app: web
+spec:
+  template:
+    spec:
+      containers:
+      - image: nginx:1.19
+        name: nginx
+`,
+		expectedOut: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web-1
+  labels:
+    app: web
+spec:
+  template:
+    spec:
+      containers:
+      - image: nginx:1.21
+        name: nginx
+      - image: nginx:1.21
+        name: sidecar
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web-2
+  labels:
+    app: web
+spec:
+  template:
+    spec:
+      containers:
+      - image: nginx:1.21
+        name: nginx
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api-1
+  labels:
+    app: web
+spec:
+  template:
+    spec:
+      containers:
+      - image: nginx:1.19
+        name: nginx
+`,
+	},
+	{
+		cfg: `
+apiVersion: airshipit.org/v1alpha1
+kind: ReplacementTransformer
+metadata:
+  name: all-objects
+replacements:
+- source:
+    value: airship
+  target:
+    objref:
+      kind: ConfigMap
+      annotationSelector: airshipit.org/managed=true
+    allObjects: true
+    fieldrefs:
+    - data.owner
+`,
+		in: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+  annotations:
+    airshipit.org/managed: "true"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm2
+  annotations:
+    airshipit.org/managed: "true"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm3
+`,
+		expectedOut: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+  annotations:
+    airshipit.org/managed: "true"
+data:
+  owner: airship
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm2
+  annotations:
+    airshipit.org/managed: "true"
+data:
+  owner: airship
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm3
+`,
+	},
+	{
+		cfg: `
+apiVersion: airshipit.org/v1alpha1
+kind: ReplacementTransformer
+metadata:
+  name: required-target
+replacements:
+- source:
+    value: nginx:1.21
+  target:
+    objref:
+      kind: Deployment
+    required: true
+    fieldrefs:
+    - spec.template.spec.initContainers[*].image
+`,
+		in: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web-1
+spec:
+  template:
+    spec:
+      initContainers: []
+`,
+		expectedErr: "failed to find any of fields spec.template.spec.initContainers[*].image " +
+			"in target resources identified by ResID: {{  Deployment}  }",
+	},
 }
 
 func TestExec(t *testing.T) {
@@ -1393,3 +1799,263 @@ func TestExec(t *testing.T) {
 		})
 	}
 }
+
+func TestNewErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		source      string
+		expectedErr string
+	}{
+		{
+			name: "format without sources",
+			source: `
+    format: "%s"`,
+			expectedErr: "format and sources must be specified together",
+		},
+		{
+			name: "sources with value",
+			source: `
+    value: test
+    format: "%s"
+    sources:
+    - value: test`,
+			expectedErr: "sources are not allowed together with objref or value",
+		},
+		{
+			name: "optional with default",
+			source: `
+    value: test
+    optional: true
+    default: test`,
+			expectedErr: "only one of optional and default is allowed in one replacement",
+		},
+		{
+			name: "unknown transformation",
+			source: `
+    value: test
+    transformations:
+    - type: rot13`,
+			expectedErr: "unknown transformation type rot13",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := make(map[string]interface{})
+			err := yaml.Unmarshal([]byte(`
+apiVersion: airshipit.org/v1alpha1
+kind: ReplacementTransformer
+metadata:
+  name: test
+replacements:
+- source:`+tt.source+`
+  target:
+    objref:
+      kind: ConfigMap
+    fieldrefs:
+    - data.value
+`), &cfg)
+			require.NoError(t, err)
+			_, err = replacement.New(cfg)
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestNewTargetErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		target      string
+		expectedErr string
+	}{
+		{
+			name: "no objref",
+			target: `
+    fieldrefs:
+    - data.value`,
+			expectedErr: "objref must be specified in replacement target",
+		},
+		{
+			name: "name with nameRegex",
+			target: `
+    objref:
+      kind: ConfigMap
+      name: cm
+    nameRegex: ^cm`,
+			expectedErr: "only one of name and nameRegex is allowed in replacement target",
+		},
+		{
+			name: "invalid nameRegex",
+			target: `
+    objref:
+      kind: ConfigMap
+    nameRegex: "["`,
+			expectedErr: "invalid nameRegex \"[\": error parsing regexp: missing closing ]: `[`",
+		},
+		{
+			name: "allObjects without kind",
+			target: `
+    objref:
+      labelSelector: app=web
+    allObjects: true`,
+			expectedErr: "kind must be specified in objref of replacement target with allObjects",
+		},
+		{
+			name: "allObjects with nameRegex",
+			target: `
+    objref:
+      kind: ConfigMap
+    nameRegex: ^cm
+    allObjects: true`,
+			expectedErr: "name and nameRegex are not allowed in replacement target with allObjects",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := make(map[string]interface{})
+			err := yaml.Unmarshal([]byte(`
+apiVersion: airshipit.org/v1alpha1
+kind: ReplacementTransformer
+metadata:
+  name: test
+replacements:
+- source:
+    value: test
+  target:`+tt.target), &cfg)
+			require.NoError(t, err)
+			_, err = replacement.New(cfg)
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestExecTrace(t *testing.T) {
+	cfg := make(map[string]interface{})
+	require.NoError(t, yaml.Unmarshal([]byte(`
+apiVersion: airshipit.org/v1alpha1
+kind: ReplacementTransformer
+metadata:
+  name: versions
+replacements:
+- source:
+    objref:
+      kind: ConfigMap
+      name: versions
+    fieldref: data.nginx
+  target:
+    objref:
+      kind: Deployment
+    fieldrefs:
+    - spec.template.spec.containers[*].image
+    - spec.template.spec.initContainers[*].image
+`), &cfg))
+	plugin, err := replacement.New(cfg)
+	require.NoError(t, err)
+
+	items, err := kio.FromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: versions
+data:
+  nginx: nginx:1.21
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - image: nginx:1.19
+        name: nginx
+`))
+	require.NoError(t, err)
+	tracer, ok := plugin.(trace.Tracer)
+	require.True(t, ok)
+	tracer.EnableTrace()
+	nodes, err := plugin.Filter(items)
+	require.NoError(t, err)
+
+	records, err := trace.Get(nodes[1])
+	require.NoError(t, err)
+	assert.Equal(t, []trace.Record{{
+		Field:       "spec.template.spec.containers[*].image",
+		Source:      "ConfigMap/versions data.nginx",
+		Transformer: "ReplacementTransformer/versions",
+	}}, records)
+}
+
+func TestExecReport(t *testing.T) {
+	cfg := make(map[string]interface{})
+	require.NoError(t, yaml.Unmarshal([]byte(`
+apiVersion: airshipit.org/v1alpha1
+kind: ReplacementTransformer
+metadata:
+  name: versions
+report: true
+replacements:
+- source:
+    objref:
+      kind: ConfigMap
+      name: versions
+    fieldref: data.nginx
+  targets:
+  - objref:
+      kind: Deployment
+    allObjects: true
+    fieldrefs:
+    - spec.template.spec.containers[*].image
+  - objref:
+      kind: Deployment
+    allObjects: true
+    fieldrefs:
+    - spec.template.spec.initContainers[*].image
+`), &cfg))
+	plugin, err := replacement.New(cfg)
+	require.NoError(t, err)
+
+	items, err := kio.FromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: versions
+data:
+  nginx: nginx:1.21
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - image: nginx:1.19
+        name: nginx
+      - image: nginx:1.19
+        name: sidecar
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+spec:
+  template:
+    spec:
+      containers:
+      - image: nginx:1.19
+        name: nginx
+`))
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	log.Init(false, buf)
+	defer log.Init(false, os.Stderr)
+	_, err = plugin.Filter(items)
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "replacement 0 updated 3 field(s) in 2 resource(s) identified by")
+	assert.Contains(t, buf.String(), "replacement 0 updated 0 field(s) in 0 resource(s) identified by")
+}
@@ -0,0 +1,66 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package sops
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+// parseAgeIdentities parses age identities in the keys file format, i.e. AGE-SECRET-KEY-1... lines
+// where empty lines and comments starting with # are ignored
+func parseAgeIdentities(data string) ([]age.Identity, error) {
+	if strings.TrimSpace(data) == "" {
+		return nil, nil
+	}
+	return age.ParseIdentities(strings.NewReader(data))
+}
+
+// ageEncrypt encrypts plaintext to X25519 recipient and returns ASCII armored age file
+func ageEncrypt(recipient string, plaintext []byte) (string, error) {
+	r, err := age.ParseX25519Recipient(strings.TrimSpace(recipient))
+	if err != nil {
+		return "", err
+	}
+	buf := &bytes.Buffer{}
+	armored := armor.NewWriter(buf)
+	w, err := age.Encrypt(armored, r)
+	if err != nil {
+		return "", err
+	}
+	if _, err = w.Write(plaintext); err != nil {
+		return "", err
+	}
+	if err = w.Close(); err != nil {
+		return "", err
+	}
+	if err = armored.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// ageDecrypt decrypts ASCII armored age file with any of the identities
+func ageDecrypt(identities []age.Identity, armored string) ([]byte, error) {
+	r, err := age.Decrypt(armor.NewReader(strings.NewReader(strings.TrimSpace(armored))), identities...)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(r)
+}
@@ -0,0 +1,98 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package sops
+
+import (
+	"bytes"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAgeIdentity  = "AGE-SECRET-KEY-1E4E344F0C2JGHVX4ZCZY6SJ50TXSHEFMGTUEKWJT8XR54W0LZV8S3Q8AD4"
+	testAgeRecipient = "age14gz065vd3glaexpmfrg49c50p6vvjwcvktpzq767ggl9j726lyzsva0thd"
+	// testAgeFile is encrypted to testAgeRecipient by the reference age implementation
+	testAgeFile = `-----BEGIN AGE ENCRYPTED FILE-----
+YWdlLWVuY3J5cHRpb24ub3JnL3YxCi0+IFgyNTUxOSBNWWkxVDgrUEFOczdTSW9T
+R3ZLYVZiMHA4U3V5dnJGN2lDWTZGRkVTZTBrClpueFJuOGdvSGVldmtGc3BXODBO
+TzRnUGs5MmlqM2hPYWtkOGFlcE1SUFUKLS0tIDFCdTMrVjUxVGVBMXVqQ3RDYk1j
+THAwbm5KUzZEbUJPYmc2Nm9iaVFjVzgKa+dtvEvSHiwmTYhRtKQsbeaPxiwspgNS
+zlNZ1dgPgw2i2mFGizY8FmJzC1RZsRmDaVpT4BWlQtJBiAgET8C9kA==
+-----END AGE ENCRYPTED FILE-----
+`
+)
+
+func TestParseAgeIdentities(t *testing.T) {
+	identities, err := parseAgeIdentities("# created: 2021-06-01\n\n" + testAgeIdentity + "\n")
+	require.NoError(t, err)
+	require.Len(t, identities, 1)
+	assert.Equal(t, testAgeRecipient, identities[0].(*age.X25519Identity).Recipient().String())
+
+	identities, err = parseAgeIdentities("\n")
+	require.NoError(t, err)
+	assert.Empty(t, identities)
+
+	_, err = parseAgeIdentities(testAgeIdentity[:len(testAgeIdentity)-1] + "Q")
+	assert.EqualError(t, err, "error at line 1: malformed secret key: invalid checksum")
+
+	_, err = parseAgeIdentities(testAgeRecipient)
+	assert.EqualError(t, err, "error at line 1: malformed secret key: unknown type \"age\"")
+
+	_, err = ageEncrypt(testAgeIdentity, nil)
+	assert.EqualError(t, err, "malformed recipient \""+testAgeIdentity+"\": invalid type \"AGE-SECRET-KEY-\"")
+}
+
+func TestAgeDecrypt(t *testing.T) {
+	identities, err := parseAgeIdentities(testAgeIdentity)
+	require.NoError(t, err)
+
+	plaintext, err := ageDecrypt(identities, testAgeFile)
+	require.NoError(t, err)
+	assert.Equal(t, bytes.Repeat([]byte("x"), 32), plaintext)
+
+	_, err = ageDecrypt(nil, testAgeFile)
+	assert.EqualError(t, err, "no identities specified")
+
+	other, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	_, err = ageDecrypt([]age.Identity{other}, testAgeFile)
+	assert.EqualError(t, err, "no identity matched any of the recipients")
+
+	_, err = ageDecrypt(identities, "not armored")
+	assert.EqualError(t, err,
+		"failed to read header: parsing age header: failed to read intro: invalid armor first line: not armored")
+}
+
+func TestAgeEncrypt(t *testing.T) {
+	identities, err := parseAgeIdentities(testAgeIdentity)
+	require.NoError(t, err)
+
+	// cover empty payload, multiple chunks and payload of exactly one chunk
+	const chunkSize = 64 * 1024
+	for _, size := range []int{0, 32, chunkSize, chunkSize*2 + 1} {
+		plaintext := bytes.Repeat([]byte("y"), size)
+		armored, err := ageEncrypt(testAgeRecipient, plaintext)
+		require.NoError(t, err)
+		decrypted, err := ageDecrypt(identities, armored)
+		require.NoError(t, err)
+		assert.Equal(t, string(plaintext), string(decrypted))
+	}
+
+	_, err = ageEncrypt("age1invalid", nil)
+	assert.Error(t, err)
+}
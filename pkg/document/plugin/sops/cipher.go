@@ -0,0 +1,102 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package sops
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+)
+
+const (
+	// dataKeySize is the size of AES-256 data key used to encrypt document values
+	dataKeySize = 32
+	// nonceSize is the size of GCM nonce used by SOPS
+	nonceSize = 32
+)
+
+var encryptedValue = regexp.MustCompile(`^ENC\[AES256_GCM,data:(.*),iv:(.*),tag:(.*),type:(.*)\]$`)
+
+// isEncrypted returns true if the value looks like SOPS encrypted one
+func isEncrypted(value string) bool {
+	return encryptedValue.MatchString(value)
+}
+
+// encryptValue encrypts plaintext representation of the value with AES-GCM, additional data
+// authenticates the location of the value in the document the same way SOPS does
+func encryptValue(key []byte, plaintext, valueType, additionalData string) (string, error) {
+	// SOPS leaves empty strings as is
+	if plaintext == "" && valueType == typeString {
+		return "", nil
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	iv := make([]byte, nonceSize)
+	if _, err = rand.Read(iv); err != nil {
+		return "", err
+	}
+	out := gcm.Seal(nil, iv, []byte(plaintext), []byte(additionalData))
+	tagStart := len(out) - gcm.Overhead()
+	return fmt.Sprintf("ENC[AES256_GCM,data:%s,iv:%s,tag:%s,type:%s]",
+		base64.StdEncoding.EncodeToString(out[:tagStart]),
+		base64.StdEncoding.EncodeToString(iv),
+		base64.StdEncoding.EncodeToString(out[tagStart:]),
+		valueType), nil
+}
+
+// decryptValue returns plaintext representation and the type of encrypted value
+func decryptValue(key []byte, value, additionalData string) (string, string, error) {
+	if value == "" {
+		return "", typeString, nil
+	}
+	parts := encryptedValue.FindStringSubmatch(value)
+	if parts == nil {
+		return "", "", ErrMalformedValue{Value: value}
+	}
+	var data [3][]byte
+	for i := range data {
+		decoded, err := base64.StdEncoding.DecodeString(parts[i+1])
+		if err != nil {
+			return "", "", ErrMalformedValue{Value: value}
+		}
+		data[i] = decoded
+	}
+	ciphertext, iv, tag := data[0], data[1], data[2]
+	if len(iv) != nonceSize {
+		return "", "", ErrMalformedValue{Value: value}
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", "", err
+	}
+	plaintext, err := gcm.Open(nil, iv, append(ciphertext, tag...), []byte(additionalData))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return string(plaintext), parts[4], nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCMWithNonceSize(block, nonceSize)
+}
@@ -0,0 +1,89 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package sops
+
+import (
+	"fmt"
+)
+
+// ErrBadConfiguration returned in case of function misconfiguration
+type ErrBadConfiguration struct {
+	Msg string
+}
+
+func (e ErrBadConfiguration) Error() string {
+	return e.Msg
+}
+
+// ErrMalformedValue returned if encrypted value doesn't match SOPS format
+type ErrMalformedValue struct {
+	Value string
+}
+
+func (e ErrMalformedValue) Error() string {
+	return fmt.Sprintf("value %q is not a valid SOPS encrypted value", e.Value)
+}
+
+// ErrDataKeyNotFound returned if none of the available keys can decrypt the data key of the document
+type ErrDataKeyNotFound struct {
+	Errors []error
+}
+
+func (e ErrDataKeyNotFound) Error() string {
+	if len(e.Errors) == 0 {
+		return "failed to decrypt data key: no age identities or PGP keys are available"
+	}
+	return fmt.Sprintf("failed to decrypt data key with any of the available keys: %v", e.Errors)
+}
+
+// ErrMACMismatch returned if message authentication code of decrypted document doesn't match stored one
+type ErrMACMismatch struct {
+	Expected string
+	Actual   string
+}
+
+func (e ErrMACMismatch) Error() string {
+	return fmt.Sprintf("MAC mismatch: stored %s, computed %s", e.Expected, e.Actual)
+}
+
+// ErrKeyGroupsNotSupported returned if the document is encrypted with SOPS key groups
+type ErrKeyGroupsNotSupported struct{}
+
+func (e ErrKeyGroupsNotSupported) Error() string {
+	return "key groups not supported, documents encrypted with SOPS key groups must be decrypted with sops binary"
+}
+
+// ErrNoRecipients returned if there is no age recipient or PGP fingerprint to encrypt the document for
+type ErrNoRecipients struct{}
+
+func (e ErrNoRecipients) Error() string {
+	return "no age recipients or PGP fingerprints to encrypt data key for, set SOPS_AGE_RECIPIENTS or SOPS_PGP_FP"
+}
+
+// ErrDocument wraps encryption or decryption error of a particular document
+type ErrDocument struct {
+	ID  string
+	Cmd string
+	Err error
+}
+
+func (e ErrDocument) Error() string {
+	return fmt.Sprintf("failed to %s document %s: %v", e.Cmd, e.ID, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e ErrDocument) Unwrap() error {
+	return e.Err
+}
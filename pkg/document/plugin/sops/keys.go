@@ -0,0 +1,170 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package sops
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/ProtonMail/go-crypto/openpgp"
+)
+
+const (
+	// EnvAgeKey is the environment variable holding age identities
+	EnvAgeKey = "SOPS_AGE_KEY"
+	// EnvAgeKeyFile is the environment variable holding path to the file with age identities,
+	// sops/age/keys.txt in user config directory is used if it's not set
+	EnvAgeKeyFile = "SOPS_AGE_KEY_FILE"
+	// EnvAgeRecipients is the environment variable holding comma separated age recipients
+	// the documents are encrypted for
+	EnvAgeRecipients = "SOPS_AGE_RECIPIENTS"
+	// EnvPGPKeys is the environment variable holding ASCII armored PGP keys, the same one
+	// is used to import keys into SOPS function container
+	EnvPGPKeys = "SOPS_IMPORT_PGP"
+	// EnvPGPFingerprints is the environment variable holding comma separated fingerprints of
+	// PGP keys the documents are encrypted for
+	EnvPGPFingerprints = "SOPS_PGP_FP"
+)
+
+// Keys holds age identities and PGP keys available in the local environment
+type Keys struct {
+	ageIdentities []age.Identity
+	pgpKeyring    openpgp.EntityList
+
+	// AgeRecipients are age1... recipients used to encrypt data keys
+	AgeRecipients []string
+	// PGPFingerprints are fingerprints of PGP keys used to encrypt data keys
+	PGPFingerprints []string
+}
+
+// LoadKeys reads keys from the environment, missing keys are not an error since
+// encryption or decryption reports it only if the keys are actually needed
+func LoadKeys() (*Keys, error) {
+	keys := &Keys{
+		AgeRecipients:   splitList(os.Getenv(EnvAgeRecipients)),
+		PGPFingerprints: splitList(os.Getenv(EnvPGPFingerprints)),
+	}
+	if err := keys.AddAgeIdentities(os.Getenv(EnvAgeKey)); err != nil {
+		return nil, err
+	}
+
+	keyFile := os.Getenv(EnvAgeKeyFile)
+	if keyFile == "" {
+		if configDir, err := os.UserConfigDir(); err == nil {
+			keyFile = filepath.Join(configDir, "sops", "age", "keys.txt")
+		}
+	}
+	if keyFile != "" {
+		data, err := ioutil.ReadFile(keyFile)
+		switch {
+		case os.IsNotExist(err) && os.Getenv(EnvAgeKeyFile) == "":
+		case err != nil:
+			return nil, err
+		default:
+			if err = keys.AddAgeIdentities(string(data)); err != nil {
+				return nil, fmt.Errorf("failed to read age identities from %s: %w", keyFile, err)
+			}
+		}
+	}
+
+	if err := keys.AddPGPKeys(os.Getenv(EnvPGPKeys)); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// AddAgeIdentities adds age identities in the keys file format
+func (k *Keys) AddAgeIdentities(data string) error {
+	identities, err := parseAgeIdentities(data)
+	if err != nil {
+		return err
+	}
+	k.ageIdentities = append(k.ageIdentities, identities...)
+	return nil
+}
+
+// AddPGPKeys adds ASCII armored PGP public or private keys
+func (k *Keys) AddPGPKeys(armored string) error {
+	if strings.TrimSpace(armored) == "" {
+		return nil
+	}
+	keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armored))
+	if err != nil {
+		return fmt.Errorf("failed to read PGP keys: %w", err)
+	}
+	k.pgpKeyring = append(k.pgpKeyring, keyring...)
+	return nil
+}
+
+// decryptDataKey decrypts the data key with the first key matching one of the document recipients
+func (k *Keys) decryptDataKey(meta *metadata) ([]byte, error) {
+	var errs []error
+	if len(k.ageIdentities) > 0 {
+		for _, key := range meta.Age {
+			dataKey, err := ageDecrypt(k.ageIdentities, key.Enc)
+			if err == nil {
+				return dataKey, nil
+			}
+			errs = append(errs, fmt.Errorf("age recipient %s: %w", key.Recipient, err))
+		}
+	}
+	if len(k.pgpKeyring) > 0 {
+		for _, key := range meta.PGP {
+			dataKey, err := pgpDecrypt(k.pgpKeyring, key.Enc)
+			if err == nil {
+				return dataKey, nil
+			}
+			errs = append(errs, fmt.Errorf("PGP key %s: %w", key.Fingerprint, err))
+		}
+	}
+	return nil, ErrDataKeyNotFound{Errors: errs}
+}
+
+// encryptDataKey encrypts the data key for all of the recipients and fills in metadata
+func (k *Keys) encryptDataKey(dataKey []byte, meta *metadata, createdAt string) error {
+	if len(k.AgeRecipients) == 0 && len(k.PGPFingerprints) == 0 {
+		return ErrNoRecipients{}
+	}
+	for _, recipient := range k.AgeRecipients {
+		enc, err := ageEncrypt(recipient, dataKey)
+		if err != nil {
+			return err
+		}
+		meta.Age = append(meta.Age, ageKey{Recipient: recipient, Enc: enc})
+	}
+	for _, fp := range k.PGPFingerprints {
+		enc, err := pgpEncrypt(k.pgpKeyring, fp, dataKey)
+		if err != nil {
+			return err
+		}
+		meta.PGP = append(meta.PGP, pgpKey{CreatedAt: createdAt, Enc: enc, Fingerprint: fp})
+	}
+	return nil
+}
+
+// splitList splits comma separated list ignoring empty elements
+func splitList(s string) []string {
+	var result []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
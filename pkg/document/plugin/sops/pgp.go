@@ -0,0 +1,81 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package sops
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+)
+
+// pgpFingerprint returns upper cased hex fingerprint of the key
+func pgpFingerprint(key *openpgp.Entity) string {
+	return fmt.Sprintf("%X", key.PrimaryKey.Fingerprint[:])
+}
+
+// pgpEncrypt encrypts plaintext for the key with fingerprint and returns ASCII armored PGP message
+func pgpEncrypt(keyring openpgp.EntityList, fingerprint string, plaintext []byte) (string, error) {
+	var recipient *openpgp.Entity
+	for _, key := range keyring {
+		if strings.EqualFold(pgpFingerprint(key), fingerprint) {
+			recipient = key
+			break
+		}
+	}
+	if recipient == nil {
+		return "", fmt.Errorf("PGP key with fingerprint %s is not found", fingerprint)
+	}
+
+	buf := &bytes.Buffer{}
+	armored, err := armor.Encode(buf, "PGP MESSAGE", nil)
+	if err != nil {
+		return "", err
+	}
+	w, err := openpgp.Encrypt(armored, []*openpgp.Entity{recipient}, nil, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	if _, err = w.Write(plaintext); err != nil {
+		return "", err
+	}
+	if err = w.Close(); err != nil {
+		return "", err
+	}
+	if err = armored.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// pgpDecrypt decrypts ASCII armored PGP message with private keys of the keyring
+func pgpDecrypt(keyring openpgp.EntityList, message string) ([]byte, error) {
+	if len(keyring.DecryptionKeys()) == 0 {
+		return nil, errors.New("no PGP private keys are available")
+	}
+	block, err := armor.Decode(strings.NewReader(message))
+	if err != nil {
+		return nil, err
+	}
+	md, err := openpgp.ReadMessage(block.Body, keyring, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(md.UnverifiedBody)
+}
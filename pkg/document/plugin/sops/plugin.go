@@ -0,0 +1,158 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package sops implements SOPS compatible encryption of documents, so that encrypted secrets are
+// decrypted in-process while bundle is built and generated secrets are encrypted before they are
+// written to disk. Data keys are encrypted with age X25519 or PGP keys from the local environment
+package sops
+
+import (
+	"fmt"
+	"strconv"
+
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+
+	"opendev.org/airship/airshipctl/pkg/document/plugin/trace"
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+const (
+	// CmdDecrypt decrypts SOPS encrypted documents
+	CmdDecrypt = "decrypt"
+	// CmdEncrypt encrypts plaintext documents
+	CmdEncrypt = "encrypt"
+)
+
+var _ kio.Filter = &plugin{}
+
+// Config is the configuration of SOPS function. It's read from the data of the ConfigMap passed to
+// the function, the keys are the same as the ones accepted by gcr.io/kpt-fn-contrib/sops image
+type Config struct {
+	// Cmd is either decrypt or encrypt
+	Cmd string
+	// TolerateFailures leaves the documents which can't be processed intact instead of failing
+	TolerateFailures bool
+	// IgnoreMAC skips verification of message authentication code of decrypted documents
+	IgnoreMAC bool
+	// Age are age recipients the documents are encrypted for in addition to SOPS_AGE_RECIPIENTS
+	Age []string
+	// PGP are fingerprints of PGP keys the documents are encrypted for in addition to SOPS_PGP_FP
+	PGP []string
+	Rules
+}
+
+type plugin struct {
+	Config
+}
+
+// New creates SOPS function from the function config, the config is a ConfigMap with the data like
+//
+//	cmd: decrypt
+//	cmd-tolerate-failures: true
+//	ignore-mac: true
+//	unencrypted-regex: ^(kind|apiVersion|metadata)$
+//
+// Keys which are specific to SOPS container, e.g. override-preexec-cmd, are ignored
+func New(obj map[string]interface{}) (kio.Filter, error) {
+	data, _ := obj["data"].(map[string]interface{})
+	value := func(key string) string {
+		if v, ok := data[key]; ok && v != nil {
+			return fmt.Sprint(v)
+		}
+		return ""
+	}
+	flag := func(key string) (bool, error) {
+		v := value(key)
+		if v == "" {
+			return false, nil
+		}
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return false, ErrBadConfiguration{Msg: fmt.Sprintf("%s must be a boolean, got %q", key, v)}
+		}
+		return b, nil
+	}
+
+	cfg := Config{
+		Cmd: value("cmd"),
+		Age: splitList(value("age")),
+		PGP: splitList(value("pgp")),
+		Rules: Rules{
+			UnencryptedSuffix: value("unencrypted-suffix"),
+			EncryptedSuffix:   value("encrypted-suffix"),
+			UnencryptedRegex:  value("unencrypted-regex"),
+			EncryptedRegex:    value("encrypted-regex"),
+		},
+	}
+	if cfg.Cmd != CmdDecrypt && cfg.Cmd != CmdEncrypt {
+		return nil, ErrBadConfiguration{
+			Msg: fmt.Sprintf("unsupported cmd %q, allowed values are %s|%s", cfg.Cmd, CmdDecrypt, CmdEncrypt)}
+	}
+	var err error
+	for key, dst := range map[string]*bool{
+		"cmd-tolerate-failures": &cfg.TolerateFailures,
+		"ignore-mac":            &cfg.IgnoreMAC,
+		"mac-only-encrypted":    &cfg.MACOnlyEncrypted,
+	} {
+		if *dst, err = flag(key); err != nil {
+			return nil, err
+		}
+	}
+	return &plugin{Config: cfg}, nil
+}
+
+// Sensitive returns true since the documents processed by the function may be left in plaintext,
+// either decrypted or not yet encrypted, so bundles built with the function must not be cached
+func (p *plugin) Sensitive() bool {
+	return true
+}
+
+// Filter decrypts or encrypts the documents, already encrypted documents are skipped on encryption
+// and plaintext ones are skipped on decryption
+func (p *plugin) Filter(items []*yaml.RNode) ([]*yaml.RNode, error) {
+	keys, err := LoadKeys()
+	if err != nil {
+		if p.TolerateFailures {
+			log.Printf("Failed to load SOPS keys, documents are left intact: %v", err)
+			return items, nil
+		}
+		return nil, err
+	}
+	keys.AgeRecipients = append(keys.AgeRecipients, p.Age...)
+	keys.PGPFingerprints = append(keys.PGPFingerprints, p.PGP...)
+
+	processed := 0
+	for _, item := range items {
+		if IsEncrypted(item) != (p.Cmd == CmdDecrypt) {
+			continue
+		}
+		if p.Cmd == CmdDecrypt {
+			err = Decrypt(item, keys, p.IgnoreMAC)
+		} else {
+			err = Encrypt(item, keys, p.Rules)
+		}
+		if err == nil {
+			processed++
+			continue
+		}
+		err = ErrDocument{ID: trace.ID(item), Cmd: p.Cmd, Err: err}
+		if !p.TolerateFailures {
+			return nil, err
+		}
+		log.Printf("%v, the document is left intact", err)
+	}
+	log.Debugf("SOPS function: %sed %d document(s) in-process", p.Cmd, processed)
+	return items, nil
+}
@@ -0,0 +1,368 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package sops
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const (
+	// MetadataKey is the top level key of the document holding SOPS metadata
+	MetadataKey = "sops"
+	// DefaultUnencryptedSuffix is the suffix of keys which are left in plaintext if no rules are set
+	DefaultUnencryptedSuffix = "_unencrypted"
+
+	// version of SOPS the metadata is compatible with
+	version = "3.7.1"
+
+	typeString = "str"
+	typeInt    = "int"
+	typeFloat  = "float"
+	typeBool   = "bool"
+)
+
+type ageKey struct {
+	Recipient string `yaml:"recipient"`
+	Enc       string `yaml:"enc"`
+}
+
+type pgpKey struct {
+	CreatedAt   string `yaml:"created_at"`
+	Enc         string `yaml:"enc"`
+	Fingerprint string `yaml:"fp"`
+}
+
+// metadata is SOPS metadata of the document, unsupported key services are kept to stay compatible with SOPS
+type metadata struct {
+	KMS               []interface{} `yaml:"kms"`
+	GCPKMS            []interface{} `yaml:"gcp_kms"`
+	AzureKV           []interface{} `yaml:"azure_kv"`
+	HCVault           []interface{} `yaml:"hc_vault"`
+	Age               []ageKey      `yaml:"age,omitempty"`
+	LastModified      string        `yaml:"lastmodified"`
+	MAC               string        `yaml:"mac"`
+	PGP               []pgpKey      `yaml:"pgp,omitempty"`
+	KeyGroups         []interface{} `yaml:"key_groups,omitempty"`
+	UnencryptedSuffix string        `yaml:"unencrypted_suffix,omitempty"`
+	EncryptedSuffix   string        `yaml:"encrypted_suffix,omitempty"`
+	UnencryptedRegex  string        `yaml:"unencrypted_regex,omitempty"`
+	EncryptedRegex    string        `yaml:"encrypted_regex,omitempty"`
+	MACOnlyEncrypted  bool          `yaml:"mac_only_encrypted,omitempty"`
+	Version           string        `yaml:"version"`
+}
+
+// Rules select the values of the document to be encrypted, the same way SOPS command line flags do.
+// Each of the rules is matched against the keys on the path to the value
+type Rules struct {
+	// UnencryptedSuffix leaves values in plaintext if any of the keys has the suffix
+	UnencryptedSuffix string
+	// EncryptedSuffix encrypts only the values if any of the keys has the suffix
+	EncryptedSuffix string
+	// UnencryptedRegex leaves values in plaintext if any of the keys matches the regex
+	UnencryptedRegex string
+	// EncryptedRegex encrypts only the values if any of the keys matches the regex
+	EncryptedRegex string
+	// MACOnlyEncrypted excludes plaintext values from the message authentication code
+	MACOnlyEncrypted bool
+}
+
+// IsEncrypted returns true if the document contains SOPS metadata
+func IsEncrypted(rn *yaml.RNode) bool {
+	return rn.YNode().Kind == yaml.MappingNode && rn.Field(MetadataKey) != nil
+}
+
+// Decrypt decrypts SOPS encrypted document in place and removes its metadata, the document
+// is left intact in case of error. MAC verification is skipped if ignoreMAC is set
+func Decrypt(rn *yaml.RNode, keys *Keys, ignoreMAC bool) error {
+	field := rn.Field(MetadataKey)
+	if field == nil {
+		return ErrBadConfiguration{Msg: "document is not encrypted"}
+	}
+	meta := &metadata{}
+	if err := field.Value.YNode().Decode(meta); err != nil {
+		return fmt.Errorf("malformed SOPS metadata: %w", err)
+	}
+	// data key is split between key groups with Shamir's secret sharing, recipients aren't listed at the top level
+	if len(meta.KeyGroups) > 0 {
+		return ErrKeyGroupsNotSupported{}
+	}
+	matcher, err := newMatcher(rulesOf(meta))
+	if err != nil {
+		return err
+	}
+	dataKey, err := keys.decryptDataKey(meta)
+	if err != nil {
+		return err
+	}
+
+	doc := rn.Copy()
+	hash := sha512.New()
+	err = walk(doc.YNode(), nil, func(node *yaml.Node, path []string) error {
+		encrypted := matcher.encrypted(path)
+		if encrypted {
+			value, valueType, err := decryptValue(dataKey, node.Value, additionalData(path))
+			if err != nil {
+				return fmt.Errorf("field %s: %w", strings.Join(path, "."), err)
+			}
+			setValue(node, value, valueType)
+		}
+		if encrypted || !meta.MACOnlyEncrypted {
+			hash.Write(macBytes(node)) //nolint:errcheck
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !ignoreMAC {
+		lastModified, err := time.Parse(time.RFC3339, meta.LastModified)
+		if err != nil {
+			return fmt.Errorf("malformed SOPS metadata: %w", err)
+		}
+		stored, _, err := decryptValue(dataKey, meta.MAC, lastModified.Format(time.RFC3339))
+		if err != nil {
+			return fmt.Errorf("failed to decrypt MAC: %w", err)
+		}
+		if computed := fmt.Sprintf("%X", hash.Sum(nil)); stored != computed {
+			return ErrMACMismatch{Expected: stored, Actual: computed}
+		}
+	}
+
+	if _, err = doc.Pipe(yaml.Clear(MetadataKey)); err != nil {
+		return err
+	}
+	rn.SetYNode(doc.YNode())
+	return nil
+}
+
+// Encrypt encrypts values of the document selected by rules with a new data key and adds SOPS metadata.
+// The data key is encrypted for age recipients and PGP fingerprints of the keys
+func Encrypt(rn *yaml.RNode, keys *Keys, rules Rules) error {
+	if IsEncrypted(rn) {
+		return ErrBadConfiguration{Msg: "document is already encrypted"}
+	}
+	if rules == (Rules{MACOnlyEncrypted: rules.MACOnlyEncrypted}) {
+		rules.UnencryptedSuffix = DefaultUnencryptedSuffix
+	}
+	matcher, err := newMatcher(rules)
+	if err != nil {
+		return err
+	}
+
+	lastModified := time.Now().UTC().Format(time.RFC3339)
+	meta := &metadata{
+		LastModified:      lastModified,
+		UnencryptedSuffix: rules.UnencryptedSuffix,
+		EncryptedSuffix:   rules.EncryptedSuffix,
+		UnencryptedRegex:  rules.UnencryptedRegex,
+		EncryptedRegex:    rules.EncryptedRegex,
+		MACOnlyEncrypted:  rules.MACOnlyEncrypted,
+		Version:           version,
+	}
+	dataKey := make([]byte, dataKeySize)
+	if _, err = rand.Read(dataKey); err != nil {
+		return err
+	}
+	if err = keys.encryptDataKey(dataKey, meta, lastModified); err != nil {
+		return err
+	}
+
+	doc := rn.Copy()
+	hash := sha512.New()
+	err = walk(doc.YNode(), nil, func(node *yaml.Node, path []string) error {
+		encrypted := matcher.encrypted(path)
+		if encrypted || !meta.MACOnlyEncrypted {
+			hash.Write(macBytes(node)) //nolint:errcheck
+		}
+		if !encrypted {
+			return nil
+		}
+		valueType, plaintext := plaintextOf(node)
+		value, err := encryptValue(dataKey, plaintext, valueType, additionalData(path))
+		if err != nil {
+			return err
+		}
+		setValue(node, value, typeString)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if meta.MAC, err = encryptValue(dataKey, fmt.Sprintf("%X", hash.Sum(nil)), typeString, lastModified); err != nil {
+		return err
+	}
+
+	metaNode := yaml.NewRNode(&yaml.Node{})
+	if err = metaNode.YNode().Encode(meta); err != nil {
+		return err
+	}
+	if err = doc.PipeE(yaml.SetField(MetadataKey, metaNode)); err != nil {
+		return err
+	}
+	rn.SetYNode(doc.YNode())
+	return nil
+}
+
+// walk calls fn for every non null scalar of the document except SOPS metadata in the document order.
+// Path contains the keys of the mappings leading to the value, sequence indexes are not included as SOPS does
+func walk(node *yaml.Node, path []string, fn func(*yaml.Node, []string) error) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, n := range node.Content {
+			if err := walk(n, path, fn); err != nil {
+				return err
+			}
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if len(path) == 0 && key == MetadataKey {
+				continue
+			}
+			if err := walk(node.Content[i+1], append(path[:len(path):len(path)], key), fn); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		for _, n := range node.Content {
+			if err := walk(n, path, fn); err != nil {
+				return err
+			}
+		}
+	case yaml.ScalarNode:
+		if node.Tag == yaml.NodeTagNull {
+			return nil
+		}
+		return fn(node, path)
+	}
+	return nil
+}
+
+// additionalData returns the data authenticating the location of the value
+func additionalData(path []string) string {
+	return strings.Join(path, ":") + ":"
+}
+
+// plaintextOf returns SOPS type and plaintext representation of the scalar
+func plaintextOf(node *yaml.Node) (string, string) {
+	switch node.Tag {
+	case yaml.NodeTagInt:
+		if i, err := strconv.ParseInt(node.Value, 0, 64); err == nil {
+			return typeInt, strconv.FormatInt(i, 10)
+		}
+	case yaml.NodeTagFloat:
+		if f, err := strconv.ParseFloat(node.Value, 64); err == nil {
+			return typeFloat, strconv.FormatFloat(f, 'f', -1, 64)
+		}
+	case yaml.NodeTagBool:
+		if b, err := strconv.ParseBool(node.Value); err == nil {
+			return typeBool, strconv.FormatBool(b)
+		}
+	}
+	return typeString, node.Value
+}
+
+// macBytes returns representation of the value used to compute MAC, booleans are capitalized
+// for compatibility with the original Python implementation of SOPS
+func macBytes(node *yaml.Node) []byte {
+	valueType, plaintext := plaintextOf(node)
+	if valueType == typeBool {
+		return []byte(strings.Title(plaintext))
+	}
+	return []byte(plaintext)
+}
+
+func setValue(node *yaml.Node, value, valueType string) {
+	node.Value = value
+	node.Style = 0
+	switch valueType {
+	case typeInt:
+		node.Tag = yaml.NodeTagInt
+	case typeFloat:
+		node.Tag = yaml.NodeTagFloat
+	case typeBool:
+		node.Tag = yaml.NodeTagBool
+	default:
+		node.Tag = yaml.NodeTagString
+	}
+}
+
+func rulesOf(meta *metadata) Rules {
+	return Rules{
+		UnencryptedSuffix: meta.UnencryptedSuffix,
+		EncryptedSuffix:   meta.EncryptedSuffix,
+		UnencryptedRegex:  meta.UnencryptedRegex,
+		EncryptedRegex:    meta.EncryptedRegex,
+		MACOnlyEncrypted:  meta.MACOnlyEncrypted,
+	}
+}
+
+// matcher decides whether the value is encrypted according to the rules
+type matcher struct {
+	Rules
+	unencryptedRegex *regexp.Regexp
+	encryptedRegex   *regexp.Regexp
+}
+
+func newMatcher(rules Rules) (*matcher, error) {
+	m := &matcher{Rules: rules}
+	var err error
+	if rules.UnencryptedRegex != "" {
+		if m.unencryptedRegex, err = regexp.Compile(rules.UnencryptedRegex); err != nil {
+			return nil, ErrBadConfiguration{Msg: fmt.Sprintf("invalid unencrypted regex: %v", err)}
+		}
+	}
+	if rules.EncryptedRegex != "" {
+		if m.encryptedRegex, err = regexp.Compile(rules.EncryptedRegex); err != nil {
+			return nil, ErrBadConfiguration{Msg: fmt.Sprintf("invalid encrypted regex: %v", err)}
+		}
+	}
+	return m, nil
+}
+
+// encrypted applies rules in the same order as SOPS does, so that the later rules take precedence
+func (m *matcher) encrypted(path []string) bool {
+	anyKey := func(match func(string) bool) bool {
+		for _, key := range path {
+			if match(key) {
+				return true
+			}
+		}
+		return false
+	}
+	encrypted := true
+	if m.UnencryptedSuffix != "" && anyKey(func(k string) bool { return strings.HasSuffix(k, m.UnencryptedSuffix) }) {
+		encrypted = false
+	}
+	if m.EncryptedSuffix != "" {
+		encrypted = anyKey(func(k string) bool { return strings.HasSuffix(k, m.EncryptedSuffix) })
+	}
+	if m.unencryptedRegex != nil && anyKey(m.unencryptedRegex.MatchString) {
+		encrypted = false
+	}
+	if m.encryptedRegex != nil {
+		encrypted = anyKey(m.encryptedRegex.MatchString)
+	}
+	return encrypted
+}
@@ -0,0 +1,285 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package sops_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+
+	"opendev.org/airship/airshipctl/pkg/document/plugin/sops"
+)
+
+const (
+	testAgeIdentity  = "AGE-SECRET-KEY-1E4E344F0C2JGHVX4ZCZY6SJ50TXSHEFMGTUEKWJT8XR54W0LZV8S3Q8AD4"
+	testAgeRecipient = "age14gz065vd3glaexpmfrg49c50p6vvjwcvktpzq767ggl9j726lyzsva0thd"
+
+	secret = `apiVersion: v1
+kind: Secret
+metadata:
+  name: db
+  annotations:
+    airshipit.org/secret: "true"
+type: Opaque
+stringData:
+  password: s3cr3t
+  port: 5432
+  ratio: 0.5
+  enabled: false
+  empty: ""
+  hosts:
+  - db-0
+  - db-1
+  note_unencrypted: visible
+`
+)
+
+// testPGPKey returns fingerprint and ASCII armored private key of a new PGP key
+func testPGPKey(t *testing.T) (string, string) {
+	t.Helper()
+	entity, err := openpgp.NewEntity("airshipctl", "test", "test@airshipit.org", nil)
+	require.NoError(t, err)
+	// keys generated by gpg have algorithm preferences, the default ones require RIPEMD160
+	for _, id := range entity.Identities {
+		id.SelfSignature.PreferredHash = []uint8{8} // SHA256
+	}
+	buf := &bytes.Buffer{}
+	w, err := armor.Encode(buf, openpgp.PrivateKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.SerializePrivate(w, nil))
+	require.NoError(t, w.Close())
+	return fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint[:]), buf.String()
+}
+
+func ageKeys(t *testing.T) *sops.Keys {
+	t.Helper()
+	keys := &sops.Keys{AgeRecipients: []string{testAgeRecipient}}
+	require.NoError(t, keys.AddAgeIdentities(testAgeIdentity))
+	return keys
+}
+
+func encrypt(t *testing.T, doc string, keys *sops.Keys, rules sops.Rules) *yaml.RNode {
+	t.Helper()
+	rn := yaml.MustParse(doc)
+	require.NoError(t, sops.Encrypt(rn, keys, rules))
+	assert.True(t, sops.IsEncrypted(rn))
+	return rn
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	testCases := []struct {
+		name      string
+		rules     sops.Rules
+		encrypted []string
+		plaintext []string
+	}{
+		{
+			name:      "default rules",
+			encrypted: []string{"kind", "stringData.password", "stringData.port", "stringData.hosts"},
+			plaintext: []string{"stringData.note_unencrypted", "stringData.empty"},
+		},
+		{
+			name:      "unencrypted regex",
+			rules:     sops.Rules{UnencryptedRegex: "^(kind|apiVersion|metadata)$"},
+			encrypted: []string{"type", "stringData.ratio", "stringData.enabled", "stringData.note_unencrypted"},
+			plaintext: []string{"kind", "apiVersion", "metadata.name", "metadata.annotations"},
+		},
+		{
+			name:      "encrypted regex",
+			rules:     sops.Rules{EncryptedRegex: "^(data|stringData)$", MACOnlyEncrypted: true},
+			encrypted: []string{"stringData.password", "stringData.hosts"},
+			plaintext: []string{"kind", "type", "metadata.name"},
+		},
+		{
+			name:      "encrypted suffix",
+			rules:     sops.Rules{EncryptedSuffix: "word"},
+			encrypted: []string{"stringData.password"},
+			plaintext: []string{"kind", "stringData.port", "stringData.hosts"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			keys := ageKeys(t)
+			rn := encrypt(t, secret, keys, tc.rules)
+			encrypted := rn.MustString()
+			for _, field := range tc.encrypted {
+				node, err := rn.Pipe(yaml.Lookup(strings.Split(field, ".")...))
+				require.NoError(t, err)
+				assert.Contains(t, node.MustString(), "ENC[AES256_GCM,data:", field)
+			}
+			for _, field := range tc.plaintext {
+				node, err := rn.Pipe(yaml.Lookup(strings.Split(field, ".")...))
+				require.NoError(t, err)
+				assert.NotContains(t, node.MustString(), "ENC[", field)
+			}
+
+			require.NoError(t, sops.Decrypt(rn, keys, false))
+			assert.False(t, sops.IsEncrypted(rn))
+			assert.YAMLEq(t, secret, rn.MustString())
+			assert.NotEqual(t, encrypted, rn.MustString())
+		})
+	}
+}
+
+func TestEncryptDecryptPGP(t *testing.T) {
+	fp, key := testPGPKey(t)
+	keys := &sops.Keys{PGPFingerprints: []string{fp}}
+	require.NoError(t, keys.AddPGPKeys(key))
+	rn := encrypt(t, secret, keys, sops.Rules{EncryptedRegex: "^stringData$"})
+
+	fpNode, err := rn.Pipe(yaml.Lookup(sops.MetadataKey, "pgp", "0", "fp"))
+	require.NoError(t, err)
+	assert.Equal(t, fp, yaml.GetValue(fpNode))
+
+	// age identities don't match PGP recipient
+	assert.Error(t, sops.Decrypt(rn.Copy(), ageKeys(t), false))
+
+	require.NoError(t, sops.Decrypt(rn, keys, false))
+	assert.YAMLEq(t, secret, rn.MustString())
+}
+
+// TestDecryptFixtures decrypts the documents encrypted by sops binary with the test PGP key
+// FBC7B9E2A4F9289AC0C1D4843D16CEE4A27381B4 used in the gates, MAC is verified as well
+func TestDecryptFixtures(t *testing.T) {
+	armored, err := ioutil.ReadFile("testdata/pgp-keys.asc")
+	require.NoError(t, err)
+	keys := &sops.Keys{}
+	require.NoError(t, keys.AddPGPKeys(string(armored)))
+
+	fixtures, err := filepath.Glob("../../../../manifests/site/test-site/target/encrypted/results/*/secrets.yaml")
+	require.NoError(t, err)
+	require.NotEmpty(t, fixtures)
+	for _, fixture := range fixtures {
+		data, err := ioutil.ReadFile(fixture)
+		require.NoError(t, err)
+		nodes, err := kio.FromBytes(data)
+		require.NoError(t, err)
+		require.NotEmpty(t, nodes, fixture)
+		for _, rn := range nodes {
+			require.True(t, sops.IsEncrypted(rn), fixture)
+			require.NoError(t, sops.Decrypt(rn, keys, false), fixture)
+			assert.False(t, sops.IsEncrypted(rn), fixture)
+			assert.NotContains(t, rn.MustString(), "ENC[", fixture)
+		}
+	}
+}
+
+func TestDecryptErrors(t *testing.T) {
+	keys := ageKeys(t)
+
+	rn := encrypt(t, secret, keys, sops.Rules{EncryptedRegex: "^stringData$"})
+	_, err := rn.Pipe(yaml.Lookup("metadata"), yaml.SetField("name", yaml.NewScalarRNode("tampered")))
+	require.NoError(t, err)
+	original := rn.MustString()
+	err = sops.Decrypt(rn, keys, false)
+	assert.IsType(t, sops.ErrMACMismatch{}, err)
+	// document is left intact on failure
+	assert.Equal(t, original, rn.MustString())
+	assert.NoError(t, sops.Decrypt(rn, keys, true))
+
+	rn = encrypt(t, secret, keys, sops.Rules{})
+	err = sops.Decrypt(rn, &sops.Keys{}, false)
+	assert.EqualError(t, err, "failed to decrypt data key: no age identities or PGP keys are available")
+
+	err = sops.Decrypt(yaml.MustParse(secret), keys, false)
+	assert.EqualError(t, err, "document is not encrypted")
+
+	rn = encrypt(t, secret, keys, sops.Rules{})
+	meta, err := rn.Pipe(yaml.Lookup(sops.MetadataKey))
+	require.NoError(t, err)
+	groups, err := yaml.Parse("- age:\n  - recipient: " + testAgeRecipient + "\n    enc: data")
+	require.NoError(t, err)
+	require.NoError(t, meta.PipeE(yaml.SetField("key_groups", groups)))
+	require.NoError(t, meta.PipeE(yaml.Clear("age")))
+	err = sops.Decrypt(rn, keys, false)
+	assert.Equal(t, sops.ErrKeyGroupsNotSupported{}, err)
+
+	err = sops.Encrypt(rn, keys, sops.Rules{})
+	assert.EqualError(t, err, "document is already encrypted")
+
+	err = sops.Encrypt(yaml.MustParse(secret), &sops.Keys{}, sops.Rules{})
+	assert.Equal(t, sops.ErrNoRecipients{}, err)
+
+	err = sops.Encrypt(yaml.MustParse(secret), keys, sops.Rules{EncryptedRegex: "("})
+	assert.Error(t, err)
+}
+
+func TestFilter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "airshipctl-sops-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	keyFile := filepath.Join(dir, "keys.txt")
+	require.NoError(t, ioutil.WriteFile(keyFile, []byte(testAgeIdentity+"\n"), 0600))
+
+	os.Setenv(sops.EnvAgeKeyFile, keyFile)
+	os.Setenv(sops.EnvAgeRecipients, testAgeRecipient)
+	defer os.Unsetenv(sops.EnvAgeKeyFile)
+	defer os.Unsetenv(sops.EnvAgeRecipients)
+
+	newFilter := func(data map[string]interface{}) kio.Filter {
+		fn, err := sops.New(map[string]interface{}{"kind": "ConfigMap", "data": data})
+		require.NoError(t, err)
+		return fn
+	}
+
+	// bundles built with the function must never be cached
+	sensitive, ok := newFilter(map[string]interface{}{"cmd": "decrypt"}).(interface{ Sensitive() bool })
+	require.True(t, ok)
+	assert.True(t, sensitive.Sensitive())
+
+	plain := yaml.MustParse(secret)
+	items, err := newFilter(map[string]interface{}{
+		"cmd":               "encrypt",
+		"unencrypted-regex": "^(kind|apiVersion|group|metadata)$",
+	}).Filter([]*yaml.RNode{plain})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.True(t, sops.IsEncrypted(items[0]))
+
+	broken := encrypt(t, secret, ageKeys(t), sops.Rules{UnencryptedRegex: "^(kind|metadata)$"})
+	require.NoError(t, broken.PipeE(yaml.SetField("kind", yaml.NewScalarRNode("Tampered"))))
+	brokenYAML := broken.MustString()
+
+	_, err = newFilter(map[string]interface{}{"cmd": "decrypt"}).Filter([]*yaml.RNode{items[0], broken})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to decrypt document Tampered/db")
+
+	items, err = newFilter(map[string]interface{}{
+		"cmd":                   "decrypt",
+		"cmd-tolerate-failures": true,
+	}).Filter([]*yaml.RNode{items[0], broken})
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.YAMLEq(t, secret, items[0].MustString())
+	assert.Equal(t, brokenYAML, items[1].MustString())
+
+	_, err = sops.New(map[string]interface{}{"data": map[string]interface{}{"cmd": "rotate"}})
+	assert.EqualError(t, err, `unsupported cmd "rotate", allowed values are decrypt|encrypt`)
+	_, err = sops.New(map[string]interface{}{"data": map[string]interface{}{"cmd": "decrypt", "ignore-mac": "maybe"}})
+	assert.EqualError(t, err, `ignore-mac must be a boolean, got "maybe"`)
+}
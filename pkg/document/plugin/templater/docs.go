@@ -0,0 +1,131 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package templater
+
+import (
+	"fmt"
+	"text/template"
+
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+
+	"opendev.org/airship/airshipctl/pkg/document/plugin/kyamlutils"
+)
+
+// Keys of the selector accepted by selectDocs function
+const (
+	selectorAPIVersion = "apiVersion"
+	selectorKind       = "kind"
+	selectorName       = "name"
+	selectorNamespace  = "namespace"
+	selectorLabels     = "labels"
+)
+
+// docsFuncMap returns functions which give templates access to the documents passed to the plugin.
+// getDoc returns exactly one document by apiVersion, kind and name, selectDocs returns the list of
+// documents matching the selector, e.g. (dict "kind" "Secret" "labels" "app=web"), and jsonPath
+// extracts the value from the document, e.g. jsonPath "{.spec.hosts[0].name}" $doc. onSelect is
+// called with the documents returned to the template
+func docsFuncMap(items []*yaml.RNode, onSelect func([]*yaml.RNode)) template.FuncMap {
+	return template.FuncMap{
+		"getDoc": func(apiVersion, kind, name string) (interface{}, error) {
+			doc, err := getDoc(items, apiVersion, kind, name)
+			if err != nil {
+				return nil, err
+			}
+			onSelect([]*yaml.RNode{doc})
+			return decode(doc)
+		},
+		"selectDocs": func(selector map[string]interface{}) ([]interface{}, error) {
+			docs, err := selectDocs(items, selector)
+			if err != nil {
+				return nil, err
+			}
+			onSelect(docs)
+			res := make([]interface{}, 0, len(docs))
+			for _, doc := range docs {
+				val, err := decode(doc)
+				if err != nil {
+					return nil, err
+				}
+				res = append(res, val)
+			}
+			return res, nil
+		},
+		"jsonPath": jsonPath,
+	}
+}
+
+func getDoc(items []*yaml.RNode, apiVersion, kind, name string) (*yaml.RNode, error) {
+	docs, err := kyamlutils.DocumentSelector{}.
+		ByAPIVersion(apiVersion).
+		ByKey(yaml.KindField, kind).
+		ByName(name).
+		Filter(items)
+	if err != nil {
+		return nil, err
+	}
+	switch len(docs) {
+	case 0:
+		return nil, ErrDocumentNotFound{APIVersion: apiVersion, Kind: kind, Name: name}
+	case 1:
+		return docs[0], nil
+	default:
+		return nil, ErrMultipleDocuments{APIVersion: apiVersion, Kind: kind, Name: name, Found: len(docs)}
+	}
+}
+
+func selectDocs(items []*yaml.RNode, selector map[string]interface{}) ([]*yaml.RNode, error) {
+	fields := map[string]string{}
+	for key, val := range selector {
+		switch key {
+		case selectorAPIVersion, selectorKind, selectorName, selectorNamespace, selectorLabels:
+			fields[key] = fmt.Sprint(val)
+		default:
+			return nil, ErrUnknownSelectorKey{Key: key}
+		}
+	}
+
+	s := kyamlutils.DocumentSelector{}.
+		ByAPIVersion(fields[selectorAPIVersion]).
+		ByName(fields[selectorName]).
+		ByNamespace(fields[selectorNamespace]).
+		ByLabel(fields[selectorLabels])
+	if kind := fields[selectorKind]; kind != "" {
+		s = s.ByKey(yaml.KindField, kind)
+	}
+	return s.Filter(items)
+}
+
+// jsonPath returns the value under the path of the document obtained by getDoc or selectDocs
+func jsonPath(path string, doc interface{}) (interface{}, error) {
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	rn, err := yaml.Parse(string(data))
+	if err != nil {
+		return nil, err
+	}
+	node, err := rn.Pipe(kyamlutils.JSONPathFilter{Path: path})
+	if err != nil || node == nil {
+		return nil, err
+	}
+	return decode(node)
+}
+
+func decode(rn *yaml.RNode) (interface{}, error) {
+	var val interface{}
+	return val, rn.YNode().Decode(&val)
+}
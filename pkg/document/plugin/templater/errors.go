@@ -0,0 +1,52 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package templater
+
+import (
+	"fmt"
+)
+
+// ErrDocumentNotFound is returned by getDoc if there is no matching document
+type ErrDocumentNotFound struct {
+	APIVersion string
+	Kind       string
+	Name       string
+}
+
+func (e ErrDocumentNotFound) Error() string {
+	return fmt.Sprintf("document %s %s %s is not found", e.APIVersion, e.Kind, e.Name)
+}
+
+// ErrMultipleDocuments is returned by getDoc if more than one document matches
+type ErrMultipleDocuments struct {
+	APIVersion string
+	Kind       string
+	Name       string
+	Found      int
+}
+
+func (e ErrMultipleDocuments) Error() string {
+	return fmt.Sprintf("found %d documents %s %s %s, expected exactly one",
+		e.Found, e.APIVersion, e.Kind, e.Name)
+}
+
+// ErrUnknownSelectorKey is returned by selectDocs if the selector has unsupported key
+type ErrUnknownSelectorKey struct {
+	Key string
+}
+
+func (e ErrUnknownSelectorKey) Error() string {
+	return fmt.Sprintf("unknown selector key %s. Allowed keys are apiVersion|kind|name|namespace|labels", e.Key)
+}
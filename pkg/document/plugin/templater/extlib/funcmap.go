@@ -32,7 +32,14 @@ var genericMap = map[string]interface{}{
 	"genCAWithKeyEx":         generateCertificateAuthorityWithPEMKeyEx,
 	"genSignedCertEx":        generateSignedCertificateEx,
 	"genSignedCertWithKeyEx": generateSignedCertificateWithPEMKeyEx,
+	"genCertWithOptions":     genCertWithOptions,
+	"genCSR":                 genCSR,
+	"genECDSAKey":            genECDSAKey,
+	"genEd25519Key":          genEd25519Key,
 	"genSSHKeyPair":          genSSHKeyPair,
+	"htpasswdBcrypt":         htpasswdBcrypt,
+	"jwks":                   jwks,
+	"publicKeyPEM":           publicKeyPEM,
 	"regexGen":               regexGen,
 	"toYaml":                 toYaml,
 	"toUint32":               toUint32,
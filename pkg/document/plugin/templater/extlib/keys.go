@@ -0,0 +1,467 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package extlib
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var keyUsages = map[string]x509.KeyUsage{
+	"digitalSignature":  x509.KeyUsageDigitalSignature,
+	"contentCommitment": x509.KeyUsageContentCommitment,
+	"keyEncipherment":   x509.KeyUsageKeyEncipherment,
+	"dataEncipherment":  x509.KeyUsageDataEncipherment,
+	"keyAgreement":      x509.KeyUsageKeyAgreement,
+	"certSign":          x509.KeyUsageCertSign,
+	"crlSign":           x509.KeyUsageCRLSign,
+	"encipherOnly":      x509.KeyUsageEncipherOnly,
+	"decipherOnly":      x509.KeyUsageDecipherOnly,
+}
+
+var extKeyUsages = map[string]x509.ExtKeyUsage{
+	"any":             x509.ExtKeyUsageAny,
+	"serverAuth":      x509.ExtKeyUsageServerAuth,
+	"clientAuth":      x509.ExtKeyUsageClientAuth,
+	"codeSigning":     x509.ExtKeyUsageCodeSigning,
+	"emailProtection": x509.ExtKeyUsageEmailProtection,
+	"timeStamping":    x509.ExtKeyUsageTimeStamping,
+	"OCSPSigning":     x509.ExtKeyUsageOCSPSigning,
+}
+
+// genECDSAKey generates PEM encoded ECDSA private key on the curve P224, P256, P384 or P521
+func genECDSAKey(curveName string) (string, error) {
+	var curve elliptic.Curve
+	switch curveName {
+	case "P224":
+		curve = elliptic.P224()
+	case "P256":
+		curve = elliptic.P256()
+	case "P384":
+		curve = elliptic.P384()
+	case "P521":
+		curve = elliptic.P521()
+	default:
+		return "", fmt.Errorf("unsupported curve %s, allowed values are P224|P256|P384|P521", curveName)
+	}
+	priv, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		return "", fmt.Errorf("error generating ecdsa key: %s", err)
+	}
+	return encodeKey(priv)
+}
+
+// genEd25519Key generates PEM encoded Ed25519 private key in PKCS#8 format
+func genEd25519Key() (string, error) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", fmt.Errorf("error generating ed25519 key: %s", err)
+	}
+	return encodeKey(priv)
+}
+
+// publicKeyPEM returns PEM encoded public key of the private key
+func publicKeyPEM(privPEM string) (string, error) {
+	pub, err := publicKeyFromPEM(privPEM)
+	if err != nil {
+		return "", err
+	}
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", fmt.Errorf("error marshaling public key: %s", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})), nil
+}
+
+// genCSR creates PEM encoded certificate signing request signed by the private key
+func genCSR(subj string, ips []interface{}, alternateDNS []interface{}, privPEM string) (string, error) {
+	priv, err := parsePrivateKeyPEM(privPEM)
+	if err != nil {
+		return "", fmt.Errorf("parsing private key: %s", err)
+	}
+	name, err := nameFromString(subj)
+	if err != nil {
+		return "", err
+	}
+	ipAddresses, err := getNetIPs(ips)
+	if err != nil {
+		return "", err
+	}
+	dnsNames, err := getAlternateDNSStrs(alternateDNS)
+	if err != nil {
+		return "", err
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:     *name,
+		IPAddresses: ipAddresses,
+		DNSNames:    dnsNames,
+	}, priv)
+	if err != nil {
+		return "", fmt.Errorf("error creating certificate request: %s", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})), nil
+}
+
+// genCertWithOptions creates certificate described by the options map with the keys:
+// subject, ips, dnsNames, uris, emails, daysValid (365 by default), notBefore (RFC3339, now by default),
+// keyUsages, extKeyUsages, isCA, key (PEM private key, RSA 2048 key is generated if absent),
+// csr (PEM certificate request, its subject, SANs and public key are used) and ca (certificate
+// returned by genCAEx, the certificate is self-signed if absent)
+func genCertWithOptions(options map[string]interface{}) (certificate, error) {
+	opts := certOptions(options)
+	cert := certificate{}
+
+	template, err := opts.template()
+	if err != nil {
+		return cert, err
+	}
+
+	var priv crypto.PrivateKey
+	var pub crypto.PublicKey
+	if csrPEM := opts.str("csr"); csrPEM != "" {
+		if pub, err = applyCSR(template, csrPEM); err != nil {
+			return cert, err
+		}
+	} else {
+		if priv, err = opts.key(); err != nil {
+			return cert, err
+		}
+		if pub, err = getPublicKey(priv); err != nil {
+			return cert, err
+		}
+	}
+
+	parent, signer := template, priv
+	if ca, ok := options["ca"]; ok {
+		caCert, ok := ca.(certificate)
+		if !ok {
+			return cert, fmt.Errorf("ca must be a certificate, got %T", ca)
+		}
+		if parent, signer, err = parseSigner(caCert); err != nil {
+			return cert, err
+		}
+	} else if priv == nil {
+		return cert, errors.New("ca must be set to sign certificate request")
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, pub, signer)
+	if err != nil {
+		return cert, fmt.Errorf("error creating certificate: %s", err)
+	}
+	cert.Cert = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	if priv != nil {
+		keyBuffer := bytes.Buffer{}
+		if err = pem.Encode(&keyBuffer, pemBlockForKey(priv)); err != nil {
+			return cert, fmt.Errorf("error pem-encoding key: %s", err)
+		}
+		cert.Key = keyBuffer.String()
+	}
+	return cert, nil
+}
+
+// htpasswdBcrypt returns bcrypt htpasswd entry of the user, unlike htpasswd of sprig
+// it returns an error instead of embedding it into the entry
+func htpasswdBcrypt(user, password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s:%s", user, hash), nil
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Crv string `json:"crv,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// jwks returns JSON Web Key Set with public keys of the PEM encoded private or public keys,
+// key ID is base64url encoded SHA-256 of the DER encoded public key as used by Kubernetes
+// for service account signing keys
+func jwks(keys ...string) (string, error) {
+	set := struct {
+		Keys []jsonWebKey `json:"keys"`
+	}{Keys: []jsonWebKey{}}
+
+	for _, keyPEM := range keys {
+		pub, err := publicKeyFromPEM(keyPEM)
+		if err != nil {
+			return "", err
+		}
+		jwk, err := toJSONWebKey(pub)
+		if err != nil {
+			return "", err
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	data, err := json.Marshal(set)
+	return string(data), err
+}
+
+func toJSONWebKey(pub crypto.PublicKey) (jsonWebKey, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return jsonWebKey{}, fmt.Errorf("error marshaling public key: %s", err)
+	}
+	sum := sha256.Sum256(der)
+	jwk := jsonWebKey{Use: "sig", Kid: base64.RawURLEncoding.EncodeToString(sum[:])}
+
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty, jwk.Alg = "RSA", "RS256"
+		jwk.N = base64.RawURLEncoding.EncodeToString(k.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		jwk.Kty, jwk.Crv = "EC", k.Curve.Params().Name
+		switch jwk.Crv {
+		case "P-256":
+			jwk.Alg = "ES256"
+		case "P-384":
+			jwk.Alg = "ES384"
+		case "P-521":
+			jwk.Alg = "ES512"
+		default:
+			return jsonWebKey{}, fmt.Errorf("curve %s is not supported by JWK", jwk.Crv)
+		}
+		jwk.X = base64.RawURLEncoding.EncodeToString(k.X.FillBytes(make([]byte, size)))
+		jwk.Y = base64.RawURLEncoding.EncodeToString(k.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk.Kty, jwk.Alg, jwk.Crv = "OKP", "EdDSA", "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(k)
+	default:
+		return jsonWebKey{}, fmt.Errorf("unsupported key type %T", pub)
+	}
+	return jwk, nil
+}
+
+func encodeKey(priv crypto.PrivateKey) (string, error) {
+	block := pemBlockForKey(priv)
+	if block == nil {
+		return "", errors.New("error pem-encoding key")
+	}
+	return string(pem.EncodeToMemory(block)), nil
+}
+
+// publicKeyFromPEM returns public key of PEM encoded private or public key
+func publicKeyFromPEM(keyPEM string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(keyPEM))
+	if block == nil {
+		return nil, errors.New("no PEM data in input")
+	}
+	if block.Type == "PUBLIC KEY" {
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parsing public key: %s", err)
+		}
+		return pub, nil
+	}
+	priv, err := parsePrivateKeyPEM(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("parsing private key: %s", err)
+	}
+	return getPublicKey(priv)
+}
+
+func applyCSR(template *x509.Certificate, csrPEM string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(csrPEM))
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, errors.New("no certificate request in PEM input")
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing certificate request: %s", err)
+	}
+	if err = csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("invalid signature of certificate request: %s", err)
+	}
+	if template.Subject.String() == "" {
+		template.Subject = csr.Subject
+	}
+	template.DNSNames = append(template.DNSNames, csr.DNSNames...)
+	template.IPAddresses = append(template.IPAddresses, csr.IPAddresses...)
+	template.EmailAddresses = append(template.EmailAddresses, csr.EmailAddresses...)
+	template.URIs = append(template.URIs, csr.URIs...)
+	return csr.PublicKey, nil
+}
+
+// certOptions are the options of genCertWithOptions
+type certOptions map[string]interface{}
+
+func (o certOptions) str(key string) string {
+	if val, ok := o[key]; ok && val != nil {
+		return fmt.Sprint(val)
+	}
+	return ""
+}
+
+func (o certOptions) list(key string) ([]interface{}, error) {
+	val, ok := o[key]
+	if !ok || val == nil {
+		return nil, nil
+	}
+	switch l := val.(type) {
+	case []interface{}:
+		return l, nil
+	case []string:
+		res := make([]interface{}, 0, len(l))
+		for _, s := range l {
+			res = append(res, s)
+		}
+		return res, nil
+	default:
+		return nil, fmt.Errorf("%s must be a list, got %T", key, val)
+	}
+}
+
+func (o certOptions) strings(key string) ([]string, error) {
+	l, err := o.list(key)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]string, 0, len(l))
+	for _, item := range l {
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a list of strings, got %v", key, item)
+		}
+		res = append(res, s)
+	}
+	return res, nil
+}
+
+func (o certOptions) key() (crypto.PrivateKey, error) {
+	if keyPEM := o.str("key"); keyPEM != "" {
+		priv, err := parsePrivateKeyPEM(keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("parsing private key: %s", err)
+		}
+		return priv, nil
+	}
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("error generating rsa key: %s", err)
+	}
+	return priv, nil
+}
+
+func (o certOptions) template() (*x509.Certificate, error) {
+	ips, err := o.list("ips")
+	if err != nil {
+		return nil, err
+	}
+	dnsNames, err := o.list("dnsNames")
+	if err != nil {
+		return nil, err
+	}
+	daysValid := 365
+	if days := o.str("daysValid"); days != "" {
+		if _, err = fmt.Sscan(days, &daysValid); err != nil {
+			return nil, fmt.Errorf("daysValid must be a number, got %s", days)
+		}
+	}
+	subj := o.str("subject")
+	if subj == "" {
+		// empty subject, it's taken from the certificate request if any
+		subj = "/"
+	}
+	template, err := getBaseCertTemplateEx(subj, ips, dnsNames, daysValid)
+	if err != nil {
+		return nil, err
+	}
+	if notBefore := o.str("notBefore"); notBefore != "" {
+		if template.NotBefore, err = time.Parse(time.RFC3339, notBefore); err != nil {
+			return nil, fmt.Errorf("invalid notBefore: %s", err)
+		}
+		template.NotAfter = template.NotBefore.Add(time.Hour * 24 * time.Duration(daysValid))
+	}
+	if template.EmailAddresses, err = o.strings("emails"); err != nil {
+		return nil, err
+	}
+	uris, err := o.strings("uris")
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range uris {
+		u, parseErr := url.Parse(s)
+		if parseErr != nil {
+			return nil, fmt.Errorf("invalid uri %s: %s", s, parseErr)
+		}
+		template.URIs = append(template.URIs, u)
+	}
+	if err = o.usages(template); err != nil {
+		return nil, err
+	}
+	if isCA, ok := o["isCA"].(bool); ok && isCA {
+		template.IsCA = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	}
+	return template, nil
+}
+
+func (o certOptions) usages(template *x509.Certificate) error {
+	if _, ok := o["keyUsages"]; ok {
+		names, err := o.strings("keyUsages")
+		if err != nil {
+			return err
+		}
+		template.KeyUsage = 0
+		for _, name := range names {
+			usage, ok := keyUsages[name]
+			if !ok {
+				return fmt.Errorf("unknown key usage %s", name)
+			}
+			template.KeyUsage |= usage
+		}
+	}
+	if _, ok := o["extKeyUsages"]; ok {
+		names, err := o.strings("extKeyUsages")
+		if err != nil {
+			return err
+		}
+		template.ExtKeyUsage = nil
+		for _, name := range names {
+			usage, ok := extKeyUsages[name]
+			if !ok {
+				return fmt.Errorf("unknown extended key usage %s", name)
+			}
+			template.ExtKeyUsage = append(template.ExtKeyUsage, usage)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package extlib
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func parseCert(t *testing.T, certPEM string) *x509.Certificate {
+	t.Helper()
+	block, _ := pem.Decode([]byte(certPEM))
+	require.NotNil(t, block)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	return cert
+}
+
+func TestGenECDSAKey(t *testing.T) {
+	keyPEM, err := genECDSAKey("P384")
+	require.NoError(t, err)
+	priv, err := parsePrivateKeyPEM(keyPEM)
+	require.NoError(t, err)
+	ecKey, ok := priv.(*ecdsa.PrivateKey)
+	require.True(t, ok)
+	assert.Equal(t, "P-384", ecKey.Curve.Params().Name)
+
+	_, err = genECDSAKey("P999")
+	assert.EqualError(t, err, "unsupported curve P999, allowed values are P224|P256|P384|P521")
+}
+
+func TestGenEd25519Key(t *testing.T) {
+	keyPEM, err := genEd25519Key()
+	require.NoError(t, err)
+	priv, err := parsePrivateKeyPEM(keyPEM)
+	require.NoError(t, err)
+	_, ok := priv.(ed25519.PrivateKey)
+	assert.True(t, ok)
+
+	pubPEM, err := publicKeyPEM(keyPEM)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(pubPEM, "-----BEGIN PUBLIC KEY-----"))
+}
+
+func TestGenCertWithOptions(t *testing.T) {
+	ca, err := generateCertificateAuthorityEx("Kubernetes API", 365)
+	require.NoError(t, err)
+	keyPEM, err := genECDSAKey("P256")
+	require.NoError(t, err)
+
+	cert, err := genCertWithOptions(map[string]interface{}{
+		"subject":      "/CN=etcd/O=airship",
+		"dnsNames":     []interface{}{"etcd.local"},
+		"ips":          []interface{}{"10.0.0.1"},
+		"uris":         []interface{}{"spiffe://cluster.local/etcd"},
+		"daysValid":    30,
+		"notBefore":    "2021-01-01T00:00:00Z",
+		"keyUsages":    []interface{}{"digitalSignature"},
+		"extKeyUsages": []interface{}{"serverAuth"},
+		"key":          keyPEM,
+		"ca":           ca,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, keyPEM, cert.Key)
+
+	parsed := parseCert(t, cert.Cert)
+	assert.NoError(t, parsed.CheckSignatureFrom(parseCert(t, ca.Cert)))
+	assert.Equal(t, "etcd", parsed.Subject.CommonName)
+	assert.Equal(t, []string{"etcd.local"}, parsed.DNSNames)
+	assert.Equal(t, "10.0.0.1", parsed.IPAddresses[0].String())
+	assert.Equal(t, "spiffe://cluster.local/etcd", parsed.URIs[0].String())
+	assert.Equal(t, x509.KeyUsageDigitalSignature, parsed.KeyUsage)
+	assert.Equal(t, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}, parsed.ExtKeyUsage)
+	assert.Equal(t, time.Date(2021, time.January, 31, 0, 0, 0, 0, time.UTC), parsed.NotAfter.UTC())
+
+	_, err = genCertWithOptions(map[string]interface{}{"keyUsages": []interface{}{"signEverything"}})
+	assert.EqualError(t, err, "unknown key usage signEverything")
+}
+
+func TestGenCertFromCSR(t *testing.T) {
+	ca, err := generateCertificateAuthorityEx("Kubernetes API", 365)
+	require.NoError(t, err)
+	keyPEM, err := genEd25519Key()
+	require.NoError(t, err)
+	csr, err := genCSR("/CN=admin/O=system:masters", nil, []interface{}{"admin.local"}, keyPEM)
+	require.NoError(t, err)
+
+	_, err = genCertWithOptions(map[string]interface{}{"csr": csr})
+	assert.EqualError(t, err, "ca must be set to sign certificate request")
+
+	cert, err := genCertWithOptions(map[string]interface{}{"csr": csr, "ca": ca})
+	require.NoError(t, err)
+	assert.Empty(t, cert.Key)
+	parsed := parseCert(t, cert.Cert)
+	assert.Equal(t, "admin", parsed.Subject.CommonName)
+	assert.Equal(t, []string{"system:masters"}, parsed.Subject.Organization)
+	assert.Equal(t, []string{"admin.local"}, parsed.DNSNames)
+}
+
+func TestHtpasswd(t *testing.T) {
+	entry, err := htpasswdBcrypt("admin", "secret")
+	require.NoError(t, err)
+	parts := strings.SplitN(entry, ":", 2)
+	require.Len(t, parts, 2)
+	assert.Equal(t, "admin", parts[0])
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(parts[1]), []byte("secret")))
+}
+
+func TestJWKS(t *testing.T) {
+	rsaKey, err := genSSHKeyPair(2048)
+	require.NoError(t, err)
+	ecKey, err := genECDSAKey("P256")
+	require.NoError(t, err)
+	edKey, err := genEd25519Key()
+	require.NoError(t, err)
+	edPub, err := publicKeyPEM(edKey)
+	require.NoError(t, err)
+
+	out, err := jwks(rsaKey.Private, ecKey, edPub)
+	require.NoError(t, err)
+	set := struct {
+		Keys []jsonWebKey `json:"keys"`
+	}{}
+	require.NoError(t, json.Unmarshal([]byte(out), &set))
+	require.Len(t, set.Keys, 3)
+	assert.Equal(t, "RS256", set.Keys[0].Alg)
+	assert.Equal(t, "AQAB", set.Keys[0].E)
+	assert.Equal(t, "ES256", set.Keys[1].Alg)
+	assert.Equal(t, "P-256", set.Keys[1].Crv)
+	assert.Equal(t, "EdDSA", set.Keys[2].Alg)
+	for _, key := range set.Keys {
+		assert.Equal(t, "sig", key.Use)
+		assert.NotEmpty(t, key.Kid)
+	}
+
+	_, err = jwks("not a key")
+	assert.EqualError(t, err, "no PEM data in input")
+}
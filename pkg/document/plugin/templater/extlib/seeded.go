@@ -0,0 +1,306 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package extlib
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"text/template"
+	"time"
+
+	"github.com/lucasjones/reggen"
+	"golang.org/x/crypto/ssh"
+)
+
+const rsaPublicExponent = 65537
+
+// SeededFuncMap returns the seeded function which creates generators of keys, certificates and
+// strings derived from the seed and a stable name, so the same material is generated on every
+// render, e.g. (seeded "kubernetes-ca").GenCAEx "Kubernetes API" 3650. notBefore is the start
+// of validity of the generated certificates
+func SeededFuncMap(seed string, notBefore *time.Time) template.FuncMap {
+	return template.FuncMap{
+		"seeded": func(name string) (*seededGenerator, error) {
+			if seed == "" {
+				return nil, errors.New("seed is not configured")
+			}
+			if name == "" {
+				return nil, errors.New("name of the seeded generator must not be empty")
+			}
+			return &seededGenerator{seed: []byte(seed), name: name, notBefore: notBefore}, nil
+		},
+	}
+}
+
+// seededGenerator generates material deterministically, each method uses its own stream of bytes
+// derived from the seed and the name of the generator
+type seededGenerator struct {
+	seed      []byte
+	name      string
+	notBefore *time.Time
+}
+
+func (g *seededGenerator) reader(purpose string) io.Reader {
+	return &seededReader{key: g.seed, info: []byte(g.name + "/" + purpose)}
+}
+
+// GenCAEx generates self-signed CA certificate, see genCAEx
+func (g *seededGenerator) GenCAEx(subj string, daysValid int) (certificate, error) {
+	priv, err := seededRSAKey(g.reader("ca-key"), 2048)
+	if err != nil {
+		return certificate{}, fmt.Errorf("error generating rsa key: %s", err)
+	}
+	ca := certificate{}
+	template, err := g.certTemplate("ca", subj, nil, nil, daysValid)
+	if err != nil {
+		return ca, err
+	}
+	template.KeyUsage = x509.KeyUsageKeyEncipherment |
+		x509.KeyUsageDigitalSignature |
+		x509.KeyUsageCertSign
+	template.IsCA = true
+
+	ca.Cert, ca.Key, err = getCertAndKey(template, priv, template, priv)
+	return ca, err
+}
+
+// GenSignedCertEx generates certificate signed by the CA, see genSignedCertEx
+func (g *seededGenerator) GenSignedCertEx(
+	subj string,
+	ips []interface{},
+	alternateDNS []interface{},
+	daysValid int,
+	ca certificate,
+) (certificate, error) {
+	priv, err := seededRSAKey(g.reader("cert-key"), 2048)
+	if err != nil {
+		return certificate{}, fmt.Errorf("error generating rsa key: %s", err)
+	}
+	cert := certificate{}
+	signerCert, signerKey, err := parseSigner(ca)
+	if err != nil {
+		return cert, err
+	}
+	template, err := g.certTemplate("cert", subj, ips, alternateDNS, daysValid)
+	if err != nil {
+		return cert, err
+	}
+
+	cert.Cert, cert.Key, err = getCertAndKey(template, priv, signerCert, signerKey)
+	return cert, err
+}
+
+// GenSSHKeyPair generates SSH key pair, see genSSHKeyPair
+func (g *seededGenerator) GenSSHKeyPair(encryptionBit int) (sshKey, error) {
+	key := sshKey{}
+	privateKey, err := seededRSAKey(g.reader("ssh-key"), encryptionBit)
+	if err != nil {
+		return key, err
+	}
+
+	var private bytes.Buffer
+	privateKeyPEM := &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)}
+	if err = pem.Encode(&private, privateKeyPEM); err != nil {
+		return key, err
+	}
+	pub, err := ssh.NewPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return key, err
+	}
+	key.Public = string(ssh.MarshalAuthorizedKey(pub))
+	key.Private = private.String()
+	return key, nil
+}
+
+// RegexGen generates string matching the regex, see regexGen
+func (g *seededGenerator) RegexGen(regex string, limit int) (string, error) {
+	if limit <= 0 {
+		return "", errors.New("limit cannot be less than or equal to 0")
+	}
+	gen, err := reggen.NewGenerator(regex)
+	if err != nil {
+		return "", err
+	}
+	var seed int64
+	if err = binary.Read(g.reader("regex"), binary.BigEndian, &seed); err != nil {
+		return "", err
+	}
+	gen.SetSeed(seed)
+	return gen.Generate(limit), nil
+}
+
+func (g *seededGenerator) certTemplate(
+	purpose string,
+	subj string,
+	ips []interface{},
+	alternateDNS []interface{},
+	daysValid int,
+) (*x509.Certificate, error) {
+	if g.notBefore == nil {
+		return nil, errors.New("notBefore of the seed must be set to generate certificates")
+	}
+	template, err := getBaseCertTemplateEx(subj, ips, alternateDNS, daysValid)
+	if err != nil {
+		return nil, err
+	}
+	// 128 bit serial number as in getBaseCertTemplateEx
+	serial := make([]byte, 16)
+	if _, err = io.ReadFull(g.reader(purpose+"-serial"), serial); err != nil {
+		return nil, err
+	}
+	template.SerialNumber = new(big.Int).SetBytes(serial)
+	template.NotBefore = *g.notBefore
+	template.NotAfter = g.notBefore.Add(time.Hour * 24 * time.Duration(daysValid))
+	return template, nil
+}
+
+func parseSigner(ca certificate) (*x509.Certificate, interface{}, error) {
+	decodedSignerCert, _ := pem.Decode([]byte(ca.Cert))
+	if decodedSignerCert == nil {
+		return nil, nil, errors.New("unable to decode certificate")
+	}
+	signerCert, err := x509.ParseCertificate(decodedSignerCert.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing certificate: decodedSignerCert.Bytes: %s", err)
+	}
+	signerKey, err := parsePrivateKeyPEM(ca.Key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing private key: %s", err)
+	}
+	return signerCert, signerKey, nil
+}
+
+// seededReader is an endless stream of bytes produced by HMAC-SHA256 of the info and a counter
+type seededReader struct {
+	key     []byte
+	info    []byte
+	counter uint64
+	buf     []byte
+}
+
+func (r *seededReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(r.buf) == 0 {
+			mac := hmac.New(sha256.New, r.key)
+			mac.Write(r.info) //nolint:errcheck
+			counter := make([]byte, 8)
+			binary.BigEndian.PutUint64(counter, r.counter)
+			mac.Write(counter) //nolint:errcheck
+			r.buf = mac.Sum(nil)
+			r.counter++
+		}
+		c := copy(p[n:], r.buf)
+		r.buf = r.buf[c:]
+		n += c
+	}
+	return n, nil
+}
+
+// seededRSAKey generates RSA key using only the bytes of the reader. Key generation of the
+// standard library is not used since it deliberately makes the result non-deterministic.
+// Primes are drawn from the reader the way FIPS 186-4 B.3.3 does: candidates have two top
+// bits set, so the modulus has exactly the requested size, the public exponent is coprime
+// with p-1 and q-1, |p-q| is greater than 2^(bits/2-100) and d is the inverse of e modulo
+// lcm(p-1, q-1). Candidates failing the checks are dropped and the next ones are drawn
+func seededRSAKey(r io.Reader, bits int) (*rsa.PrivateKey, error) {
+	if bits < 512 {
+		return nil, fmt.Errorf("rsa key size must be at least 512 bits, got %d", bits)
+	}
+	e := big.NewInt(rsaPublicExponent)
+	one := big.NewInt(1)
+	minDistance := new(big.Int).Lsh(one, uint(bits/2-100))
+	for {
+		p, err := seededPrime(r, bits-bits/2)
+		if err != nil {
+			return nil, err
+		}
+		q, err := seededPrime(r, bits/2)
+		if err != nil {
+			return nil, err
+		}
+		if new(big.Int).Abs(new(big.Int).Sub(p, q)).Cmp(minDistance) <= 0 {
+			continue
+		}
+		pMinus1 := new(big.Int).Sub(p, one)
+		qMinus1 := new(big.Int).Sub(q, one)
+		if !coprime(e, pMinus1) || !coprime(e, qMinus1) {
+			continue
+		}
+		n := new(big.Int).Mul(p, q)
+		if n.BitLen() != bits {
+			continue
+		}
+		gcd := new(big.Int).GCD(nil, nil, pMinus1, qMinus1)
+		lcm := new(big.Int).Div(new(big.Int).Mul(pMinus1, qMinus1), gcd)
+		d := new(big.Int).ModInverse(e, lcm)
+		if d == nil {
+			continue
+		}
+		key := &rsa.PrivateKey{
+			PublicKey: rsa.PublicKey{N: n, E: rsaPublicExponent},
+			D:         d,
+			Primes:    []*big.Int{p, q},
+		}
+		if err = key.Validate(); err != nil {
+			return nil, err
+		}
+		key.Precompute()
+		return key, nil
+	}
+}
+
+func coprime(a, b *big.Int) bool {
+	return new(big.Int).GCD(nil, nil, a, b).Cmp(big.NewInt(1)) == 0
+}
+
+func seededPrime(r io.Reader, bits int) (*big.Int, error) {
+	b := uint(bits % 8)
+	if b == 0 {
+		b = 8
+	}
+	buf := make([]byte, (bits+7)/8)
+	p := new(big.Int)
+	for {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		// keep the size of the candidate and set two top bits, so the product
+		// of two primes has exactly the requested size
+		buf[0] &= uint8(int(1<<b) - 1)
+		if b >= 2 {
+			buf[0] |= 3 << (b - 2)
+		} else {
+			buf[0] |= 1
+			if len(buf) > 1 {
+				buf[1] |= 0x80
+			}
+		}
+		buf[len(buf)-1] |= 1
+		p.SetBytes(buf)
+		if p.ProbablyPrime(20) {
+			return p, nil
+		}
+	}
+}
@@ -0,0 +1,141 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package extlib
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSeeded(t *testing.T, seed, name string, notBefore *time.Time) *seededGenerator {
+	t.Helper()
+	fn, ok := SeededFuncMap(seed, notBefore)["seeded"].(func(string) (*seededGenerator, error))
+	require.True(t, ok)
+	g, err := fn(name)
+	require.NoError(t, err)
+	return g
+}
+
+func TestSeededFuncMapErrors(t *testing.T) {
+	fn, ok := SeededFuncMap("", nil)["seeded"].(func(string) (*seededGenerator, error))
+	require.True(t, ok)
+	_, err := fn("name")
+	assert.EqualError(t, err, "seed is not configured")
+
+	fn, ok = SeededFuncMap("seed", nil)["seeded"].(func(string) (*seededGenerator, error))
+	require.True(t, ok)
+	_, err = fn("")
+	assert.EqualError(t, err, "name of the seeded generator must not be empty")
+
+	_, err = newSeeded(t, "seed", "ca", nil).GenCAEx("Kubernetes API", 365)
+	assert.EqualError(t, err, "notBefore of the seed must be set to generate certificates")
+}
+
+func TestSeededRegexGen(t *testing.T) {
+	regex := "^[a-z]{5,10}$"
+	first, err := newSeeded(t, "seed", "password", nil).RegexGen(regex, 10)
+	require.NoError(t, err)
+	second, err := newSeeded(t, "seed", "password", nil).RegexGen(regex, 10)
+	require.NoError(t, err)
+	other, err := newSeeded(t, "seed", "other-password", nil).RegexGen(regex, 10)
+	require.NoError(t, err)
+
+	assert.Regexp(t, regexp.MustCompile(regex), first)
+	assert.Equal(t, first, second)
+	assert.NotEqual(t, first, other)
+
+	_, err = newSeeded(t, "seed", "password", nil).RegexGen(regex, 0)
+	assert.Error(t, err)
+}
+
+func TestSeededSSHKeyPair(t *testing.T) {
+	first, err := newSeeded(t, "seed", "ssh", nil).GenSSHKeyPair(1024)
+	require.NoError(t, err)
+	second, err := newSeeded(t, "seed", "ssh", nil).GenSSHKeyPair(1024)
+	require.NoError(t, err)
+	other, err := newSeeded(t, "another seed", "ssh", nil).GenSSHKeyPair(1024)
+	require.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.NotEqual(t, first.Private, other.Private)
+}
+
+func TestSeededCertificates(t *testing.T) {
+	notBefore := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+	g := newSeeded(t, "seed", "kubernetes", &notBefore)
+
+	ca, err := g.GenCAEx("Kubernetes API", 3650)
+	require.NoError(t, err)
+	sameCA, err := newSeeded(t, "seed", "kubernetes", &notBefore).GenCAEx("Kubernetes API", 3650)
+	require.NoError(t, err)
+	assert.Equal(t, ca, sameCA)
+
+	cert, err := g.GenSignedCertEx("/CN=admin/O=system:masters", nil, []interface{}{"example.com"}, 365, ca)
+	require.NoError(t, err)
+	sameCert, err := g.GenSignedCertEx("/CN=admin/O=system:masters", nil, []interface{}{"example.com"}, 365, ca)
+	require.NoError(t, err)
+	assert.Equal(t, cert, sameCert)
+
+	caBlock, _ := pem.Decode([]byte(ca.Cert))
+	require.NotNil(t, caBlock)
+	caCert, err := x509.ParseCertificate(caBlock.Bytes)
+	require.NoError(t, err)
+	certBlock, _ := pem.Decode([]byte(cert.Cert))
+	require.NotNil(t, certBlock)
+	parsed, err := x509.ParseCertificate(certBlock.Bytes)
+	require.NoError(t, err)
+
+	assert.NoError(t, parsed.CheckSignatureFrom(caCert))
+	assert.Equal(t, notBefore, parsed.NotBefore.UTC())
+	assert.Equal(t, notBefore.Add(365*24*time.Hour), parsed.NotAfter.UTC())
+	assert.Equal(t, []string{"example.com"}, parsed.DNSNames)
+}
+
+func TestSeededRSAKey(t *testing.T) {
+	for _, bits := range []int{1024, 2048} {
+		key, err := seededRSAKey(newSeeded(t, "seed", "rsa", nil).reader("key"), bits)
+		require.NoError(t, err)
+		same, err := seededRSAKey(newSeeded(t, "seed", "rsa", nil).reader("key"), bits)
+		require.NoError(t, err)
+		assert.Equal(t, key.D, same.D)
+
+		require.NoError(t, key.Validate())
+		assert.Equal(t, bits, key.N.BitLen())
+		require.Len(t, key.Primes, 2)
+		p, q := key.Primes[0], key.Primes[1]
+		assert.NotEqual(t, 0, p.Cmp(q))
+		assert.NotNil(t, key.Precomputed.Dp)
+
+		one := big.NewInt(1)
+		minDistance := new(big.Int).Lsh(one, uint(bits/2-100))
+		assert.Equal(t, 1, new(big.Int).Abs(new(big.Int).Sub(p, q)).Cmp(minDistance))
+
+		e := big.NewInt(int64(key.E))
+		for _, prime := range key.Primes {
+			gcd := new(big.Int).GCD(nil, nil, e, new(big.Int).Sub(prime, one))
+			assert.Equal(t, 0, gcd.Cmp(one))
+		}
+	}
+
+	_, err := seededRSAKey(newSeeded(t, "seed", "rsa", nil).reader("key"), 256)
+	assert.EqualError(t, err, "rsa key size must be at least 512 bits, got 256")
+}
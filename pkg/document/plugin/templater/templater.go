@@ -18,23 +18,30 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"text/template"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/kustomize/kyaml/kio"
 	"sigs.k8s.io/kustomize/kyaml/yaml"
 
 	airshipv1 "opendev.org/airship/airshipctl/pkg/api/v1alpha1"
+	"opendev.org/airship/airshipctl/pkg/document/plugin/trace"
 
 	sprig "github.com/Masterminds/sprig/v3"
 
 	extlib "opendev.org/airship/airshipctl/pkg/document/plugin/templater/extlib"
 )
 
-var _ kio.Filter = &plugin{}
+var (
+	_ kio.Filter   = &plugin{}
+	_ trace.Tracer = &plugin{}
+)
 
 type plugin struct {
 	*airshipv1.Templater
+	trace bool
 }
 
 // New creates new instance of the plugin
@@ -60,12 +67,50 @@ func funcMapAppend(fma, fmb template.FuncMap) template.FuncMap {
 	return fma
 }
 
+// seedFuncMap returns functions generating material derived from the seed of the plugin
+func (t *plugin) seedFuncMap() (template.FuncMap, error) {
+	if t.Seed == nil {
+		return extlib.SeededFuncMap("", nil), nil
+	}
+	var notBefore *time.Time
+	if t.Seed.NotBefore != "" {
+		parsed, err := time.Parse(time.RFC3339, t.Seed.NotBefore)
+		if err != nil {
+			return nil, fmt.Errorf("invalid notBefore of the seed: %w", err)
+		}
+		notBefore = &parsed
+	}
+	return extlib.SeededFuncMap(t.Seed.Value, notBefore), nil
+}
+
+// Sensitive returns true since templates may render generated private keys, certificates
+// and passwords, so bundles built with the function must not be cached
+func (t *plugin) Sensitive() bool {
+	return true
+}
+
+// EnableTrace makes the plugin record the source documents of the generated ones
+func (t *plugin) EnableTrace() {
+	t.trace = true
+}
+
 func (t *plugin) Filter(items []*yaml.RNode) ([]*yaml.RNode, error) {
 	out := &bytes.Buffer{}
 
 	funcMap := template.FuncMap{}
 	funcMap = funcMapAppend(funcMap, sprig.TxtFuncMap())
 	funcMap = funcMapAppend(funcMap, extlib.GenericFuncMap())
+	var sources []string
+	funcMap = funcMapAppend(funcMap, docsFuncMap(items, func(docs []*yaml.RNode) {
+		for _, doc := range docs {
+			sources = appendUnique(sources, trace.ID(doc))
+		}
+	}))
+	seedFuncMap, err := t.seedFuncMap()
+	if err != nil {
+		return nil, err
+	}
+	funcMap = funcMapAppend(funcMap, seedFuncMap)
 
 	tmpl, err := template.New("tmpl").Funcs(funcMap).Parse(t.Template)
 	if err != nil {
@@ -97,5 +142,25 @@ func (t *plugin) Filter(items []*yaml.RNode) ([]*yaml.RNode, error) {
 	if !ok {
 		return nil, fmt.Errorf("output conversion error")
 	}
+	if t.trace {
+		record := trace.Record{
+			Source:      strings.Join(sources, ", "),
+			Transformer: trace.Transformer(t.Kind, t.Name),
+		}
+		for _, node := range res.Nodes {
+			if err = trace.Add(node, record); err != nil {
+				return nil, err
+			}
+		}
+	}
 	return append(items, res.Nodes...), nil
 }
+
+func appendUnique(list []string, item string) []string {
+	for _, i := range list {
+		if i == item {
+			return list
+		}
+	}
+	return append(list, item)
+}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
 
 	"sigs.k8s.io/kustomize/kyaml/kio"
 	"sigs.k8s.io/yaml"
@@ -27,9 +28,12 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/base64"
+	"encoding/json"
 	"encoding/pem"
+	"strings"
 
 	"opendev.org/airship/airshipctl/pkg/document/plugin/templater"
+	"opendev.org/airship/airshipctl/pkg/document/plugin/trace"
 )
 
 func TestTemplater(t *testing.T) {
@@ -268,3 +272,195 @@ template: |
 		assert.Equal(t, tc.expectedSubject, cert.Subject)
 	}
 }
+
+func TestGenericFunctions(t *testing.T) {
+	cfg := make(map[string]interface{})
+	require.NoError(t, yaml.Unmarshal([]byte(`
+apiVersion: airshipit.org/v1alpha1
+kind: Templater
+metadata:
+  name: notImportantHere
+template: |
+  {{- $key := genECDSAKey "P256" }}
+  entry: {{ htpasswdBcrypt "admin" "secret" | quote }}
+  sprigEntry: {{ htpasswd "admin" "secret" | quote }}
+  publicKey: {{ publicKeyPEM $key | b64enc | quote }}
+  jwks: {{ jwks $key | quote }}
+`), &cfg))
+	plugin, err := templater.New(cfg)
+	require.NoError(t, err)
+	nodes, err := plugin.Filter(nil)
+	require.NoError(t, err)
+	buf := &bytes.Buffer{}
+	require.NoError(t, kio.ByteWriter{Writer: buf}.Write(nodes))
+
+	res := make(map[string]string)
+	require.NoError(t, yaml.Unmarshal(buf.Bytes(), &res))
+
+	for _, entry := range []string{res["entry"], res["sprigEntry"]} {
+		require.True(t, strings.HasPrefix(entry, "admin:"))
+		assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(strings.TrimPrefix(entry, "admin:")), []byte("secret")))
+	}
+
+	publicKey, err := base64.StdEncoding.DecodeString(res["publicKey"])
+	require.NoError(t, err)
+	block, _ := pem.Decode(publicKey)
+	require.NotNil(t, block)
+	assert.Equal(t, "PUBLIC KEY", block.Type)
+
+	set := struct {
+		Keys []map[string]string `json:"keys"`
+	}{}
+	require.NoError(t, json.Unmarshal([]byte(res["jwks"]), &set))
+	require.Len(t, set.Keys, 1)
+	assert.Equal(t, "EC", set.Keys[0]["kty"])
+	assert.Equal(t, "P-256", set.Keys[0]["crv"])
+}
+
+func TestTemplaterDocuments(t *testing.T) {
+	items, err := kio.FromBytes([]byte(`apiVersion: airshipit.org/v1alpha1
+kind: NetworkCatalogue
+metadata:
+  name: networking
+spec:
+  ntp:
+    servers:
+    - 0.pool.ntp.org
+    - 1.pool.ntp.org
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: first
+  labels:
+    app: web
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: second
+  labels:
+    app: web
+`))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name        string
+		template    string
+		expectedOut string
+		expectedErr string
+	}{
+		{
+			name: "get document",
+			template: `{{- $nc := getDoc "airshipit.org/v1alpha1" "NetworkCatalogue" "networking" }}
+ntp: {{ jsonPath "{.spec.ntp.servers[1]}" $nc }}
+`,
+			expectedOut: "ntp: 1.pool.ntp.org\n",
+		},
+		{
+			name: "select documents",
+			template: `secrets:
+{{- range selectDocs (dict "kind" "Secret" "labels" "app=web") }}
+- {{ .metadata.name }}
+{{- end }}
+`,
+			expectedOut: "secrets:\n- first\n- second\n",
+		},
+		{
+			name:        "document not found",
+			template:    `{{ getDoc "v1" "Secret" "third" }}`,
+			expectedErr: "document v1 Secret third is not found",
+		},
+		{
+			name:        "multiple documents",
+			template:    `{{ getDoc "v1" "Secret" "" }}`,
+			expectedErr: "found 2 documents v1 Secret , expected exactly one",
+		},
+		{
+			name:        "unknown selector key",
+			template:    `{{ selectDocs (dict "group" "v1") }}`,
+			expectedErr: "unknown selector key group. Allowed keys are apiVersion|kind|name|namespace|labels",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			plugin, err := templater.New(map[string]interface{}{
+				"apiVersion": "airshipit.org/v1alpha1",
+				"kind":       "Templater",
+				"template":   tc.template,
+			})
+			require.NoError(t, err)
+			nodes, err := plugin.Filter(items)
+			if tc.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			buf := &bytes.Buffer{}
+			require.NoError(t, kio.ByteWriter{Writer: buf}.Write(nodes[len(items):]))
+			assert.Equal(t, tc.expectedOut, buf.String())
+		})
+	}
+}
+
+func TestTemplaterTrace(t *testing.T) {
+	items, err := kio.FromBytes([]byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: versions
+  namespace: default
+data:
+  nginx: nginx:1.21
+`))
+	require.NoError(t, err)
+	plugin, err := templater.New(map[string]interface{}{
+		"apiVersion": "airshipit.org/v1alpha1",
+		"kind":       "Templater",
+		"metadata":   map[string]interface{}{"name": "images"},
+		"template": `{{- $versions := getDoc "v1" "ConfigMap" "versions" }}
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: images
+data:
+  image: {{ $versions.data.nginx }}
+`,
+	})
+	require.NoError(t, err)
+	nodes, err := plugin.Filter(items)
+	require.NoError(t, err)
+	require.Len(t, nodes, 2)
+	records, err := trace.Get(nodes[1])
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	tracer, ok := plugin.(trace.Tracer)
+	require.True(t, ok)
+	tracer.EnableTrace()
+	nodes, err = plugin.Filter(items)
+	require.NoError(t, err)
+	require.Len(t, nodes, 2)
+
+	records, err = trace.Get(nodes[1])
+	require.NoError(t, err)
+	assert.Equal(t, []trace.Record{{Source: "ConfigMap/default/versions", Transformer: "Templater/images"}}, records)
+	records, err = trace.Get(nodes[0])
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestTemplaterSensitive(t *testing.T) {
+	plugin, err := templater.New(map[string]interface{}{
+		"apiVersion": "airshipit.org/v1alpha1",
+		"kind":       "Templater",
+		"metadata":   map[string]interface{}{"name": "secrets"},
+		"template":   `{{ $key := genSSHKeyPair 2048 }}`,
+	})
+	require.NoError(t, err)
+	sensitive, ok := plugin.(interface{ Sensitive() bool })
+	require.True(t, ok)
+	assert.True(t, sensitive.Sensitive())
+}
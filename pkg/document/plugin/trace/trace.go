@@ -0,0 +1,89 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package trace records provenance of the document fields modified by airshipctl KRM functions,
+// so that it's possible to find out which replacement or template produced a rendered value
+package trace
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// Annotation is the document annotation holding JSON list of trace records
+const Annotation = "config.airshipit.org/trace"
+
+// Tracer is implemented by the functions which are able to record provenance of the fields they modify,
+// tracing is enabled by the bundle build for built-in functions only
+type Tracer interface {
+	// EnableTrace makes the function add trace records to the documents it modifies
+	EnableTrace()
+}
+
+// Record describes a single modification of the document
+type Record struct {
+	// Field is the path of the modified field, empty if the whole document was generated
+	Field string `json:"field,omitempty"`
+	// Source identifies documents and fields the value was taken from
+	Source string `json:"source,omitempty"`
+	// Transformer is the kind and name of the function config, e.g. ReplacementTransformer/versions
+	Transformer string `json:"transformer"`
+}
+
+// Transformer returns the name of the function config used in the trace records
+func Transformer(kind, name string) string {
+	return fmt.Sprintf("%s/%s", kind, name)
+}
+
+// ID returns the identifier of the document used as a source in the trace records,
+// e.g. ConfigMap/default/versions
+func ID(rn *yaml.RNode) string {
+	// metadata errors are reported by kustomize, the identifier is informational only
+	meta, _ := rn.GetMeta()
+	if meta.Namespace != "" {
+		return fmt.Sprintf("%s/%s/%s", meta.Kind, meta.Namespace, meta.Name)
+	}
+	return fmt.Sprintf("%s/%s", meta.Kind, meta.Name)
+}
+
+// Add appends the records to the trace annotation of the document
+func Add(rn *yaml.RNode, records ...Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	trace, err := Get(rn)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(append(trace, records...))
+	if err != nil {
+		return err
+	}
+	return rn.PipeE(yaml.SetAnnotation(Annotation, string(data)))
+}
+
+// Get returns the trace records of the document
+func Get(rn *yaml.RNode) ([]Record, error) {
+	var trace []Record
+	val, ok := rn.GetAnnotations()[Annotation]
+	if !ok {
+		return trace, nil
+	}
+	if err := json.Unmarshal([]byte(val), &trace); err != nil {
+		return nil, fmt.Errorf("malformed %s annotation of %s: %w", Annotation, ID(rn), err)
+	}
+	return trace, nil
+}
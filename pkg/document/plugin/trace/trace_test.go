@@ -0,0 +1,55 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package trace_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+
+	"opendev.org/airship/airshipctl/pkg/document/plugin/trace"
+)
+
+func TestAdd(t *testing.T) {
+	rn, err := yaml.Parse(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+  namespace: default
+`)
+	require.NoError(t, err)
+	assert.Equal(t, "ConfigMap/default/cm", trace.ID(rn))
+
+	first := trace.Record{Field: "data.a", Source: "value", Transformer: trace.Transformer("ReplacementTransformer", "a")}
+	second := trace.Record{Field: "data.b", Transformer: "ReplacementTransformer/b"}
+	require.NoError(t, trace.Add(rn))
+	assert.NotContains(t, rn.GetAnnotations(), trace.Annotation)
+	require.NoError(t, trace.Add(rn, first))
+	require.NoError(t, trace.Add(rn, second))
+
+	records, err := trace.Get(rn)
+	require.NoError(t, err)
+	assert.Equal(t, []trace.Record{first, second}, records)
+	assert.Equal(t,
+		`[{"field":"data.a","source":"value","transformer":"ReplacementTransformer/a"},`+
+			`{"field":"data.b","transformer":"ReplacementTransformer/b"}]`,
+		rn.GetAnnotations()[trace.Annotation])
+
+	require.NoError(t, rn.PipeE(yaml.SetAnnotation(trace.Annotation, "not json")))
+	_, err = trace.Get(rn)
+	assert.Error(t, err)
+}
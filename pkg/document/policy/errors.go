@@ -0,0 +1,56 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package policy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidRule is returned when the policy rule is misconfigured
+type ErrInvalidRule struct {
+	Policy string
+	Rule   string
+	What   string
+}
+
+func (e ErrInvalidRule) Error() string {
+	return fmt.Sprintf("invalid rule %s of validation policy %s: %s", e.Rule, e.Policy, e.What)
+}
+
+// ErrEvaluation is returned when the rule can't be evaluated against the document
+type ErrEvaluation struct {
+	Rule     string
+	Resource string
+	Err      error
+}
+
+func (e ErrEvaluation) Error() string {
+	return fmt.Sprintf("failed to evaluate rule %s against %s: %v", e.Rule, e.Resource, e.Err)
+}
+
+// ErrPolicyViolation is returned when documents violate deny rules of the policies
+type ErrPolicyViolation struct {
+	Violations []Violation
+}
+
+func (e ErrPolicyViolation) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d policy violation(s) found:", len(e.Violations))
+	for _, v := range e.Violations {
+		fmt.Fprintf(&b, "\n  %s", v)
+	}
+	return b.String()
+}
@@ -0,0 +1,268 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+
+	"opendev.org/airship/airshipctl/pkg/api/v1alpha1"
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/document/plugin/kyamlutils"
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+// ValidationPolicyKind is the kind of the policy documents
+const ValidationPolicyKind = "ValidationPolicy"
+
+// Violation describes the document which doesn't satisfy the policy rule
+type Violation struct {
+	Policy   string
+	Rule     string
+	Action   string
+	Message  string
+	Resource string
+}
+
+func (v Violation) String() string {
+	msg := v.Message
+	if msg == "" {
+		msg = "policy rule is violated"
+	}
+	return fmt.Sprintf("%s: %s (%s/%s)", v.Resource, msg, v.Policy, v.Rule)
+}
+
+// Load returns validation policies defined in the bundle which apply to the phase
+func Load(bundle document.Bundle, phaseName string) ([]v1alpha1.ValidationPolicy, error) {
+	selector := document.NewSelector().ByGvk(v1alpha1.GroupVersion.Group, v1alpha1.GroupVersion.Version,
+		ValidationPolicyKind)
+	docs, err := bundle.Select(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	var policies []v1alpha1.ValidationPolicy
+	for _, doc := range docs {
+		p := v1alpha1.ValidationPolicy{}
+		if err = doc.ToAPIObject(&p, v1alpha1.Scheme); err != nil {
+			return nil, err
+		}
+		if appliesTo(p.Spec.Phases, phaseName) {
+			policies = append(policies, p)
+		}
+	}
+	return policies, nil
+}
+
+func appliesTo(phases []string, phaseName string) bool {
+	if len(phases) == 0 {
+		return true
+	}
+	for _, name := range phases {
+		if name == phaseName {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluate checks the documents against the policies and returns all violations found
+func Evaluate(policies []v1alpha1.ValidationPolicy, docs []document.Document) ([]Violation, error) {
+	var violations []Violation
+	for _, p := range policies {
+		for _, rule := range p.Spec.Rules {
+			r, err := newRule(p.Name, rule)
+			if err != nil {
+				return nil, err
+			}
+			for _, doc := range docs {
+				if !r.matches(doc) {
+					continue
+				}
+				violated, err := r.violated(doc)
+				if err != nil {
+					return nil, err
+				}
+				if violated {
+					violations = append(violations, Violation{
+						Policy:   p.Name,
+						Rule:     rule.Name,
+						Action:   r.action,
+						Message:  rule.Message,
+						Resource: document.ResourceName(doc),
+					})
+				}
+			}
+		}
+	}
+	return violations, nil
+}
+
+// Check evaluates policies of the phase config bundle which apply to the phase against
+// the rendered documents. Warnings are logged, ErrPolicyViolation is returned if any
+// of deny rules is violated
+func Check(phaseBundle document.Bundle, phaseName string, docs []document.Document) error {
+	policies, err := Load(phaseBundle, phaseName)
+	if err != nil || len(policies) == 0 {
+		return err
+	}
+	violations, err := Evaluate(policies, docs)
+	if err != nil {
+		return err
+	}
+
+	var denied []Violation
+	for _, v := range violations {
+		if v.Action == v1alpha1.PolicyActionWarn {
+			log.Printf("WARNING: %s", v)
+			continue
+		}
+		denied = append(denied, v)
+	}
+	if len(denied) > 0 {
+		return ErrPolicyViolation{Violations: denied}
+	}
+	return nil
+}
+
+// rule is a parsed policy rule
+type rule struct {
+	name     string
+	action   string
+	match    v1alpha1.PolicyMatch
+	labels   labels.Selector
+	forEach  *kyamlutils.JSONPathFilter
+	cond     kyamlutils.JSONPathFilter
+	required bool
+}
+
+func newRule(policy string, r v1alpha1.PolicyRule) (*rule, error) {
+	res := &rule{
+		name:   r.Name,
+		action: r.Action,
+		match:  r.Match,
+		labels: labels.Everything(),
+	}
+	if res.action == "" {
+		res.action = v1alpha1.PolicyActionDeny
+	}
+	if res.action != v1alpha1.PolicyActionDeny && res.action != v1alpha1.PolicyActionWarn {
+		return nil, ErrInvalidRule{Policy: policy, Rule: r.Name,
+			What: fmt.Sprintf("unknown action %s, allowed values are deny|warn", r.Action)}
+	}
+
+	condition := r.Forbid
+	if r.Require != "" {
+		condition = r.Require
+		res.required = true
+	}
+	if (r.Forbid == "") == (r.Require == "") {
+		return nil, ErrInvalidRule{Policy: policy, Rule: r.Name,
+			What: "exactly one of forbid and require must be set"}
+	}
+
+	var err error
+	if r.Match.LabelSelector != "" {
+		if res.labels, err = labels.Parse(r.Match.LabelSelector); err != nil {
+			return nil, ErrInvalidRule{Policy: policy, Rule: r.Name, What: err.Error()}
+		}
+	}
+	if r.ForEach != "" {
+		expr := r.ForEach
+		if !strings.HasPrefix(expr, "{") {
+			expr = fmt.Sprintf("{%s}", expr)
+		}
+		res.forEach = &kyamlutils.JSONPathFilter{Path: expr}
+		if err = parse(*res.forEach); err != nil {
+			return nil, ErrInvalidRule{Policy: policy, Rule: r.Name,
+				What: fmt.Sprintf("unable to parse forEach %q: %v", r.ForEach, err)}
+		}
+	}
+	// NOTE JSONPath filters only work on lists, so the element is wrapped into a list
+	// and the condition is met if the filter catches it
+	res.cond = kyamlutils.JSONPathFilter{Path: fmt.Sprintf("{.items[?(%s)]}", condition)}
+	if err = parse(res.cond); err != nil {
+		return nil, ErrInvalidRule{Policy: policy, Rule: r.Name,
+			What: fmt.Sprintf("unable to parse condition %q: %v", condition, err)}
+	}
+	return res, nil
+}
+
+// parse checks the expression by running it against an empty object, path elements
+// are missing there so filters are parsed but never evaluated
+func parse(filter kyamlutils.JSONPathFilter) error {
+	_, err := filter.Query(yaml.NewMapRNode(nil))
+	return err
+}
+
+func (r *rule) matches(doc document.Document) bool {
+	if len(r.match.Kinds) > 0 && !contains(r.match.Kinds, doc.GetKind()) {
+		return false
+	}
+	ns := doc.GetNamespace()
+	if len(r.match.Namespaces) > 0 && !contains(r.match.Namespaces, ns) {
+		return false
+	}
+	if contains(r.match.ExcludeNamespaces, ns) {
+		return false
+	}
+	return r.labels.Matches(labels.Set(doc.GetLabels()))
+}
+
+// violated returns true if the document or any of its elements selected by forEach breaks the rule
+func (r *rule) violated(doc document.Document) (bool, error) {
+	out, err := doc.AsYAML()
+	if err != nil {
+		return false, err
+	}
+	rn, err := yaml.Parse(string(out))
+	if err != nil {
+		return false, err
+	}
+
+	elements := []*yaml.RNode{rn}
+	if r.forEach != nil {
+		if elements, err = r.forEach.Query(rn); err != nil {
+			return false, ErrEvaluation{Rule: r.name, Resource: document.ResourceName(doc), Err: err}
+		}
+	}
+
+	for _, element := range elements {
+		items := yaml.NewRNode(&yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+			yaml.NewScalarRNode("items").YNode(),
+			{Kind: yaml.SequenceNode, Content: []*yaml.Node{element.YNode()}},
+		}})
+		results, err := r.cond.Query(items)
+		if err != nil {
+			return false, ErrEvaluation{Rule: r.name, Resource: document.ResourceName(doc), Err: err}
+		}
+		if met := len(results) > 0; met != r.required {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
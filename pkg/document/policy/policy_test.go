@@ -0,0 +1,275 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package policy_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/api/v1alpha1"
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/document/policy"
+)
+
+const (
+	policies = `apiVersion: airshipit.org/v1alpha1
+kind: ValidationPolicy
+metadata:
+  name: security
+spec:
+  rules:
+  - name: no-privileged
+    message: privileged containers are not allowed
+    match:
+      kinds:
+      - Deployment
+      excludeNamespaces:
+      - kube-system
+    forEach: .spec.template.spec.containers[*]
+    forbid: "@.securityContext.privileged == true"
+  - name: resource-limits
+    action: warn
+    message: containers must have resource limits
+    match:
+      kinds:
+      - Deployment
+    forEach: .spec.template.spec.containers[*]
+    require: "@.resources.limits"
+---
+apiVersion: airshipit.org/v1alpha1
+kind: ValidationPolicy
+metadata:
+  name: other-phase
+spec:
+  phases:
+  - other
+  rules:
+  - name: replicas
+    match:
+      labelSelector: app=web
+    require: "@.spec.replicas > 1"
+`
+	deployments = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: privileged
+  namespace: default
+  labels:
+    app: web
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: good
+        resources:
+          limits:
+            cpu: 100m
+      - name: bad
+        securityContext:
+          privileged: true
+        resources:
+          limits:
+            cpu: 100m
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: system
+  namespace: kube-system
+spec:
+  template:
+    spec:
+      containers:
+      - name: proxy
+        securityContext:
+          privileged: true
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+  namespace: default
+  labels:
+    app: web
+data:
+  key: value
+`
+)
+
+func documents(t *testing.T, data string) (document.Bundle, []document.Document) {
+	t.Helper()
+	bundle, err := document.NewBundleFromBytes([]byte(data))
+	require.NoError(t, err)
+	docs, err := bundle.GetAllDocuments()
+	require.NoError(t, err)
+	return bundle, docs
+}
+
+func TestLoad(t *testing.T) {
+	bundle, _ := documents(t, policies)
+
+	loaded, err := policy.Load(bundle, "initinfra")
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+	assert.Equal(t, "security", loaded[0].Name)
+
+	loaded, err = policy.Load(bundle, "other")
+	require.NoError(t, err)
+	assert.Len(t, loaded, 2)
+}
+
+func TestEvaluate(t *testing.T) {
+	policyBundle, _ := documents(t, policies)
+	loaded, err := policy.Load(policyBundle, "other")
+	require.NoError(t, err)
+	_, docs := documents(t, deployments)
+
+	violations, err := policy.Evaluate(loaded, docs)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []policy.Violation{
+		{
+			Policy:   "security",
+			Rule:     "no-privileged",
+			Action:   v1alpha1.PolicyActionDeny,
+			Message:  "privileged containers are not allowed",
+			Resource: "Deployment/default/privileged",
+		},
+		{
+			Policy:   "security",
+			Rule:     "resource-limits",
+			Action:   v1alpha1.PolicyActionWarn,
+			Message:  "containers must have resource limits",
+			Resource: "Deployment/kube-system/system",
+		},
+		{
+			Policy:   "other-phase",
+			Rule:     "replicas",
+			Action:   v1alpha1.PolicyActionDeny,
+			Resource: "Deployment/default/privileged",
+		},
+		{
+			Policy:   "other-phase",
+			Rule:     "replicas",
+			Action:   v1alpha1.PolicyActionDeny,
+			Resource: "ConfigMap/default/cm",
+		},
+	}, violations)
+}
+
+func TestEvaluateInvalidRule(t *testing.T) {
+	tests := []struct {
+		name string
+		rule v1alpha1.PolicyRule
+		what string
+	}{
+		{
+			name: "unknown action",
+			rule: v1alpha1.PolicyRule{Name: "r", Action: "block", Forbid: "@.kind"},
+			what: "unknown action block, allowed values are deny|warn",
+		},
+		{
+			name: "no condition",
+			rule: v1alpha1.PolicyRule{Name: "r"},
+			what: "exactly one of forbid and require must be set",
+		},
+		{
+			name: "invalid condition",
+			rule: v1alpha1.PolicyRule{Name: "r", Forbid: "@.kind =="},
+			what: `unable to parse condition "@.kind ==": ` +
+				`invalid filter expression '@.kind ==': unexpected end of expression`,
+		},
+		{
+			name: "both conditions",
+			rule: v1alpha1.PolicyRule{Name: "r", Forbid: "@.kind", Require: "@.kind"},
+			what: "exactly one of forbid and require must be set",
+		},
+	}
+	_, docs := documents(t, deployments)
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			p := v1alpha1.ValidationPolicy{Spec: v1alpha1.ValidationPolicySpec{Rules: []v1alpha1.PolicyRule{tt.rule}}}
+			p.Name = "p"
+			_, err := policy.Evaluate([]v1alpha1.ValidationPolicy{p}, docs)
+			assert.Equal(t, policy.ErrInvalidRule{Policy: "p", Rule: "r", What: tt.what}, err)
+		})
+	}
+}
+
+func TestEvaluateLogicalConditions(t *testing.T) {
+	_, docs := documents(t, deployments)
+	tests := []struct {
+		name     string
+		rule     v1alpha1.PolicyRule
+		violated []string
+	}{
+		{
+			name: "and",
+			rule: v1alpha1.PolicyRule{
+				ForEach: ".spec.template.spec.containers[*]",
+				Forbid:  "@.securityContext.privileged == true && @.name != 'proxy'",
+			},
+			violated: []string{"Deployment/default/privileged"},
+		},
+		{
+			name: "or",
+			rule: v1alpha1.PolicyRule{
+				Match:   v1alpha1.PolicyMatch{Kinds: []string{"Deployment"}},
+				ForEach: ".spec.template.spec.containers[*]",
+				Require: "@.resources.limits || @.name =~ /^prox/",
+			},
+		},
+		{
+			name: "negation",
+			rule: v1alpha1.PolicyRule{
+				Match:  v1alpha1.PolicyMatch{Kinds: []string{"Deployment"}},
+				Forbid: "!@.spec.replicas",
+			},
+			violated: []string{"Deployment/kube-system/system"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.rule.Name = "r"
+			p := v1alpha1.ValidationPolicy{Spec: v1alpha1.ValidationPolicySpec{Rules: []v1alpha1.PolicyRule{tt.rule}}}
+			violations, err := policy.Evaluate([]v1alpha1.ValidationPolicy{p}, docs)
+			require.NoError(t, err)
+			var violated []string
+			for _, v := range violations {
+				violated = append(violated, v.Resource)
+			}
+			assert.Equal(t, tt.violated, violated)
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	policyBundle, _ := documents(t, policies)
+	_, docs := documents(t, deployments)
+
+	err := policy.Check(policyBundle, "initinfra", docs)
+	require.Error(t, err)
+	violationErr, ok := err.(policy.ErrPolicyViolation)
+	require.True(t, ok)
+	require.Len(t, violationErr.Violations, 1)
+	assert.Equal(t, "no-privileged", violationErr.Violations[0].Rule)
+
+	emptyBundle, _ := documents(t, "")
+	assert.NoError(t, policy.Check(emptyBundle, "initinfra", docs))
+}
@@ -0,0 +1,38 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package query
+
+import (
+	"fmt"
+)
+
+// ErrInvalidFormat is returned when unsupported output format is requested
+type ErrInvalidFormat struct {
+	RequestedFormat string
+}
+
+func (e ErrInvalidFormat) Error() string {
+	return fmt.Sprintf("invalid output format specified %s. Allowed values are table|yaml|json",
+		e.RequestedFormat)
+}
+
+// ErrInvalidAPIVersion is returned when API version filter is not in group/version or version format
+type ErrInvalidAPIVersion struct {
+	APIVersion string
+}
+
+func (e ErrInvalidAPIVersion) Error() string {
+	return fmt.Sprintf("invalid API version %s, expected format is group/version or version", e.APIVersion)
+}
@@ -0,0 +1,90 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"sigs.k8s.io/yaml"
+
+	"opendev.org/airship/airshipctl/pkg/util"
+)
+
+const (
+	// TableOutputFormat prints matched documents as a table
+	TableOutputFormat = "table"
+	// YAMLOutputFormat prints matched documents as a YAML list
+	YAMLOutputFormat = "yaml"
+	// JSONOutputFormat prints matched documents as a JSON list
+	JSONOutputFormat = "json"
+)
+
+// WriteResults prints query results in the requested format, the value column of
+// the table is printed only if the field was queried
+func WriteResults(w io.Writer, format string, results []Result, withValue bool) error {
+	switch format {
+	case TableOutputFormat:
+		return writeTable(w, results, withValue)
+	case YAMLOutputFormat:
+		out, err := yaml.Marshal(results)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(out)
+		return err
+	case JSONOutputFormat:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	default:
+		return ErrInvalidFormat{RequestedFormat: format}
+	}
+}
+
+func writeTable(w io.Writer, results []Result, withValue bool) error {
+	tw := util.NewTabWriter(w)
+	header := "API VERSION\tKIND\tNAMESPACE\tNAME"
+	if withValue {
+		header += "\tVALUE"
+	}
+	fmt.Fprintln(tw, header)
+	for _, r := range results {
+		row := fmt.Sprintf("%s\t%s\t%s\t%s", r.APIVersion, r.Kind, r.Namespace, r.Name)
+		if withValue {
+			value, err := tableValue(r.Value)
+			if err != nil {
+				return err
+			}
+			row += "\t" + value
+		}
+		fmt.Fprintln(tw, row)
+	}
+	return tw.Flush()
+}
+
+// tableValue prints scalars as is and compacts maps and lists to a single line JSON
+func tableValue(value interface{}) (string, error) {
+	switch value.(type) {
+	case nil:
+		return "", nil
+	case map[string]interface{}, []interface{}:
+		out, err := json.Marshal(value)
+		return string(out), err
+	default:
+		return fmt.Sprint(value), nil
+	}
+}
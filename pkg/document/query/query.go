@@ -0,0 +1,103 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package query
+
+import (
+	"errors"
+	"strings"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+)
+
+// Filter holds the criteria the documents are selected by, empty criteria match all documents
+type Filter struct {
+	Name        string
+	Namespace   string
+	APIVersion  string
+	Kind        string
+	Labels      string
+	Annotations string
+}
+
+// Selector returns document selector built from the filter, API version is either group/version
+// or version of the core group
+func (f Filter) Selector() (document.Selector, error) {
+	selector := document.NewSelector().
+		ByName(f.Name).
+		ByNamespace(f.Namespace).
+		ByLabel(f.Labels).
+		ByAnnotation(f.Annotations)
+	if f.APIVersion == "" {
+		return selector.ByKind(f.Kind), nil
+	}
+	parts := strings.Split(f.APIVersion, "/")
+	switch {
+	case len(parts) == 1 && parts[0] != "":
+		return selector.ByGvk("", parts[0], f.Kind), nil
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+		return selector.ByGvk(parts[0], parts[1], f.Kind), nil
+	default:
+		return selector, ErrInvalidAPIVersion{APIVersion: f.APIVersion}
+	}
+}
+
+// Result is a document matched by the query along with the value of requested field
+type Result struct {
+	APIVersion string      `json:"apiVersion"`
+	Kind       string      `json:"kind"`
+	Namespace  string      `json:"namespace,omitempty"`
+	Name       string      `json:"name"`
+	Value      interface{} `json:"value,omitempty"`
+}
+
+// Query selects documents of the bundle and gets the value of the field from each of them. Documents
+// which don't have the field are skipped, all matched documents are returned if the field is empty
+func Query(bundle document.Bundle, selector document.Selector, field string) ([]Result, error) {
+	docs, err := bundle.Select(selector)
+	if err != nil {
+		return nil, err
+	}
+	path := jsonPath(field)
+	results := make([]Result, 0, len(docs))
+	for _, doc := range docs {
+		result := Result{
+			APIVersion: document.APIVersion(doc),
+			Kind:       doc.GetKind(),
+			Namespace:  doc.GetNamespace(),
+			Name:       doc.GetName(),
+		}
+		if path != "" {
+			result.Value, err = doc.GetFieldValue(path)
+			if errors.As(err, &document.ErrDocumentDataKeyNotFound{}) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// jsonPath converts dot separated field path to JSON path query, so that missing fields are reported
+// the same way regardless of the path syntax
+func jsonPath(field string) string {
+	field = strings.TrimSpace(field)
+	if field == "" || strings.HasPrefix(field, "{") {
+		return field
+	}
+	return "{." + strings.TrimPrefix(field, ".") + "}"
+}
@@ -0,0 +1,188 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package query_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/document/query"
+)
+
+func TestQuery(t *testing.T) {
+	bundle, err := document.NewBundleByPath("testdata")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		selector document.Selector
+		field    string
+		expected []query.Result
+	}{
+		{
+			name:     "select by label",
+			selector: document.NewSelector().ByLabel("app=web"),
+			expected: []query.Result{
+				{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "frontend", Name: "web"},
+				{APIVersion: "v1", Kind: "ConfigMap", Namespace: "frontend", Name: "settings"},
+			},
+		},
+		{
+			name:     "field path skips documents without field",
+			selector: document.NewSelector().ByKind("Deployment"),
+			field:    "spec.replicas",
+			expected: []query.Result{
+				{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "frontend", Name: "web", Value: 2},
+			},
+		},
+		{
+			name:     "JSON path",
+			selector: document.NewSelector().ByGvk("apps", "v1", "Deployment").ByNamespace("frontend"),
+			field:    "{.spec.template.spec.containers[*].image}",
+			expected: []query.Result{
+				{
+					APIVersion: "apps/v1",
+					Kind:       "Deployment",
+					Namespace:  "frontend",
+					Name:       "web",
+					Value:      []interface{}{"nginx:1.21", "envoy:1.18"},
+				},
+			},
+		},
+		{
+			name:     "select by annotation",
+			selector: document.NewSelector().ByAnnotation("airshipit.org/owner=ui"),
+			field:    "metadata.labels",
+			expected: []query.Result{
+				{
+					APIVersion: "apps/v1",
+					Kind:       "Deployment",
+					Namespace:  "frontend",
+					Name:       "web",
+					Value:      map[string]interface{}{"app": "web"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			results, err := query.Query(bundle, tc.selector, tc.field)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, results)
+		})
+	}
+}
+
+func TestWriteResults(t *testing.T) {
+	results := []query.Result{
+		{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "frontend", Name: "web",
+			Value: map[string]interface{}{"app": "web"}},
+		{APIVersion: "v1", Kind: "ConfigMap", Name: "settings", Value: "production"},
+	}
+
+	testCases := []struct {
+		format    string
+		withValue bool
+		expected  string
+	}{
+		{
+			format:    query.TableOutputFormat,
+			withValue: true,
+			expected: "API VERSION   KIND         NAMESPACE   NAME       VALUE\n" +
+				"apps/v1       Deployment   frontend    web        {\"app\":\"web\"}\n" +
+				"v1            ConfigMap                settings   production\n",
+		},
+		{
+			format: query.TableOutputFormat,
+			expected: "API VERSION   KIND         NAMESPACE   NAME\n" +
+				"apps/v1       Deployment   frontend    web\n" +
+				"v1            ConfigMap                settings\n",
+		},
+		{
+			format:    query.YAMLOutputFormat,
+			withValue: true,
+			expected: `- apiVersion: apps/v1
+  kind: Deployment
+  name: web
+  namespace: frontend
+  value:
+    app: web
+- apiVersion: v1
+  kind: ConfigMap
+  name: settings
+  value: production
+`,
+		},
+		{
+			format:    query.JSONOutputFormat,
+			withValue: true,
+			expected: `[
+  {
+    "apiVersion": "apps/v1",
+    "kind": "Deployment",
+    "namespace": "frontend",
+    "name": "web",
+    "value": {
+      "app": "web"
+    }
+  },
+  {
+    "apiVersion": "v1",
+    "kind": "ConfigMap",
+    "name": "settings",
+    "value": "production"
+  }
+]
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.format, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			require.NoError(t, query.WriteResults(buf, tc.format, results, tc.withValue))
+			assert.Equal(t, tc.expected, buf.String())
+		})
+	}
+}
+
+func TestFilterSelector(t *testing.T) {
+	bundle, err := document.NewBundleByPath("testdata")
+	require.NoError(t, err)
+
+	selector, err := query.Filter{Kind: "ConfigMap", APIVersion: "v1", Labels: "app=web"}.Selector()
+	require.NoError(t, err)
+	docs, err := bundle.Select(selector)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "settings", docs[0].GetName())
+
+	selector, err = query.Filter{APIVersion: "apps/v1", Namespace: "backend"}.Selector()
+	require.NoError(t, err)
+	docs, err = bundle.Select(selector)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "api", docs[0].GetName())
+
+	_, err = query.Filter{APIVersion: "apps/v1/extra"}.Selector()
+	assert.Equal(t, query.ErrInvalidAPIVersion{APIVersion: "apps/v1/extra"}, err)
+}
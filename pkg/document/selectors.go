@@ -190,14 +190,6 @@ func NewClusterctlMetadataSelector() Selector {
 		ClusterctlMetadataKind)
 }
 
-// NewValidatorExecutorSelector returns selector to get validator executor documents
-func NewValidatorExecutorSelector() Selector {
-	return NewSelector().ByGvk(ValidatorGroup,
-		ValidatorVersion,
-		ValidatorKind).
-		ByName(ValidatorName)
-}
-
 // NewCRDSelector returns selector to get custom resource definition documents
 func NewCRDSelector() Selector {
 	return NewSelector().ByKind(CRDKind)
@@ -103,6 +103,16 @@ func MakeResMap(fs fs.FileSystem, kfile string) (map[string][]string, error) {
 		resMap["Resources"] = append(resMap["Resources"], path)
 	}
 
+	for _, p := range k.Bases {
+		path := filepath.Join(basedir, p)
+		resMap["Bases"] = append(resMap["Bases"], path)
+	}
+
+	for _, p := range k.Components {
+		path := filepath.Join(basedir, p)
+		resMap["Components"] = append(resMap["Components"], path)
+	}
+
 	for _, p := range k.Crds {
 		path := filepath.Join(basedir, p)
 		resMap["Crds"] = append(resMap["Crds"], path)
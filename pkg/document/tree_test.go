@@ -120,6 +120,24 @@ func TestBuildKustomTree(t *testing.T) {
 	}
 }
 
+func TestBuildKustomTreeBasesComponents(t *testing.T) {
+	root, err := document.BuildKustomTree("testdata/tree-bases/kustomization.yaml", ioutil.Discard, "testdata")
+	require.NoError(t, err)
+	assert.Equal(t, "tree-bases/kustomization.yaml", root.Name)
+
+	children := map[string][]string{}
+	for _, node := range root.Children {
+		for _, child := range node.Children {
+			children[node.Name] = append(children[node.Name], child.Name)
+		}
+	}
+	assert.Equal(t, map[string][]string{
+		"Bases":      {"testdata/tree-bases/base/kustomization.yaml"},
+		"Components": {"testdata/tree-bases/component/kustomization.yaml"},
+		"Resources":  {"tree-bases/configmap.yaml"},
+	}, children)
+}
+
 func Test_makeResMap(t *testing.T) {
 	type args struct {
 		kfile string
@@ -146,6 +164,23 @@ func Test_makeResMap(t *testing.T) {
 				},
 			},
 		},
+		{
+			args: func(t *testing.T) args {
+				return args{kfile: "testdata/tree-bases/kustomization.yaml", fs: fs.NewDocumentFs()}
+			},
+			name: "success resmap with bases and components",
+			want1: map[string][]string{
+				"Bases": {
+					"testdata/tree-bases/base",
+				},
+				"Components": {
+					"testdata/tree-bases/component",
+				},
+				"Resources": {
+					"testdata/tree-bases/configmap.yaml",
+				},
+			},
+		},
 		{
 			args: func(t *testing.T) args {
 				return args{kfile: "testdata/no_plan_site/phases/kustomization.yaml"}
@@ -0,0 +1,95 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// FieldError describes invalid field of the document
+type FieldError struct {
+	Path    string
+	Message string
+}
+
+func (e FieldError) String() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// ResourceErrors holds field errors of the document
+type ResourceErrors struct {
+	Resource string
+	Errors   []FieldError
+}
+
+// ErrValidationFailed is returned when some of the documents don't match their schemas
+type ErrValidationFailed struct {
+	Resources []ResourceErrors
+}
+
+func (e ErrValidationFailed) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "validation failed for %d resource(s):", len(e.Resources))
+	for _, res := range e.Resources {
+		for _, fieldErr := range res.Errors {
+			fmt.Fprintf(&b, "\n  %s: %s", res.Resource, fieldErr)
+		}
+	}
+	return b.String()
+}
+
+// ErrSchemaNotFound is returned when there is no schema for the document
+type ErrSchemaNotFound struct {
+	GVK schema.GroupVersionKind
+}
+
+func (e ErrSchemaNotFound) Error() string {
+	return fmt.Sprintf("schema for %s is not found", e.GVK)
+}
+
+// ErrInvalidSchema is returned when the schema can't be parsed
+type ErrInvalidSchema struct {
+	GVK schema.GroupVersionKind
+	Err error
+}
+
+func (e ErrInvalidSchema) Error() string {
+	return fmt.Sprintf("invalid schema for %s: %v", e.GVK, e.Err)
+}
+
+// ErrSchemaLocation is returned when the configured schema location can't be used
+type ErrSchemaLocation struct {
+	Location string
+	Reason   string
+}
+
+func (e ErrSchemaLocation) Error() string {
+	return fmt.Sprintf("schema location %s can't be used: %s", e.Location, e.Reason)
+}
+
+// ErrUnsupportedKubernetesVersion is returned when there are no schemas of the Kubernetes version
+// to validate against
+type ErrUnsupportedKubernetesVersion struct {
+	Version string
+}
+
+func (e ErrUnsupportedKubernetesVersion) Error() string {
+	return fmt.Sprintf("kubernetes version %s is not supported, built-in schemas are of version %s, "+
+		"schemas of other versions must be provided in v%s-standalone subdirectory of the schema location",
+		e.Version, DefaultKubernetesVersion, e.Version)
+}
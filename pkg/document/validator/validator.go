@@ -0,0 +1,315 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+
+	"opendev.org/airship/airshipctl/pkg/api/v1alpha1"
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+const (
+	// DefaultKubernetesVersion is the version of Kubernetes to validate against if the phase doesn't
+	// set one, it's the version of the schemas built into airshipctl, i.e. the one kustomize is built with
+	DefaultKubernetesVersion = "1.20.4"
+
+	defaultStrict               = true
+	defaultIgnoreMissingSchemas = false
+
+	fileScheme = "file://"
+	gvkExt     = "x-kubernetes-group-version-kind"
+)
+
+// Validator validates documents against OpenAPI schemas offline. Schemas are taken from CRDs,
+// local schema location in kubeval layout and Kubernetes schemas built into airshipctl, in that order.
+// Built-in schemas are the ones of DefaultKubernetesVersion only, schemas of other versions must be
+// provided in the versioned subdirectory of the local schema location
+type Validator struct {
+	strict               bool
+	ignoreMissingSchemas bool
+	kindsToSkip          map[string]bool
+	schemaDir            string
+
+	crdSchemas   map[schema.GroupVersionKind]*spec.Schema
+	localSchemas map[schema.GroupVersionKind]*spec.Schema
+	definitions  spec.Definitions
+	builtin      map[schema.GroupVersionKind]string
+}
+
+// New returns validator configured by ValidationConfig, schemas of custom resources
+// are extracted from the CRD documents
+func New(cfg v1alpha1.ValidationConfig, crds []document.Document) (*Validator, error) {
+	v := &Validator{
+		strict:               defaultStrict,
+		ignoreMissingSchemas: defaultIgnoreMissingSchemas,
+		kindsToSkip:          map[string]bool{document.CRDKind: true},
+		localSchemas:         make(map[schema.GroupVersionKind]*spec.Schema),
+	}
+	if cfg.Strict != nil {
+		v.strict = *cfg.Strict
+	}
+	if cfg.IgnoreMissingSchemas != nil {
+		v.ignoreMissingSchemas = *cfg.IgnoreMissingSchemas
+	}
+	for _, kind := range cfg.KindsToSkip {
+		v.kindsToSkip[kind] = true
+	}
+	version := strings.TrimPrefix(cfg.KubernetesVersion, "v")
+	if version == "" {
+		version = DefaultKubernetesVersion
+	}
+	dir, versioned, err := localSchemaDir(cfg.SchemaLocation, version, v.strict)
+	if err != nil {
+		return nil, err
+	}
+	v.schemaDir = dir
+
+	if v.crdSchemas, err = crdSchemas(crds); err != nil {
+		return nil, err
+	}
+	switch {
+	case version == DefaultKubernetesVersion:
+		v.definitions, v.builtin = builtinSchemas()
+	case versioned:
+		// built-in schemas are of another version, so only the local ones are used
+		v.definitions, v.builtin = spec.Definitions{}, make(map[schema.GroupVersionKind]string)
+	default:
+		return nil, ErrUnsupportedKubernetesVersion{Version: version}
+	}
+	return v, nil
+}
+
+// Validate validates the documents and returns ErrValidationFailed with all
+// the errors found if any of documents is invalid
+func (v *Validator) Validate(docs []document.Document) error {
+	var failed []ResourceErrors
+	for _, doc := range docs {
+		errs, err := v.ValidateDocument(doc)
+		if err != nil {
+			return err
+		}
+		res := document.ResourceName(doc)
+		if len(errs) == 0 {
+			log.Debugf("Resource valid: %s", res)
+			continue
+		}
+		failed = append(failed, ResourceErrors{Resource: res, Errors: errs})
+	}
+	if len(failed) > 0 {
+		return ErrValidationFailed{Resources: failed}
+	}
+	return nil
+}
+
+// ValidateDocument returns field errors of the document
+func (v *Validator) ValidateDocument(doc document.Document) ([]FieldError, error) {
+	if v.kindsToSkip[doc.GetKind()] {
+		log.Debugf("Skipping validation of %s", document.ResourceName(doc))
+		return nil, nil
+	}
+	gvk := schema.GroupVersionKind{Group: doc.GetGroup(), Version: doc.GetVersion(), Kind: doc.GetKind()}
+	s, err := v.schema(gvk)
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		if v.ignoreMissingSchemas {
+			log.Debugf("Schema of %s is not found, skipping", document.ResourceName(doc))
+			return nil, nil
+		}
+		return nil, ErrSchemaNotFound{GVK: gvk}
+	}
+
+	obj := map[string]interface{}{}
+	if err = doc.ToObject(&obj); err != nil {
+		return nil, err
+	}
+	w := newWalker(v.definitions, v.strict)
+	w.validate("", obj, s)
+	return w.errs, nil
+}
+
+func (v *Validator) schema(gvk schema.GroupVersionKind) (*spec.Schema, error) {
+	if s, ok := v.crdSchemas[gvk]; ok {
+		return s, nil
+	}
+	s, err := v.localSchema(gvk)
+	if err != nil || s != nil {
+		return s, err
+	}
+	if name, ok := v.builtin[gvk]; ok {
+		def := v.definitions[name]
+		return &def, nil
+	}
+	return nil, nil
+}
+
+// localSchema reads the schema from the local schema location, files are named in kubeval manner,
+// e.g. deployment-apps-v1.json
+func (v *Validator) localSchema(gvk schema.GroupVersionKind) (*spec.Schema, error) {
+	if v.schemaDir == "" {
+		return nil, nil
+	}
+	if s, ok := v.localSchemas[gvk]; ok {
+		return s, nil
+	}
+
+	name := strings.ToLower(gvk.Kind)
+	if gvk.Group != "" {
+		name += "-" + strings.ToLower(strings.Split(gvk.Group, ".")[0])
+	}
+	name += "-" + strings.ToLower(gvk.Version) + ".json"
+	data, err := ioutil.ReadFile(filepath.Join(v.schemaDir, name))
+	if os.IsNotExist(err) {
+		v.localSchemas[gvk] = nil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	s := &spec.Schema{}
+	if err = json.Unmarshal(data, s); err != nil {
+		return nil, ErrInvalidSchema{GVK: gvk, Err: err}
+	}
+	v.localSchemas[gvk] = s
+	return s, nil
+}
+
+// localSchemaDir returns directory of the local schemas and whether it's the subdirectory of the
+// given Kubernetes version in kubeval layout, i.e. v<version>-standalone[-strict]. Remote locations
+// can't be used since validation is performed offline
+func localSchemaDir(location, version string, strict bool) (string, bool, error) {
+	if location == "" {
+		return "", false, nil
+	}
+	if strings.Contains(location, "://") && !strings.HasPrefix(location, fileScheme) {
+		return "", false, ErrSchemaLocation{Location: location, Reason: "remote locations are not supported"}
+	}
+	dir := strings.TrimPrefix(location, fileScheme)
+	if _, err := os.Stat(dir); err != nil {
+		return "", false, ErrSchemaLocation{Location: location, Reason: err.Error()}
+	}
+
+	candidates := []string{fmt.Sprintf("v%s-standalone", version)}
+	if strict {
+		candidates = append([]string{candidates[0] + "-strict"}, candidates...)
+	}
+	for _, candidate := range candidates {
+		versioned := filepath.Join(dir, candidate)
+		if info, err := os.Stat(versioned); err == nil && info.IsDir() {
+			return versioned, true, nil
+		}
+	}
+	return dir, false, nil
+}
+
+// crdSchemas extracts OpenAPI v3 schemas of all served versions of the CRDs,
+// both apiextensions.k8s.io/v1 and v1beta1 CRDs are supported
+func crdSchemas(crds []document.Document) (map[schema.GroupVersionKind]*spec.Schema, error) {
+	result := make(map[schema.GroupVersionKind]*spec.Schema)
+	for _, crd := range crds {
+		obj := map[string]interface{}{}
+		if err := crd.ToObject(&obj); err != nil {
+			return nil, err
+		}
+		group, _ := nested(obj, "spec", "group").(string)
+		kind, _ := nested(obj, "spec", "names", "kind").(string)
+		common := nested(obj, "spec", "validation", "openAPIV3Schema")
+
+		schemas := make(map[string]interface{})
+		if version, ok := nested(obj, "spec", "version").(string); ok && common != nil {
+			schemas[version] = common
+		}
+		versions, _ := nested(obj, "spec", "versions").([]interface{})
+		for _, item := range versions {
+			version, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := version["name"].(string)
+			s := nested(version, "schema", "openAPIV3Schema")
+			if s == nil {
+				s = common
+			}
+			if name != "" && s != nil {
+				schemas[name] = s
+			}
+		}
+
+		for version, raw := range schemas {
+			gvk := schema.GroupVersionKind{Group: group, Version: version, Kind: kind}
+			data, err := json.Marshal(raw)
+			if err != nil {
+				return nil, err
+			}
+			s := &spec.Schema{}
+			if err = json.Unmarshal(data, s); err != nil {
+				return nil, ErrInvalidSchema{GVK: gvk, Err: err}
+			}
+			result[gvk] = s
+		}
+	}
+	return result, nil
+}
+
+// builtinSchemas returns definitions of Kubernetes schemas built into airshipctl
+// and the index of the definitions by GVK
+func builtinSchemas() (spec.Definitions, map[schema.GroupVersionKind]string) {
+	index := make(map[schema.GroupVersionKind]string)
+	root := openapi.Schema()
+	if root == nil {
+		return spec.Definitions{}, index
+	}
+	for name, def := range root.Definitions {
+		gvks, ok := def.Extensions[gvkExt].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, item := range gvks {
+			m, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			group, _ := m["group"].(string)
+			version, _ := m["version"].(string)
+			kind, _ := m["kind"].(string)
+			index[schema.GroupVersionKind{Group: group, Version: version, Kind: kind}] = name
+		}
+	}
+	return root.Definitions, index
+}
+
+func nested(obj map[string]interface{}, fields ...string) interface{} {
+	var cur interface{} = obj
+	for _, field := range fields {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		cur = m[field]
+	}
+	return cur
+}
@@ -0,0 +1,302 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"opendev.org/airship/airshipctl/pkg/api/v1alpha1"
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/document/validator"
+)
+
+const (
+	crd = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+spec:
+  group: example.com
+  names:
+    kind: Widget
+    plural: widgets
+  scope: Namespaced
+  versions:
+  - name: v1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          apiVersion:
+            type: string
+          kind:
+            type: string
+          metadata:
+            type: object
+          spec:
+            type: object
+            required:
+            - size
+            properties:
+              size:
+                type: integer
+                minimum: 1
+              color:
+                type: string
+                enum:
+                - red
+                - blue
+              parts:
+                type: array
+                items:
+                  type: object
+                  properties:
+                    name:
+                      type: string
+`
+	validWidget = `apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: valid
+spec:
+  size: 2
+  color: red
+  parts:
+  - name: bolt
+`
+	invalidWidget = `apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: invalid
+spec:
+  color: green
+  parts:
+  - name: 42
+`
+	extraFieldWidget = `apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: extra
+spec:
+  size: 1
+  shape: round
+`
+	invalidDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: "three"
+  selector:
+    matchLabels:
+      app: app
+  template:
+    metadata:
+      labels:
+        app: app
+    spec:
+      containers:
+      - name: app
+        image: app:1.0
+`
+	unknownKind = `apiVersion: example.com/v1
+kind: Gadget
+metadata:
+  name: unknown
+`
+)
+
+func newDocs(t *testing.T, sources ...string) []document.Document {
+	t.Helper()
+	docs := make([]document.Document, 0, len(sources))
+	for _, src := range sources {
+		doc, err := document.NewDocumentFromBytes([]byte(src))
+		require.NoError(t, err)
+		docs = append(docs, doc)
+	}
+	return docs
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestValidateDocument(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         v1alpha1.ValidationConfig
+		doc         string
+		expected    []string
+		expectedErr error
+	}{
+		{
+			name: "valid custom resource",
+			doc:  validWidget,
+		},
+		{
+			name: "invalid custom resource",
+			doc:  invalidWidget,
+			expected: []string{
+				"spec: required property size is missing",
+				"spec.color: value green is not one of [red blue]",
+				"spec.parts[0].name: expected string, got integer",
+			},
+		},
+		{
+			name:     "unknown field in strict mode",
+			doc:      extraFieldWidget,
+			expected: []string{"spec.shape: additional property is not allowed"},
+		},
+		{
+			name: "unknown field in non strict mode",
+			cfg:  v1alpha1.ValidationConfig{Strict: boolPtr(false)},
+			doc:  extraFieldWidget,
+		},
+		{
+			name:     "built-in kubernetes schema",
+			doc:      invalidDeployment,
+			expected: []string{"spec.replicas: expected integer, got string"},
+		},
+		{
+			name: "skipped kind",
+			cfg:  v1alpha1.ValidationConfig{KindsToSkip: []string{"Deployment"}},
+			doc:  invalidDeployment,
+		},
+		{
+			name: "missing schema is ignored",
+			cfg:  v1alpha1.ValidationConfig{IgnoreMissingSchemas: boolPtr(true)},
+			doc:  unknownKind,
+		},
+		{
+			name: "local schema location",
+			cfg:  v1alpha1.ValidationConfig{SchemaLocation: "file://testdata/schemas"},
+			doc:  unknownKind,
+		},
+		{
+			name: "missing schema",
+			doc:  unknownKind,
+			expectedErr: validator.ErrSchemaNotFound{
+				GVK: schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Gadget"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := validator.New(tt.cfg, newDocs(t, crd))
+			require.NoError(t, err)
+
+			errs, err := v.ValidateDocument(newDocs(t, tt.doc)[0])
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				return
+			}
+			require.NoError(t, err)
+			actual := make([]string, 0, len(errs))
+			for _, fieldErr := range errs {
+				actual = append(actual, fieldErr.String())
+			}
+			assert.ElementsMatch(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	v, err := validator.New(v1alpha1.ValidationConfig{}, newDocs(t, crd))
+	require.NoError(t, err)
+
+	assert.NoError(t, v.Validate(newDocs(t, crd, validWidget)))
+
+	err = v.Validate(newDocs(t, validWidget, extraFieldWidget, invalidDeployment))
+	require.Error(t, err)
+	failed, ok := err.(validator.ErrValidationFailed)
+	require.True(t, ok)
+	require.Len(t, failed.Resources, 2)
+	assert.Contains(t, failed.Resources[0].Resource, "extra")
+	assert.Contains(t, failed.Resources[1].Resource, "app")
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         v1alpha1.ValidationConfig
+		expectedErr error
+	}{
+		{
+			name: "default kubernetes version",
+			cfg:  v1alpha1.ValidationConfig{KubernetesVersion: "v" + validator.DefaultKubernetesVersion},
+		},
+		{
+			name:        "unsupported kubernetes version",
+			cfg:         v1alpha1.ValidationConfig{KubernetesVersion: "1.18.6"},
+			expectedErr: validator.ErrUnsupportedKubernetesVersion{Version: "1.18.6"},
+		},
+		{
+			name: "unsupported kubernetes version without versioned schemas",
+			cfg: v1alpha1.ValidationConfig{
+				KubernetesVersion: "1.18.6",
+				SchemaLocation:    "testdata/schemas",
+			},
+			expectedErr: validator.ErrUnsupportedKubernetesVersion{Version: "1.18.6"},
+		},
+		{
+			name: "remote schema location",
+			cfg:  v1alpha1.ValidationConfig{SchemaLocation: "https://example.com/schemas"},
+			expectedErr: validator.ErrSchemaLocation{
+				Location: "https://example.com/schemas",
+				Reason:   "remote locations are not supported",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validator.New(tt.cfg, nil)
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
+
+func TestNewMissingSchemaLocation(t *testing.T) {
+	_, err := validator.New(v1alpha1.ValidationConfig{SchemaLocation: "file://testdata/missing"}, nil)
+	require.Error(t, err)
+	assert.IsType(t, validator.ErrSchemaLocation{}, err)
+}
+
+func TestVersionedSchemaLocation(t *testing.T) {
+	v, err := validator.New(v1alpha1.ValidationConfig{
+		KubernetesVersion: "1.19.0",
+		SchemaLocation:    "file://testdata/schemas",
+	}, nil)
+	require.NoError(t, err)
+
+	errs, err := v.ValidateDocument(newDocs(t, unknownKind)[0])
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+
+	// built-in schemas are of another version and aren't used
+	_, err = v.ValidateDocument(newDocs(t, invalidDeployment)[0])
+	assert.Equal(t, validator.ErrSchemaNotFound{
+		GVK: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+	}, err)
+}
@@ -0,0 +1,342 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package validator
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-openapi/spec"
+)
+
+const (
+	definitionsPrefix        = "#/definitions/"
+	preserveUnknownFieldsExt = "x-kubernetes-preserve-unknown-fields"
+	intOrStringExt           = "x-kubernetes-int-or-string"
+	intOrStringFormat        = "int-or-string"
+	maxRefDepth              = 32
+)
+
+// stringOrNumberDefinitions are the definitions declared as strings which accept numbers as well
+var stringOrNumberDefinitions = map[string]bool{
+	"io.k8s.apimachinery.pkg.util.intstr.IntOrString": true,
+	"io.k8s.apimachinery.pkg.api.resource.Quantity":   true,
+}
+
+// walker validates the object against the schema collecting errors with field paths
+type walker struct {
+	definitions spec.Definitions
+	strict      bool
+	patterns    map[string]*regexp.Regexp
+	errs        []FieldError
+}
+
+func newWalker(definitions spec.Definitions, strict bool) *walker {
+	return &walker{
+		definitions: definitions,
+		strict:      strict,
+		patterns:    make(map[string]*regexp.Regexp),
+	}
+}
+
+func (w *walker) fail(path string, format string, a ...interface{}) {
+	if path == "" {
+		path = "<root>"
+	}
+	w.errs = append(w.errs, FieldError{Path: path, Message: fmt.Sprintf(format, a...)})
+}
+
+func (w *walker) validate(path string, value interface{}, s *spec.Schema) {
+	s, stringOrNumber := w.resolve(s)
+	if s == nil || value == nil {
+		return
+	}
+	if stringOrNumber || isIntOrString(s) {
+		switch value.(type) {
+		case string, float64, int, int64:
+		default:
+			w.fail(path, "expected integer or string, got %s", typeName(value))
+		}
+		return
+	}
+
+	for i := range s.AllOf {
+		w.validate(path, value, &s.AllOf[i])
+	}
+	if len(s.AnyOf) > 0 && w.matches(value, s.AnyOf) == 0 {
+		w.fail(path, "value doesn't match any of the allowed schemas")
+	}
+	if len(s.OneOf) > 0 {
+		if n := w.matches(value, s.OneOf); n != 1 {
+			w.fail(path, "value must match exactly one schema, matches %d", n)
+		}
+	}
+	if s.Not != nil && w.matches(value, []spec.Schema{*s.Not}) == 1 {
+		w.fail(path, "value must not match the schema")
+	}
+
+	if len(s.Type) > 0 && !typeMatches(s.Type, value) {
+		w.fail(path, "expected %s, got %s", strings.Join(s.Type, " or "), typeName(value))
+		return
+	}
+	if len(s.Enum) > 0 && !inEnum(value, s.Enum) {
+		w.fail(path, "value %v is not one of %v", value, s.Enum)
+	}
+
+	switch val := value.(type) {
+	case string:
+		w.validateString(path, val, s)
+	case float64:
+		w.validateNumber(path, val, s)
+	case []interface{}:
+		w.validateArray(path, val, s)
+	case map[string]interface{}:
+		w.validateObject(path, val, s)
+	}
+}
+
+// resolve follows schema references, true is returned if reference points to the
+// definition which accepts both strings and numbers
+func (w *walker) resolve(s *spec.Schema) (*spec.Schema, bool) {
+	for i := 0; s != nil && i < maxRefDepth; i++ {
+		ref := s.Ref.String()
+		if ref == "" {
+			return s, false
+		}
+		name := strings.TrimPrefix(ref, definitionsPrefix)
+		if stringOrNumberDefinitions[name] {
+			return s, true
+		}
+		def, ok := w.definitions[name]
+		if !ok {
+			// unknown references aren't validated
+			return nil, false
+		}
+		s = &def
+	}
+	return nil, false
+}
+
+// matches returns number of schemas the value is valid against
+func (w *walker) matches(value interface{}, schemas []spec.Schema) int {
+	n := 0
+	for i := range schemas {
+		sub := newWalker(w.definitions, w.strict)
+		sub.patterns = w.patterns
+		sub.validate("", value, &schemas[i])
+		if len(sub.errs) == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+func (w *walker) validateString(path string, val string, s *spec.Schema) {
+	length := int64(utf8.RuneCountInString(val))
+	if s.MinLength != nil && length < *s.MinLength {
+		w.fail(path, "length must be greater than or equal to %d", *s.MinLength)
+	}
+	if s.MaxLength != nil && length > *s.MaxLength {
+		w.fail(path, "length must be less than or equal to %d", *s.MaxLength)
+	}
+	if s.Pattern != "" {
+		re, ok := w.patterns[s.Pattern]
+		if !ok {
+			// patterns which aren't supported by RE2 syntax are skipped
+			re, _ = regexp.Compile(s.Pattern)
+			w.patterns[s.Pattern] = re
+		}
+		if re != nil && !re.MatchString(val) {
+			w.fail(path, "value %q doesn't match pattern %s", val, s.Pattern)
+		}
+	}
+}
+
+func (w *walker) validateNumber(path string, val float64, s *spec.Schema) {
+	if s.Minimum != nil && (val < *s.Minimum || (s.ExclusiveMinimum && val == *s.Minimum)) {
+		w.fail(path, "value %v must be greater than%s %v", val, orEqual(s.ExclusiveMinimum), *s.Minimum)
+	}
+	if s.Maximum != nil && (val > *s.Maximum || (s.ExclusiveMaximum && val == *s.Maximum)) {
+		w.fail(path, "value %v must be less than%s %v", val, orEqual(s.ExclusiveMaximum), *s.Maximum)
+	}
+	if s.MultipleOf != nil && *s.MultipleOf != 0 && math.Mod(val, *s.MultipleOf) != 0 {
+		w.fail(path, "value %v must be a multiple of %v", val, *s.MultipleOf)
+	}
+}
+
+func (w *walker) validateArray(path string, val []interface{}, s *spec.Schema) {
+	if s.MinItems != nil && int64(len(val)) < *s.MinItems {
+		w.fail(path, "must have at least %d items", *s.MinItems)
+	}
+	if s.MaxItems != nil && int64(len(val)) > *s.MaxItems {
+		w.fail(path, "must have at most %d items", *s.MaxItems)
+	}
+	if s.UniqueItems {
+		for i := range val {
+			for j := i + 1; j < len(val); j++ {
+				if reflect.DeepEqual(val[i], val[j]) {
+					w.fail(fmt.Sprintf("%s[%d]", path, j), "duplicates item %d", i)
+				}
+			}
+		}
+	}
+	if s.Items == nil {
+		return
+	}
+	for i, item := range val {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		switch {
+		case s.Items.Schema != nil:
+			w.validate(itemPath, item, s.Items.Schema)
+		case i < len(s.Items.Schemas):
+			w.validate(itemPath, item, &s.Items.Schemas[i])
+		}
+	}
+}
+
+func (w *walker) validateObject(path string, val map[string]interface{}, s *spec.Schema) {
+	for _, req := range s.Required {
+		if _, ok := val[req]; !ok {
+			w.fail(path, "required property %s is missing", req)
+		}
+	}
+	if s.MinProperties != nil && int64(len(val)) < *s.MinProperties {
+		w.fail(path, "must have at least %d properties", *s.MinProperties)
+	}
+	if s.MaxProperties != nil && int64(len(val)) > *s.MaxProperties {
+		w.fail(path, "must have at most %d properties", *s.MaxProperties)
+	}
+
+	keys := make([]string, 0, len(val))
+	for key := range val {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		child := key
+		if path != "" {
+			child = path + "." + key
+		}
+		if prop, ok := s.Properties[key]; ok {
+			w.validate(child, val[key], &prop)
+			continue
+		}
+		if s.AdditionalProperties != nil {
+			if s.AdditionalProperties.Schema != nil {
+				w.validate(child, val[key], s.AdditionalProperties.Schema)
+			} else if !s.AdditionalProperties.Allows {
+				w.fail(child, "additional property is not allowed")
+			}
+			continue
+		}
+		if w.strict && len(s.Properties) > 0 && !preservesUnknownFields(s) {
+			w.fail(child, "additional property is not allowed")
+		}
+	}
+}
+
+func isIntOrString(s *spec.Schema) bool {
+	if s.Format == intOrStringFormat {
+		return true
+	}
+	v, ok := s.Extensions.GetBool(intOrStringExt)
+	return ok && v
+}
+
+func preservesUnknownFields(s *spec.Schema) bool {
+	v, ok := s.Extensions.GetBool(preserveUnknownFieldsExt)
+	return ok && v
+}
+
+func typeMatches(types spec.StringOrArray, value interface{}) bool {
+	for _, t := range types {
+		switch val := value.(type) {
+		case map[string]interface{}:
+			if t == "object" {
+				return true
+			}
+		case []interface{}:
+			if t == "array" {
+				return true
+			}
+		case string:
+			if t == "string" {
+				return true
+			}
+		case bool:
+			if t == "boolean" {
+				return true
+			}
+		case float64:
+			if t == "number" || (t == "integer" && val == math.Trunc(val)) {
+				return true
+			}
+		case int, int64:
+			if t == "number" || t == "integer" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func typeName(value interface{}) string {
+	switch val := value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if val == math.Trunc(val) {
+			return "integer"
+		}
+		return "number"
+	case int, int64:
+		return "integer"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func inEnum(value interface{}, enum []interface{}) bool {
+	for _, e := range enum {
+		if reflect.DeepEqual(value, e) {
+			return true
+		}
+		// numbers may be decoded into different types
+		if fmt.Sprint(value) == fmt.Sprint(e) {
+			return true
+		}
+	}
+	return false
+}
+
+func orEqual(exclusive bool) string {
+	if exclusive {
+		return ""
+	}
+	return " or equal to"
+}
@@ -0,0 +1,265 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package document
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+
+	"opendev.org/airship/airshipctl/pkg/fs"
+)
+
+const (
+	// OCIBundleConfigMediaType is the media type of the config of OCI artifact containing the bundle
+	OCIBundleConfigMediaType = "application/vnd.airshipit.bundle.config.v1+json"
+	// OCIBundleLayerTitle is the title of the OCI artifact layer containing the bundle tarball
+	OCIBundleLayerTitle = "bundle.tar.gz"
+
+	ociManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+	ociLayerMediaType    = "application/vnd.oci.image.layer.v1.tar+gzip"
+	ociImageIndexFile    = "index.json"
+	ociLayoutFile        = "oci-layout"
+	ociLayoutVersion     = "1.0.0"
+	ociBlobsDir          = "blobs/sha256"
+	ociRefNameAnnotation = "org.opencontainers.image.ref.name"
+	ociTitleAnnotation   = "org.opencontainers.image.title"
+)
+
+type bundleFile struct {
+	path    string
+	content []byte
+}
+
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type ociManifest struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	MediaType     string          `json:"mediaType,omitempty"`
+	Config        ociDescriptor   `json:"config"`
+	Layers        []ociDescriptor `json:"layers"`
+}
+
+type ociIndex struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	Manifests     []ociDescriptor `json:"manifests"`
+}
+
+// DocumentFilePath returns path of the file the document is written to by the bundle writers,
+// it is <namespace>/<kind>-<name>.yaml for namespaced documents and <kind>-<name>.yaml otherwise
+func DocumentFilePath(doc Document) string {
+	return filepath.Join(doc.GetNamespace(), fmt.Sprintf("%s-%s.yaml", strings.ToLower(doc.GetKind()), doc.GetName()))
+}
+
+// WriteBundleFiles writes each document of the bundle to a separate file inside of the directory,
+// see DocumentFilePath for the layout. Paths of the files relative to the directory are returned
+// in the order of the documents
+func WriteBundleFiles(fSys fs.FileSystem, bundle Bundle, dir string) ([]string, error) {
+	files, err := documentFiles(bundle)
+	if err != nil {
+		return nil, err
+	}
+	if err = writeFiles(fSys, dir, files); err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		paths = append(paths, file.path)
+	}
+	return paths, nil
+}
+
+// WriteBundleKustomization writes each document of the bundle to a separate file along with the
+// kustomization which lists them as resources, so that the directory can be used as kustomize
+// entrypoint or handed to GitOps tools
+func WriteBundleKustomization(fSys fs.FileSystem, bundle Bundle, dir string) error {
+	files, err := kustomizationFiles(bundle)
+	if err != nil {
+		return err
+	}
+	return writeFiles(fSys, dir, files)
+}
+
+// WriteBundleTarball writes gzipped tarball of the kustomization directory produced by
+// WriteBundleKustomization. The tarball is reproducible, i.e. the same bundle always results in
+// the same bytes
+func WriteBundleTarball(out io.Writer, bundle Bundle) error {
+	files, err := kustomizationFiles(bundle)
+	if err != nil {
+		return err
+	}
+
+	gw := gzip.NewWriter(out)
+	tw := tar.NewWriter(gw)
+	for _, file := range files {
+		hdr := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     filepath.ToSlash(file.path),
+			Mode:     0644,
+			Size:     int64(len(file.content)),
+			ModTime:  time.Unix(0, 0),
+			Format:   tar.FormatPAX,
+		}
+		if err = tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err = tw.Write(file.content); err != nil {
+			return err
+		}
+	}
+	if err = tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// WriteBundleOCILayout writes OCI image layout to the directory. The layout contains an artifact
+// with a single layer holding the tarball produced by WriteBundleTarball, the manifest of the
+// artifact is referenced from the index by the reference name, e.g. the tag
+func WriteBundleOCILayout(fSys fs.FileSystem, bundle Bundle, dir, refName string) error {
+	tarball := &bytes.Buffer{}
+	if err := WriteBundleTarball(tarball, bundle); err != nil {
+		return err
+	}
+	config := []byte("{}")
+	manifest := ociManifest{
+		SchemaVersion: 2,
+		MediaType:     ociManifestMediaType,
+		Config:        descriptor(OCIBundleConfigMediaType, config, nil),
+		Layers: []ociDescriptor{
+			descriptor(ociLayerMediaType, tarball.Bytes(), map[string]string{ociTitleAnnotation: OCIBundleLayerTitle}),
+		},
+	}
+	manifestJSON, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	var annotations map[string]string
+	if refName != "" {
+		annotations = map[string]string{ociRefNameAnnotation: refName}
+	}
+	index, err := json.Marshal(ociIndex{
+		SchemaVersion: 2,
+		Manifests:     []ociDescriptor{descriptor(ociManifestMediaType, manifestJSON, annotations)},
+	})
+	if err != nil {
+		return err
+	}
+
+	files := []bundleFile{
+		{path: ociLayoutFile, content: []byte(fmt.Sprintf(`{"imageLayoutVersion":"%s"}`, ociLayoutVersion))},
+		{path: ociImageIndexFile, content: index},
+	}
+	for _, blob := range [][]byte{config, tarball.Bytes(), manifestJSON} {
+		files = append(files, bundleFile{path: filepath.Join(ociBlobsDir, digestHex(blob)), content: blob})
+	}
+	return writeFiles(fSys, dir, files)
+}
+
+func documentFiles(bundle Bundle) ([]bundleFile, error) {
+	docs, err := bundle.GetAllDocuments()
+	if err != nil {
+		return nil, err
+	}
+	files := make([]bundleFile, 0, len(docs))
+	paths := map[string]bool{}
+	for _, doc := range docs {
+		path := DocumentFilePath(doc)
+		if paths[path] && doc.GetGroup() != "" {
+			// the same kind and name in different API groups, e.g. Cluster of CAPI and of the provider
+			path = filepath.Join(doc.GetNamespace(),
+				fmt.Sprintf("%s.%s-%s.yaml", strings.ToLower(doc.GetKind()), doc.GetGroup(), doc.GetName()))
+		}
+		if paths[path] {
+			return nil, ErrDuplicateDocumentPath{Path: path}
+		}
+		paths[path] = true
+
+		content, err := doc.AsYAML()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, bundleFile{path: path, content: content})
+	}
+	return files, nil
+}
+
+func kustomizationFiles(bundle Bundle) ([]bundleFile, error) {
+	files, err := documentFiles(bundle)
+	if err != nil {
+		return nil, err
+	}
+	k := struct {
+		types.TypeMeta `json:",inline"`
+		Resources      []string `json:"resources"`
+	}{
+		TypeMeta: types.TypeMeta{
+			APIVersion: types.KustomizationVersion,
+			Kind:       types.KustomizationKind,
+		},
+		Resources: []string{},
+	}
+	for _, file := range files {
+		k.Resources = append(k.Resources, filepath.ToSlash(file.path))
+	}
+	content, err := yaml.Marshal(k)
+	if err != nil {
+		return nil, err
+	}
+	return append([]bundleFile{{path: KustomizationFile, content: content}}, files...), nil
+}
+
+func writeFiles(fSys fs.FileSystem, dir string, files []bundleFile) error {
+	for _, file := range files {
+		path := filepath.Join(dir, file.path)
+		if err := fSys.MkdirAll(filepath.Dir(path)); err != nil {
+			return err
+		}
+		if err := fSys.WriteFile(path, file.content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func descriptor(mediaType string, content []byte, annotations map[string]string) ociDescriptor {
+	return ociDescriptor{
+		MediaType:   mediaType,
+		Digest:      "sha256:" + digestHex(content),
+		Size:        int64(len(content)),
+		Annotations: annotations,
+	}
+}
+
+func digestHex(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,205 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package document_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	kustfs "sigs.k8s.io/kustomize/kyaml/filesys"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	testfs "opendev.org/airship/airshipctl/testutil/fs"
+)
+
+const writerDocs = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: frontend
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: frontend
+spec:
+  replicas: 1
+---
+apiVersion: cluster.x-k8s.io/v1alpha3
+kind: Cluster
+metadata:
+  name: target-cluster
+  namespace: target-infra
+---
+apiVersion: example.com/v1
+kind: Cluster
+metadata:
+  name: target-cluster
+  namespace: target-infra
+`
+
+var writerPaths = []string{
+	"namespace-frontend.yaml",
+	"frontend/deployment-web.yaml",
+	"target-infra/cluster-target-cluster.yaml",
+	"target-infra/cluster.example.com-target-cluster.yaml",
+}
+
+const writerKustomization = `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- namespace-frontend.yaml
+- frontend/deployment-web.yaml
+- target-infra/cluster-target-cluster.yaml
+- target-infra/cluster.example.com-target-cluster.yaml
+`
+
+func writerBundle(t *testing.T, docs string) document.Bundle {
+	t.Helper()
+	bundle, err := document.NewBundleFromBytes([]byte(docs))
+	require.NoError(t, err)
+	return bundle
+}
+
+func TestWriteBundleFiles(t *testing.T) {
+	fSys := testfs.MockFileSystem{FileSystem: kustfs.MakeFsInMemory()}
+	paths, err := document.WriteBundleFiles(fSys, writerBundle(t, writerDocs), "/out")
+	require.NoError(t, err)
+	assert.Equal(t, writerPaths, paths)
+
+	content, err := fSys.ReadFile("/out/frontend/deployment-web.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: frontend
+spec:
+  replicas: 1
+`, string(content))
+	assert.False(t, fSys.Exists("/out/kustomization.yaml"))
+
+	duplicate := writerDocs + "---\napiVersion: v1\nkind: Namespace\nmetadata:\n  name: frontend\n"
+	_, err = document.WriteBundleFiles(fSys, writerBundle(t, duplicate), "/duplicate")
+	assert.Equal(t, document.ErrDuplicateDocumentPath{Path: "namespace-frontend.yaml"}, err)
+}
+
+func TestWriteBundleKustomization(t *testing.T) {
+	fSys := testfs.MockFileSystem{FileSystem: kustfs.MakeFsInMemory()}
+	require.NoError(t, document.WriteBundleKustomization(fSys, writerBundle(t, writerDocs), "/out"))
+
+	content, err := fSys.ReadFile("/out/kustomization.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, writerKustomization, string(content))
+	for _, path := range writerPaths {
+		assert.True(t, fSys.Exists(filepath.Join("/out", path)), path)
+	}
+}
+
+func TestWriteBundleTarball(t *testing.T) {
+	buf := &bytes.Buffer{}
+	require.NoError(t, document.WriteBundleTarball(buf, writerBundle(t, writerDocs)))
+
+	files := readTarball(t, buf.Bytes())
+	assert.Equal(t, append([]string{"kustomization.yaml"}, writerPaths...), files.names)
+	assert.Equal(t, writerKustomization, files.content["kustomization.yaml"])
+	assert.True(t, strings.HasPrefix(files.content["namespace-frontend.yaml"], "apiVersion: v1\nkind: Namespace\n"))
+
+	// the same bundle produces exactly the same tarball
+	again := &bytes.Buffer{}
+	require.NoError(t, document.WriteBundleTarball(again, writerBundle(t, writerDocs)))
+	assert.Equal(t, buf.Bytes(), again.Bytes())
+}
+
+func TestWriteBundleOCILayout(t *testing.T) {
+	fSys := testfs.MockFileSystem{FileSystem: kustfs.MakeFsInMemory()}
+	bundle := writerBundle(t, writerDocs)
+	require.NoError(t, document.WriteBundleOCILayout(fSys, bundle, "/oci", "initinfra"))
+
+	layout, err := fSys.ReadFile("/oci/oci-layout")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"imageLayoutVersion":"1.0.0"}`, string(layout))
+
+	type descriptor struct {
+		MediaType   string            `json:"mediaType"`
+		Digest      string            `json:"digest"`
+		Size        int               `json:"size"`
+		Annotations map[string]string `json:"annotations"`
+	}
+	readBlob := func(d descriptor) []byte {
+		blob, readErr := fSys.ReadFile(filepath.Join("/oci/blobs/sha256", strings.TrimPrefix(d.Digest, "sha256:")))
+		require.NoError(t, readErr)
+		assert.Len(t, blob, d.Size)
+		return blob
+	}
+
+	index := struct {
+		Manifests []descriptor `json:"manifests"`
+	}{}
+	indexJSON, err := fSys.ReadFile("/oci/index.json")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(indexJSON, &index))
+	require.Len(t, index.Manifests, 1)
+	assert.Equal(t, "application/vnd.oci.image.manifest.v1+json", index.Manifests[0].MediaType)
+	assert.Equal(t, map[string]string{"org.opencontainers.image.ref.name": "initinfra"}, index.Manifests[0].Annotations)
+
+	manifest := struct {
+		Config descriptor   `json:"config"`
+		Layers []descriptor `json:"layers"`
+	}{}
+	require.NoError(t, json.Unmarshal(readBlob(index.Manifests[0]), &manifest))
+	assert.Equal(t, document.OCIBundleConfigMediaType, manifest.Config.MediaType)
+	assert.Equal(t, "{}", string(readBlob(manifest.Config)))
+	require.Len(t, manifest.Layers, 1)
+	assert.Equal(t, document.OCIBundleLayerTitle, manifest.Layers[0].Annotations["org.opencontainers.image.title"])
+
+	tarball := &bytes.Buffer{}
+	require.NoError(t, document.WriteBundleTarball(tarball, bundle))
+	assert.Equal(t, tarball.Bytes(), readBlob(manifest.Layers[0]))
+}
+
+type tarballFiles struct {
+	names   []string
+	content map[string]string
+}
+
+func readTarball(t *testing.T, data []byte) tarballFiles {
+	t.Helper()
+	gr, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	tr := tar.NewReader(gr)
+	files := tarballFiles{content: map[string]string{}}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := ioutil.ReadAll(tr)
+		require.NoError(t, err)
+		files.names = append(files.names, hdr.Name)
+		files.content[hdr.Name] = string(content)
+	}
+	return files
+}
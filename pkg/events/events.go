@@ -94,8 +94,10 @@ var bootstrapOperationToString = map[BootstrapOperation]string{
 }
 
 var genericContainerOperationToString = map[GenericContainerOperation]string{
-	GenericContainerStart: "GenericContainerStart",
-	GenericContainerStop:  "GenericContainerStop",
+	GenericContainerStart:     "GenericContainerStart",
+	GenericContainerStop:      "GenericContainerStop",
+	GenericContainerStepStart: "GenericContainerStepStart",
+	GenericContainerStepStop:  "GenericContainerStepStop",
 }
 
 var baremetalInventoryOperationToString = map[BaremetalManagerStep]string{
@@ -219,6 +221,10 @@ const (
 	GenericContainerStart GenericContainerOperation = iota
 	// GenericContainerStop operation
 	GenericContainerStop
+	// GenericContainerStepStart operation
+	GenericContainerStepStart
+	// GenericContainerStepStop operation
+	GenericContainerStepStop
 )
 
 // GenericContainerEvent needs to to track events in GenericContainer executor
@@ -23,6 +23,7 @@ import (
 
 	"opendev.org/airship/airshipctl/pkg/api/v1alpha1"
 	"opendev.org/airship/airshipctl/pkg/cluster/clustermap"
+	"opendev.org/airship/airshipctl/pkg/config"
 	"opendev.org/airship/airshipctl/pkg/document"
 	"opendev.org/airship/airshipctl/pkg/fs"
 	"opendev.org/airship/airshipctl/pkg/k8s/utils"
@@ -46,6 +47,7 @@ type Builder struct {
 	bundle       document.Bundle
 	client       corev1.CoreV1Interface
 	clusterMap   clustermap.ClusterMap
+	overrides    map[string]*config.KubeconfigOverride
 	fs           fs.FileSystem
 	siteKubeconf *api.Config
 }
@@ -62,6 +64,13 @@ func (b *Builder) WithClusterMap(cMap clustermap.ClusterMap) *Builder {
 	return b
 }
 
+// WithKubeconfigOverrides allows to set kubeconfig overrides of the clusters keyed by cluster name,
+// override file is used instead of the kubeconfig sources of the cluster map
+func (b *Builder) WithKubeconfigOverrides(overrides map[string]*config.KubeconfigOverride) *Builder {
+	b.overrides = overrides
+	return b
+}
+
 // WithClusterName allows to reach to a cluster to download kubeconfig from there
 func (b *Builder) WithClusterName(clusterName string) *Builder {
 	b.clusterName = clusterName
@@ -160,15 +169,27 @@ func (b *Builder) buildOne(clusterID string) (string, *api.Config, error) {
 		return "", nil, err
 	}
 
+	override := b.overrides[clusterID]
+	if override != nil && override.Path != "" {
+		// explicitly configured override must not silently fall back to the cluster map sources
+		log.Debugf("Using kubeconfig override for cluster '%s' from file '%s'", clusterID, override.Path)
+		oneKubeconf, sourceErr := b.trySource(clusterID, destContext, v1alpha1.KubeconfigSource{
+			Type: v1alpha1.KubeconfigSourceTypeFilesystem,
+			FileSystem: v1alpha1.KubeconfigSourceFilesystem{
+				Path:    override.Path,
+				Context: override.ContextName,
+			},
+		})
+		if sourceErr != nil {
+			return "", nil, &ErrKubeconfigOverrideFailed{ClusterName: clusterID, Path: override.Path, Err: sourceErr}
+		}
+		return b.mergeOne(clusterID, destContext, oneKubeconf)
+	}
+
 	for _, source := range sources {
 		oneKubeconf, sourceErr := b.trySource(clusterID, destContext, source)
 		if sourceErr == nil {
-			// Merge source context into site kubeconfig
-			log.Debugf("Merging kubecontext for cluster '%s', into site kubeconfig", clusterID)
-			if err = mergeContextAPI(destContext, destContext, b.siteKubeconf, oneKubeconf); err != nil {
-				return "", nil, err
-			}
-			return destContext, oneKubeconf, err
+			return b.mergeOne(clusterID, destContext, oneKubeconf)
 		}
 		// if error, log it and ignore it. missing problem with one kubeconfig should not
 		// effect other clusters, which don't depend on it. If they do depend on it, their calls
@@ -181,6 +202,19 @@ func (b *Builder) buildOne(clusterID string) (string, *api.Config, error) {
 	return "", nil, &ErrAllSourcesFailed{ClusterName: clusterID}
 }
 
+// mergeOne applies the proxy URL override to the cluster kubeconfig and merges it into site kubeconfig
+func (b *Builder) mergeOne(clusterID, destContext string, oneKubeconf *api.Config) (string, *api.Config, error) {
+	if override := b.overrides[clusterID]; override != nil && override.ProxyURL != "" {
+		setProxyURL(destContext, override.ProxyURL, oneKubeconf)
+	}
+	// Merge source context into site kubeconfig
+	log.Debugf("Merging kubecontext for cluster '%s', into site kubeconfig", clusterID)
+	if err := mergeContextAPI(destContext, destContext, b.siteKubeconf, oneKubeconf); err != nil {
+		return "", nil, err
+	}
+	return destContext, oneKubeconf, nil
+}
+
 func (b *Builder) trySource(clusterID, dstContext string, source v1alpha1.KubeconfigSource) (*api.Config, error) {
 	var getter KubeSourceFunc
 	// TODO add sourceContext defaults
@@ -316,6 +350,18 @@ func mergeContextAPI(destContext, sourceContext string, dst, src *api.Config) er
 	return nil
 }
 
+// setProxyURL sets proxy URL of the cluster referenced by the context
+func setProxyURL(contextName, proxyURL string, kubeconf *api.Config) {
+	context, exists := kubeconf.Contexts[contextName]
+	if !exists {
+		return
+	}
+	if cluster, exists := kubeconf.Clusters[context.Cluster]; exists {
+		log.Debugf("Setting proxy URL '%s' for kubeconfig cluster '%s'", proxyURL, context.Cluster)
+		cluster.ProxyURL = proxyURL
+	}
+}
+
 func emptyConfig() *api.Config {
 	return &api.Config{
 		Contexts:  make(map[string]*api.Context),
@@ -29,6 +29,7 @@ import (
 
 	"opendev.org/airship/airshipctl/pkg/api/v1alpha1"
 	"opendev.org/airship/airshipctl/pkg/cluster/clustermap"
+	"opendev.org/airship/airshipctl/pkg/config"
 	"opendev.org/airship/airshipctl/pkg/document"
 	"opendev.org/airship/airshipctl/pkg/fs"
 	"opendev.org/airship/airshipctl/pkg/k8s/kubeconfig"
@@ -291,6 +292,111 @@ func TestBuilderClusterctl(t *testing.T) {
 	}
 }
 
+func TestBuilderKubeconfigOverrides(t *testing.T) {
+	clusterID := "parent"
+	testBundle, err := document.NewBundleByPath("testdata")
+	require.NoError(t, err)
+	clusterMap := clustermap.NewClusterMap(&v1alpha1.ClusterMap{
+		Map: map[string]*v1alpha1.Cluster{
+			clusterID: {
+				Sources: []v1alpha1.KubeconfigSource{
+					{
+						Type: v1alpha1.KubeconfigSourceTypeBundle,
+						Bundle: v1alpha1.KubeconfigSourceBundle{
+							Context: "parent_parent_context",
+						},
+					},
+				},
+			},
+		},
+	})
+
+	tests := []struct {
+		name             string
+		overrides        map[string]*config.KubeconfigOverride
+		expectedCluster  string
+		expectedUser     string
+		expectedProxyURL string
+		expectedErr      string
+	}{
+		{
+			name:            "no overrides",
+			expectedCluster: "parent_parent_cluster",
+			expectedUser:    "parent_parent_admin",
+		},
+		{
+			name: "override file",
+			overrides: map[string]*config.KubeconfigOverride{
+				clusterID: {
+					Path:        "testdata/kubeconfig",
+					ContextName: "dummy_cluster",
+				},
+			},
+			expectedCluster: "dummycluster_ephemeral",
+			expectedUser:    "kubernetes-admin",
+		},
+		{
+			name: "override file does not exist",
+			overrides: map[string]*config.KubeconfigOverride{
+				clusterID: {
+					Path: "testdata/does-not-exist",
+				},
+			},
+			expectedErr: "failed to get kubeconfig for cluster 'parent' from override file 'testdata/does-not-exist'",
+		},
+		{
+			name: "override proxy url",
+			overrides: map[string]*config.KubeconfigOverride{
+				clusterID: {
+					ProxyURL: "socks5://bastion:1080",
+				},
+			},
+			expectedCluster:  "parent_parent_cluster",
+			expectedUser:     "parent_parent_admin",
+			expectedProxyURL: "socks5://bastion:1080",
+		},
+		{
+			name: "override for other cluster",
+			overrides: map[string]*config.KubeconfigOverride{
+				"other": {
+					Path:     "testdata/kubeconfig",
+					ProxyURL: "socks5://bastion:1080",
+				},
+			},
+			expectedCluster: "parent_parent_cluster",
+			expectedUser:    "parent_parent_admin",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			kube := kubeconfig.NewBuilder().
+				WithClusterMap(clusterMap).
+				WithKubeconfigOverrides(tt.overrides).
+				WithClusterName(clusterID).
+				WithBundle(testBundle).
+				Build()
+			require.NotNil(t, kube)
+
+			buf := bytes.NewBuffer([]byte{})
+			err := kube.Write(buf)
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			compareResults(t, []string{clusterID}, []string{tt.expectedCluster}, []string{tt.expectedUser}, buf.Bytes())
+
+			resultKubeconf, err := clientcmd.Load(buf.Bytes())
+			require.NoError(t, err)
+			require.Contains(t, resultKubeconf.Clusters, tt.expectedCluster)
+			assert.Equal(t, tt.expectedProxyURL, resultKubeconf.Clusters[tt.expectedCluster].ProxyURL)
+		})
+	}
+}
+
 func compareResults(t *testing.T, contexts, clusters, authInfos []string, kubeconfBytes []byte) {
 	t.Helper()
 	resultKubeconf, err := clientcmd.Load(kubeconfBytes)
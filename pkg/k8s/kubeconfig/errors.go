@@ -25,6 +25,19 @@ func (e *ErrAllSourcesFailed) Error() string {
 	return fmt.Sprintf("all kubeconfig sources failed for cluster '%s'", e.ClusterName)
 }
 
+// ErrKubeconfigOverrideFailed is returned when kubeconfig can't be taken from the override file
+// configured for the cluster in airshipctl config
+type ErrKubeconfigOverrideFailed struct {
+	ClusterName string
+	Path        string
+	Err         error
+}
+
+func (e *ErrKubeconfigOverrideFailed) Error() string {
+	return fmt.Sprintf("failed to get kubeconfig for cluster '%s' from override file '%s': %v",
+		e.ClusterName, e.Path, e.Err)
+}
+
 // ErrKubeconfigMergeFailed is returned when builder doesn't know which context to merge
 type ErrKubeconfigMergeFailed struct {
 	Message string
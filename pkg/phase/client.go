@@ -17,15 +17,14 @@ package phase
 import (
 	"bytes"
 	"io"
-	"os"
 	"path/filepath"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"sigs.k8s.io/yaml"
 
 	"opendev.org/airship/airshipctl/pkg/api/v1alpha1"
-	"opendev.org/airship/airshipctl/pkg/container"
 	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/document/policy"
+	"opendev.org/airship/airshipctl/pkg/document/validator"
 	"opendev.org/airship/airshipctl/pkg/events"
 	"opendev.org/airship/airshipctl/pkg/k8s/kubeconfig"
 	"opendev.org/airship/airshipctl/pkg/k8s/utils"
@@ -34,7 +33,6 @@ import (
 	"opendev.org/airship/airshipctl/pkg/phase/executors"
 	executorerrors "opendev.org/airship/airshipctl/pkg/phase/executors/errors"
 	"opendev.org/airship/airshipctl/pkg/phase/ifc"
-	"opendev.org/airship/airshipctl/pkg/util"
 )
 
 // ExecutorRegistry returns map with executor factories
@@ -104,6 +102,7 @@ func (p *phase) executor(docFactory document.DocFactoryFunc,
 	kubeconf := kubeconfig.NewBuilder().
 		WithBundle(p.helper.PhaseConfigBundle()).
 		WithClusterMap(cMap).
+		WithKubeconfigOverrides(p.helper.KubeconfigOverrides()).
 		WithTempRoot(p.helper.WorkDir()).
 		WithClusterName(p.apiObj.ClusterName).
 		SiteWide(p.apiObj.Config.SiteWideKubeconfig).
@@ -131,6 +130,15 @@ func (p *phase) Run(ro ifc.RunOptions) error {
 	if err != nil {
 		return err
 	}
+	if ro.EnforcePolicies {
+		docs, err := renderDocuments(executor)
+		if err != nil {
+			return err
+		}
+		if err = policy.Check(p.helper.PhaseConfigBundle(), p.apiObj.Name, docs); err != nil {
+			return err
+		}
+	}
 	ch := make(chan events.Event)
 
 	go func() {
@@ -145,51 +153,61 @@ func (p *phase) Validate() error {
 	if err != nil {
 		return err
 	}
-	return validate(executor, p.helper, p.apiObj.Config.ValidationCfg)
+	return validate(executor, p.helper, p.apiObj.Name, p.apiObj.Config.ValidationCfg)
 }
 
-func validate(executor ifc.Executor, helper ifc.Helper, validationCfg v1alpha1.ValidationConfig) error {
-	if err := executor.Validate(); err != nil {
-		return err
-	}
-
+// renderDocuments returns all documents rendered by the executor
+func renderDocuments(executor ifc.Executor) ([]document.Document, error) {
 	buf := &bytes.Buffer{}
 	if err := executor.Render(buf, ifc.RenderOptions{FilterSelector: document.NewSelector()}); err != nil {
-		return err
+		return nil, err
 	}
-
-	doc, err := helper.PhaseConfigBundle().SelectOne(document.NewValidatorExecutorSelector())
+	rendered, err := document.NewBundleFromBytes(buf.Bytes())
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return rendered.GetAllDocuments()
+}
 
-	apiObj := v1alpha1.DefaultGenericContainer()
-	err = doc.ToAPIObject(apiObj, v1alpha1.Scheme)
-	if err != nil {
+// validate checks rendered documents of the phase against their schemas and the validation policies
+func validate(executor ifc.Executor, helper ifc.Helper, phaseName string,
+	validationCfg v1alpha1.ValidationConfig) error {
+	if err := executor.Validate(); err != nil {
 		return err
 	}
 
-	valCfg, err := yaml.Marshal(validationCfg)
+	docs, err := renderDocuments(executor)
 	if err != nil {
 		return err
 	}
-	apiObj.Config = string(valCfg)
 
+	// schemas of custom resources are taken from CRDs of the phase and from additional CRD entrypoints
+	var crds []document.Document
+	for _, doc := range docs {
+		if doc.GetKind() == document.CRDKind {
+			crds = append(crds, doc)
+		}
+	}
 	for _, path := range validationCfg.CRDList {
 		bundle, err := document.NewBundleByPath(filepath.Join(helper.TargetPath(), path))
 		if err != nil {
 			return err
 		}
-		bundle, err = bundle.SelectBundle(document.NewCRDSelector())
+		additional, err := bundle.Select(document.NewCRDSelector())
 		if err != nil {
 			return err
 		}
-		if err = bundle.Write(buf); err != nil {
-			return err
-		}
+		crds = append(crds, additional...)
 	}
 
-	return container.NewClientV1Alpha1("", buf, os.Stdout, apiObj, helper.TargetPath()).Run()
+	v, err := validator.New(validationCfg, crds)
+	if err != nil {
+		return err
+	}
+	if err = v.Validate(docs); err != nil {
+		return err
+	}
+	return policy.Check(helper.PhaseConfigBundle(), phaseName, docs)
 }
 
 // Render executor documents
@@ -263,12 +281,8 @@ type plan struct {
 
 // Validate makes sure that phase plan is properly configured
 func (p *plan) Validate() error {
-	util.Setenv(util.EnvVar{Key: v1alpha1.ValidatorPreventCleanup})
-	for i, step := range p.apiObj.Phases {
+	for _, step := range p.apiObj.Phases {
 		log.Printf("validating phase: %s\n", step.Name)
-		if i == len(p.apiObj.Phases)-1 {
-			util.Unsetenv(util.EnvVar{Key: v1alpha1.ValidatorPreventCleanup})
-		}
 		phaseRunner, err := p.phaseClient.PhaseByID(ifc.ID{Name: step.Name})
 		if err != nil {
 			return err
@@ -277,7 +291,7 @@ func (p *plan) Validate() error {
 		if err != nil {
 			return err
 		}
-		if err = validate(executor, p.helper, p.apiObj.ValidationCfg); err != nil {
+		if err = validate(executor, p.helper, step.Name, p.apiObj.ValidationCfg); err != nil {
 			return err
 		}
 	}
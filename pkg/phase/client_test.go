@@ -138,6 +138,91 @@ func TestPhaseRun(t *testing.T) {
 	}
 }
 
+const (
+	insecureConfigMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: default
+data:
+  mode: insecure
+`
+	sandboxConfigMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: sandbox
+data:
+  mode: insecure
+`
+)
+
+func policyConfig(t *testing.T) *config.Config {
+	cfg := testConfig(t)
+	cfg.Manifests["dummy_manifest"].MetadataPath = "policy_site/metadata.yaml"
+	return cfg
+}
+
+func policyRegistry(render string) phase.ExecutorRegistry {
+	return func() map[schema.GroupVersionKind]ifc.ExecutorFactory {
+		gvk := schema.GroupVersionKind{
+			Group:   "airshipit.org",
+			Version: "v1alpha1",
+			Kind:    "KubernetesApply",
+		}
+		return map[schema.GroupVersionKind]ifc.ExecutorFactory{
+			gvk: func(config ifc.ExecutorConfig) (ifc.Executor, error) {
+				return fakeExecutor{render: render}, nil
+			},
+		}
+	}
+}
+
+func TestPhaseRunEnforcePolicies(t *testing.T) {
+	tests := []struct {
+		name         string
+		errContains  string
+		options      ifc.RunOptions
+		registryFunc phase.ExecutorRegistry
+	}{
+		{
+			name:         "policy violation",
+			options:      ifc.RunOptions{DryRun: true, EnforcePolicies: true},
+			registryFunc: policyRegistry(insecureConfigMap),
+			errContains: "1 policy violation(s) found:\n  ConfigMap/default/settings: " +
+				"insecure mode is not allowed (config/no-insecure-mode)",
+		},
+		{
+			name:         "no policy violation",
+			options:      ifc.RunOptions{DryRun: true, EnforcePolicies: true},
+			registryFunc: policyRegistry(sandboxConfigMap),
+		},
+		{
+			name:         "policies are not enforced",
+			options:      ifc.RunOptions{DryRun: true},
+			registryFunc: policyRegistry(insecureConfigMap),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			helper, err := phase.NewHelper(policyConfig(t))
+			require.NoError(t, err)
+			client := phase.NewClient(helper, phase.InjectRegistry(tt.registryFunc))
+			p, err := client.PhaseByID(ifc.ID{Name: "kube_apply"})
+			require.NoError(t, err)
+			err = p.Run(tt.options)
+			if tt.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestPhaseValidate(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -206,7 +291,7 @@ func TestPhaseValidate(t *testing.T) {
 			errContains: "",
 		},
 		{
-			name: "validator executor not found",
+			name: "validator executor document is not required",
 			configFunc: func(t *testing.T) *config.Config {
 				cfg := testConfig(t)
 				cfg.Manifests["dummy_manifest"].MetadataPath = "invalid_validation_site/metadata.yaml"
@@ -223,8 +308,7 @@ func TestPhaseValidate(t *testing.T) {
 					gvk: fakeExecFactory,
 				}
 			},
-			errContains: `document filtered by selector [Group="airshipit.org", Version="v1alpha1", ` +
-				`Kind="GenericContainer", Name="document-validation"] found no documents`,
+			errContains: "",
 		},
 		{
 			name:         "Error no document entry point",
@@ -260,6 +344,19 @@ func TestPhaseValidate(t *testing.T) {
 			},
 			errContains: "validation error",
 		},
+		{
+			name:         "policy violation",
+			configFunc:   policyConfig,
+			phaseID:      ifc.ID{Name: "kube_apply"},
+			registryFunc: policyRegistry(insecureConfigMap),
+			errContains:  "ConfigMap/default/settings: insecure mode is not allowed (config/no-insecure-mode)",
+		},
+		{
+			name:         "no policy violation",
+			configFunc:   policyConfig,
+			phaseID:      ifc.ID{Name: "kube_apply"},
+			registryFunc: policyRegistry(sandboxConfigMap),
+		},
 	}
 
 	for _, tt := range tests {
@@ -466,8 +563,7 @@ func TestPlanValidate(t *testing.T) {
 			configFunc:   testConfig,
 			planID:       ifc.ID{Name: "init"},
 			registryFunc: fakeRegistry,
-			errContains: `document filtered by selector [Group="airshipit.org", Version="v1alpha1", ` +
-				`Kind="GenericContainer", Name="document-validation"] found no documents`,
+			errContains:  "",
 		},
 		{
 			name:         "Invalid fake executor",
@@ -515,10 +611,12 @@ var _ ifc.Executor = fakeExecutor{}
 
 type fakeExecutor struct {
 	validate error
+	render   string
 }
 
-func (e fakeExecutor) Render(_ io.Writer, _ ifc.RenderOptions) error {
-	return nil
+func (e fakeExecutor) Render(w io.Writer, _ ifc.RenderOptions) error {
+	_, err := w.Write([]byte(e.render))
+	return err
 }
 
 func (e fakeExecutor) Run(ch chan events.Event, _ ifc.RunOptions) {
@@ -45,6 +45,7 @@ type GenericRunFlags struct {
 // RunFlags options for phase run command
 type RunFlags struct {
 	GenericRunFlags
+	EnforcePolicies bool
 }
 
 // RunCommand phase run command
@@ -0,0 +1,111 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document/diff"
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+// DiffCommand document diff command
+type DiffCommand struct {
+	Factory config.Factory
+	Writer  io.Writer
+	// From and To are git revisions of the phase repository, e.g. HEAD~1, tag or commit hash.
+	// The content of the phase repository including uncommitted changes is used if To is empty
+	From string
+	To   string
+	// Target is either the name of the phase or the path to the kustomize entrypoint relative
+	// to the root of the phase repository
+	Target       string
+	ShowSecrets  bool
+	OutputFormat string
+}
+
+// RunE renders documents of the entrypoint at both revisions and prints the differences
+func (c *DiffCommand) RunE() error {
+	if c.OutputFormat != diff.TextOutputFormat && c.OutputFormat != diff.YAMLOutputFormat &&
+		c.OutputFormat != diff.JSONOutputFormat {
+		return diff.ErrInvalidFormat{RequestedFormat: c.OutputFormat}
+	}
+	if c.From == "" {
+		return diff.ErrMissingRevision{}
+	}
+	cfg, err := c.Factory()
+	if err != nil {
+		return err
+	}
+	targetPath, err := cfg.CurrentContextTargetPath()
+	if err != nil {
+		return err
+	}
+	phaseRepoDir, err := cfg.CurrentContextPhaseRepositoryDir()
+	if err != nil {
+		return err
+	}
+	repoPath := filepath.Join(targetPath, phaseRepoDir)
+	entrypoint, err := c.entrypoint(cfg, repoPath)
+	if err != nil {
+		return err
+	}
+
+	workDir, err := ioutil.TempDir("", "airshipctl-diff-")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if rmErr := os.RemoveAll(workDir); rmErr != nil {
+			log.Printf("failed to remove temporary directory %s: %v", workDir, rmErr)
+		}
+	}()
+
+	from, err := diff.Render(repoPath, c.From, entrypoint, filepath.Join(workDir, "from"))
+	if err != nil {
+		return err
+	}
+	to, err := diff.Render(repoPath, c.To, entrypoint, filepath.Join(workDir, "to"))
+	if err != nil {
+		return err
+	}
+	changes, err := diff.Diff(from, to, diff.Options{ShowSecrets: c.ShowSecrets})
+	if err != nil {
+		return err
+	}
+	return diff.WriteChanges(c.Writer, c.OutputFormat, changes)
+}
+
+// entrypoint returns the target itself if it's a directory of the phase repository, otherwise the
+// target is considered to be the name of the phase and path to its document entrypoint is returned
+func (c *DiffCommand) entrypoint(cfg *config.Config, repoPath string) (string, error) {
+	if info, err := os.Stat(filepath.Join(repoPath, c.Target)); err == nil && info.IsDir() {
+		return c.Target, nil
+	}
+	root, err := phaseDocumentRoot(cfg, c.Target)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(repoPath, root)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", diff.ErrEntrypointOutsideRepo{Entrypoint: root, RepoPath: repoPath}
+	}
+	return rel, nil
+}
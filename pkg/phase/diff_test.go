@@ -0,0 +1,54 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document/diff"
+	"opendev.org/airship/airshipctl/pkg/phase"
+)
+
+func TestDiffCommandValidation(t *testing.T) {
+	cmd := &phase.DiffCommand{
+		Factory: func() (*config.Config, error) {
+			return nil, fmt.Errorf(testFactoryErr)
+		},
+		OutputFormat: diff.TextOutputFormat,
+	}
+	assert.Equal(t, diff.ErrMissingRevision{}, cmd.RunE())
+
+	cmd.From = "HEAD~1"
+	assert.EqualError(t, cmd.RunE(), testFactoryErr)
+
+	// document entrypoint of the phase must be located inside of the phase repository
+	cmd.Factory = func() (*config.Config, error) {
+		cfg := testConfig(t)
+		cfg.Manifests["dummy_manifest"].Repositories["primary"].URLString = "empty/filename"
+		return cfg, nil
+	}
+	cmd.Target = "capi_init"
+	assert.Equal(t, diff.ErrEntrypointOutsideRepo{
+		Entrypoint: "testdata/valid_site/phases",
+		RepoPath:   "testdata/filename",
+	}, cmd.RunE())
+
+	cmd.OutputFormat = "xml"
+	assert.Equal(t, diff.ErrInvalidFormat{RequestedFormat: "xml"}, cmd.RunE())
+}
@@ -41,6 +41,27 @@ func (e ErrUnknownRenderSource) Error() string {
 		e.Source, e.ValidSources)
 }
 
+// ErrUnknownRenderFormat returned when render command format doesn't match any known formats
+type ErrUnknownRenderFormat struct {
+	Format       string
+	ValidFormats []string
+}
+
+func (e ErrUnknownRenderFormat) Error() string {
+	return fmt.Sprintf("wrong render format '%s' specified must be one of %v",
+		e.Format, e.ValidFormats)
+}
+
+// ErrRenderOutputDirNotSpecified returned when render command format writes documents to the
+// directory and the directory is not specified
+type ErrRenderOutputDirNotSpecified struct {
+	Format string
+}
+
+func (e ErrRenderOutputDirNotSpecified) Error() string {
+	return fmt.Sprintf("must specify output directory when using %s format", e.Format)
+}
+
 // ErrRenderPhaseNameNotSpecified returned when render command is called with either phase or
 // executor source and phase name is not specified
 type ErrRenderPhaseNameNotSpecified struct {
@@ -61,6 +82,15 @@ func (e ErrInvalidFormat) Error() string {
 	return fmt.Sprintf("invalid output format specified %s. Allowed values are table|yaml", e.RequestedFormat)
 }
 
+// ErrInvalidGraphFormat is returned when unsupported format of the dependency graph is requested
+type ErrInvalidGraphFormat struct {
+	RequestedFormat string
+}
+
+func (e ErrInvalidGraphFormat) Error() string {
+	return fmt.Sprintf("invalid output format specified %s. Allowed values are table|dot|json", e.RequestedFormat)
+}
+
 // ErrInvalidPhase is returned if the phase is invalid
 type ErrInvalidPhase struct {
 	Reason string
@@ -18,7 +18,10 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	airshipv1 "opendev.org/airship/airshipctl/pkg/api/v1alpha1"
+	"opendev.org/airship/airshipctl/pkg/container"
+	"opendev.org/airship/airshipctl/pkg/document"
 	"opendev.org/airship/airshipctl/pkg/events"
+	"opendev.org/airship/airshipctl/pkg/log"
 	"opendev.org/airship/airshipctl/pkg/phase/executors/errors"
 	"opendev.org/airship/airshipctl/pkg/phase/ifc"
 )
@@ -70,3 +73,25 @@ func handleError(ch chan<- events.Event, err error) {
 		Error: err,
 	})
 }
+
+// resolveImage pins image url by digest using versions catalogue referenced from image verification policy,
+// catalogue document is looked up in the phase config bundle
+func resolveImage(image string, policy *airshipv1.ImageVerification, bundle document.Bundle) (string, error) {
+	if policy == nil || policy.VersionsCatalogueRef == nil {
+		return image, nil
+	}
+	log.Debugf("Versions catalogue reference is specified, looking for the object: '%v'", policy.VersionsCatalogueRef)
+	selector := document.NewSelector().
+		ByName(policy.VersionsCatalogueRef.Name).
+		ByNamespace(policy.VersionsCatalogueRef.Namespace).
+		ByGvk(airshipv1.GroupVersion.Group, airshipv1.GroupVersion.Version, "VersionsCatalogue")
+	doc, err := bundle.SelectOne(selector)
+	if err != nil {
+		return "", err
+	}
+	catalogue := &airshipv1.VersionsCatalogue{}
+	if err = doc.ToObject(catalogue); err != nil {
+		return "", err
+	}
+	return container.ResolveImageDigest(image, catalogue)
+}
@@ -17,6 +17,7 @@ package executors
 import (
 	"bytes"
 	goerrors "errors"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
@@ -29,6 +30,7 @@ import (
 	"opendev.org/airship/airshipctl/pkg/k8s/kubeconfig"
 	"opendev.org/airship/airshipctl/pkg/log"
 	"opendev.org/airship/airshipctl/pkg/phase/errors"
+	executorerrors "opendev.org/airship/airshipctl/pkg/phase/executors/errors"
 	"opendev.org/airship/airshipctl/pkg/phase/ifc"
 )
 
@@ -116,6 +118,11 @@ func (c *ContainerExecutor) Run(evtCh chan events.Event, opts ifc.RunOptions) {
 		return
 	}
 
+	if err = c.setImage(); err != nil {
+		handleError(evtCh, err)
+		return
+	}
+
 	// TODO check the executor type  when dryrun is set
 	if opts.DryRun {
 		evtCh <- events.NewEvent().WithGenericContainerEvent(events.GenericContainerEvent{
@@ -125,7 +132,11 @@ func (c *ContainerExecutor) Run(evtCh chan events.Event, opts ifc.RunOptions) {
 		return
 	}
 
-	err = c.ClientFunc(c.ResultsDir, input, output, c.Container, c.MountBasePath).Run()
+	if len(c.Container.Spec.Steps) > 0 {
+		err = c.runSteps(evtCh, input, output)
+	} else {
+		err = c.ClientFunc(c.ResultsDir, input, output, c.Container, c.MountBasePath).Run()
+	}
 	if err != nil {
 		handleError(evtCh, err)
 		return
@@ -137,6 +148,53 @@ func (c *ContainerExecutor) Run(evtCh chan events.Event, opts ifc.RunOptions) {
 	})
 }
 
+// runSteps executes generic container steps one by one, output bundle of each step is passed
+// as input to the next one and the output of the last step is written to the executor output
+func (c *ContainerExecutor) runSteps(evtCh chan events.Event, input io.Reader, output io.Writer) error {
+	for i, step := range c.Container.Spec.Steps {
+		name := step.Name
+		if name == "" {
+			name = fmt.Sprintf("step-%d", i)
+		}
+		evtCh <- events.NewEvent().WithGenericContainerEvent(events.GenericContainerEvent{
+			Operation: events.GenericContainerStepStart,
+			Message:   fmt.Sprintf("starting generic container step '%s'", name),
+		})
+
+		resultsDir, stepOutput := "", &bytes.Buffer{}
+		var out io.Writer = stepOutput
+		if i == len(c.Container.Spec.Steps)-1 {
+			resultsDir, out = c.ResultsDir, output
+		}
+
+		err := c.ClientFunc(resultsDir, input, out, c.stepContainer(step), c.MountBasePath).Run()
+		if err != nil {
+			return executorerrors.ErrContainerStep{Step: name, Err: err}
+		}
+		input = stepOutput
+
+		evtCh <- events.NewEvent().WithGenericContainerEvent(events.GenericContainerEvent{
+			Operation: events.GenericContainerStepStop,
+			Message:   fmt.Sprintf("generic container step '%s' finished", name),
+		})
+	}
+	return nil
+}
+
+// stepContainer returns generic container configuration for a particular step
+func (c *ContainerExecutor) stepContainer(step v1alpha1.ContainerStep) *v1alpha1.GenericContainer {
+	conf := c.Container.DeepCopy()
+	conf.Spec.Steps = nil
+	if step.Image != "" {
+		conf.Spec.Image = step.Image
+	}
+	if len(step.Cmd) > 0 {
+		conf.Spec.Airship.Cmd = step.Cmd
+	}
+	conf.Spec.EnvVars = append(conf.Spec.EnvVars, step.EnvVars...)
+	return conf
+}
+
 // SetKubeConfig adds env variable and mounts kubeconfig to container
 func (c *ContainerExecutor) SetKubeConfig() (kubeconfig.Cleanup, error) {
 	context, err := c.Options.ClusterMap.ClusterKubeconfigContext(c.Options.ClusterName)
@@ -201,6 +259,24 @@ func (c *ContainerExecutor) setConfig() error {
 	return nil
 }
 
+func (c *ContainerExecutor) setImage() error {
+	policy := c.Container.Spec.Airship.ImageVerification
+	image, err := resolveImage(c.Container.Spec.Image, policy, c.Options.PhaseConfigBundle)
+	if err != nil {
+		return err
+	}
+	c.Container.Spec.Image = image
+	for i, step := range c.Container.Spec.Steps {
+		if step.Image == "" {
+			continue
+		}
+		if c.Container.Spec.Steps[i].Image, err = resolveImage(step.Image, policy, c.Options.PhaseConfigBundle); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Status returns the status of the given phase
 func (c *ContainerExecutor) Status() (ifc.ExecutorStatus, error) {
 	return ifc.ExecutorStatus{}, commonerrors.ErrNotImplemented{What: GenericContainer}
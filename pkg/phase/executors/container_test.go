@@ -16,6 +16,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -243,6 +244,106 @@ type: Opaque
 	}
 }
 
+func TestGenericContainerSteps(t *testing.T) {
+	tests := []struct {
+		name           string
+		failStep       string
+		expectedErr    string
+		expectedImages []string
+		expectedInputs []string
+		expectedOps    []events.GenericContainerOperation
+	}{
+		{
+			name:           "success all steps",
+			expectedImages: []string{"step-image-1", "base-image"},
+			expectedInputs: []string{"", "step-image-1|"},
+			expectedOps: []events.GenericContainerOperation{
+				events.GenericContainerStart,
+				events.GenericContainerStepStart,
+				events.GenericContainerStepStop,
+				events.GenericContainerStepStart,
+				events.GenericContainerStepStop,
+				events.GenericContainerStop,
+			},
+		},
+		{
+			name:           "first step fails",
+			failStep:       "step-image-1",
+			expectedErr:    "generic container step 'first' failed: step error",
+			expectedImages: []string{"step-image-1"},
+			expectedInputs: []string{""},
+			expectedOps: []events.GenericContainerOperation{
+				events.GenericContainerStart,
+				events.GenericContainerStepStart,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			bundle, err := document.NewBundleFromBytes([]byte{})
+			require.NoError(t, err)
+
+			var images, inputs []string
+			clientFunc := func(_ string, input io.Reader, output io.Writer,
+				conf *v1alpha1.GenericContainer, _ string) container.ClientV1Alpha1 {
+				return MockClientFuncInterface{MockRun: func() error {
+					data, err := ioutil.ReadAll(input)
+					require.NoError(t, err)
+					images = append(images, conf.Spec.Image)
+					inputs = append(inputs, string(data))
+					assert.Nil(t, conf.Spec.Steps)
+					if conf.Spec.Image == tt.failStep {
+						return fmt.Errorf("step error")
+					}
+					if output != nil {
+						_, err = output.Write([]byte(conf.Spec.Image + "|"))
+					}
+					return err
+				}}
+			}
+
+			e := executors.ContainerExecutor{
+				ResultsDir:     "results",
+				ExecutorBundle: bundle,
+				ClientFunc:     clientFunc,
+				Container: &v1alpha1.GenericContainer{
+					Spec: v1alpha1.GenericContainerSpec{
+						Image: "base-image",
+						Steps: []v1alpha1.ContainerStep{
+							{Name: "first", Image: "step-image-1"},
+							{Name: "second", Cmd: []string{"/bin/true"}},
+						},
+					},
+				},
+			}
+
+			ch := make(chan events.Event)
+			go e.Run(ch, ifc.RunOptions{})
+
+			ops := []events.GenericContainerOperation{}
+			var lastErr error
+			for evt := range ch {
+				if evt.Type == events.ErrorType {
+					lastErr = evt.ErrorEvent.Error
+					continue
+				}
+				ops = append(ops, evt.GenericContainerEvent.Operation)
+			}
+			if tt.expectedErr != "" {
+				require.Error(t, lastErr)
+				assert.Equal(t, tt.expectedErr, lastErr.Error())
+			} else {
+				assert.NoError(t, lastErr)
+			}
+			assert.Equal(t, tt.expectedOps, ops)
+			assert.Equal(t, tt.expectedImages, images)
+			assert.Equal(t, tt.expectedInputs, inputs)
+		})
+	}
+}
+
 func TestSetKubeConfig(t *testing.T) {
 	getFileErr := fmt.Errorf("failed to get file")
 	testCases := []struct {
@@ -38,6 +38,7 @@ type EphemeralExecutor struct {
 
 	BootConf  *v1alpha1.BootConfiguration
 	Container container.Container
+	Options   ifc.ExecutorConfig
 }
 
 // NewEphemeralExecutor creates instance of phase executor
@@ -52,6 +53,7 @@ func NewEphemeralExecutor(cfg ifc.ExecutorConfig) (ifc.Executor, error) {
 	return &EphemeralExecutor{
 		ExecutorDocument: cfg.ExecutorDocument,
 		BootConf:         apiObj,
+		Options:          cfg,
 	}, nil
 }
 
@@ -73,11 +75,16 @@ func (c *EphemeralExecutor) Run(evtCh chan events.Event, opts ifc.RunOptions) {
 	}
 
 	if c.Container == nil {
+		image, err := c.verifiedImage()
+		if err != nil {
+			handleError(evtCh, err)
+			return
+		}
 		ctx := context.Background()
 		builder, err := container.NewContainer(
 			ctx,
 			c.BootConf.BootstrapContainer.ContainerRuntime,
-			c.BootConf.BootstrapContainer.Image)
+			image)
 		if err != nil {
 			handleError(evtCh, err)
 			return
@@ -130,6 +137,16 @@ func (c *EphemeralExecutor) Run(evtCh chan events.Event, opts ifc.RunOptions) {
 	})
 }
 
+// verifiedImage returns bootstrap container image pinned and verified according to image verification policy
+func (c *EphemeralExecutor) verifiedImage() (string, error) {
+	policy := c.BootConf.BootstrapContainer.ImageVerification
+	image, err := resolveImage(c.BootConf.BootstrapContainer.Image, policy, c.Options.PhaseConfigBundle)
+	if err != nil {
+		return "", err
+	}
+	return image, container.VerifyImage(image, policy)
+}
+
 // Validate executor configuration and documents
 func (c *EphemeralExecutor) Validate() error {
 	return errors.ErrNotImplemented{}
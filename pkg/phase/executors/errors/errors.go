@@ -91,3 +91,13 @@ type ErrExecutorRegistration struct {
 func (e ErrExecutorRegistration) Error() string {
 	return fmt.Sprintf("failed to register executor %s, registration function returned %s", e.ExecutorName, e.Err.Error())
 }
+
+// ErrContainerStep is returned when one of the generic container steps failed
+type ErrContainerStep struct {
+	Step string
+	Err  error
+}
+
+func (e ErrContainerStep) Error() string {
+	return fmt.Sprintf("generic container step '%s' failed: %s", e.Step, e.Err.Error())
+}
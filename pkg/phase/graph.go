@@ -0,0 +1,240 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"opendev.org/airship/airshipctl/pkg/api/v1alpha1"
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/fs"
+	phaseerrors "opendev.org/airship/airshipctl/pkg/phase/errors"
+	"opendev.org/airship/airshipctl/pkg/phase/ifc"
+	"opendev.org/airship/airshipctl/pkg/util"
+)
+
+const (
+	// DotOutputFormat graphviz dot
+	DotOutputFormat = "dot"
+	// JSONOutputFormat json
+	JSONOutputFormat = "json"
+)
+
+// GraphFlags options for phase graph command
+type GraphFlags struct {
+	FormatType string
+	// AffectedBy limits the graph to the phases which depend on the path, the path is either
+	// absolute or relative to the root of the phase repository
+	AffectedBy string
+}
+
+// GraphCommand phase graph command
+type GraphCommand struct {
+	Options GraphFlags
+	Factory config.Factory
+	Writer  io.Writer
+}
+
+// GraphPhase is a phase of the dependency graph
+type GraphPhase struct {
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+	Entrypoint string `json:"entrypoint"`
+}
+
+// GraphNode is a kustomization directory or a file of the dependency graph along with the phases
+// which use it directly or indirectly
+type GraphNode struct {
+	Path          string   `json:"path"`
+	Kustomization bool     `json:"kustomization"`
+	Phases        []string `json:"phases,omitempty"`
+}
+
+// DependencyGraph is a graph of the phases and kustomize entrypoints they use. Paths of the
+// nodes are relative to the root of the phase repository
+type DependencyGraph struct {
+	Phases []GraphPhase          `json:"phases"`
+	Nodes  []GraphNode           `json:"nodes"`
+	Edges  []document.KustomEdge `json:"edges"`
+}
+
+// RunE runs the phase graph command
+func (c *GraphCommand) RunE() error {
+	if c.Options.FormatType != TableOutputFormat && c.Options.FormatType != DotOutputFormat &&
+		c.Options.FormatType != JSONOutputFormat {
+		return phaseerrors.ErrInvalidGraphFormat{RequestedFormat: c.Options.FormatType}
+	}
+	cfg, err := c.Factory()
+	if err != nil {
+		return err
+	}
+
+	helper, err := NewHelper(cfg)
+	if err != nil {
+		return err
+	}
+
+	graph, err := BuildDependencyGraph(helper, c.Options.AffectedBy)
+	if err != nil {
+		return err
+	}
+	switch c.Options.FormatType {
+	case DotOutputFormat:
+		return graph.WriteDot(c.Writer)
+	case JSONOutputFormat:
+		enc := json.NewEncoder(c.Writer)
+		enc.SetIndent("", "  ")
+		return enc.Encode(graph)
+	default:
+		return graph.WriteTable(c.Writer)
+	}
+}
+
+// BuildDependencyGraph builds dependency graph of document entrypoints of all the phases. If
+// affectedBy path is not empty the graph contains only the phases and the nodes depending on it
+func BuildDependencyGraph(helper ifc.Helper, affectedBy string) (*DependencyGraph, error) {
+	phases, err := helper.ListPhases(ifc.ListPhaseOptions{})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(phases, func(i, j int) bool {
+		return phaseName(phases[i]) < phaseName(phases[j])
+	})
+
+	kustomGraph := document.NewKustomGraph(fs.NewDocumentFs())
+	entrypoints := map[string]string{}
+	for _, p := range phases {
+		if p.Config.DocumentEntryPoint == "" {
+			continue
+		}
+		entrypoint := filepath.Clean(filepath.Join(helper.PhaseEntryPointBasePath(), p.Config.DocumentEntryPoint))
+		if err = kustomGraph.Add(entrypoint); err != nil {
+			return nil, err
+		}
+		entrypoints[phaseName(p)] = entrypoint
+	}
+
+	root := filepath.Join(helper.TargetPath(), helper.PhaseRepoDir())
+	inScope := func(string) bool { return true }
+	if affectedBy != "" {
+		if !filepath.IsAbs(affectedBy) {
+			affectedBy = filepath.Join(root, affectedBy)
+		}
+		dependents := map[string]bool{}
+		for _, node := range kustomGraph.Dependents(affectedBy) {
+			dependents[node] = true
+		}
+		inScope = func(node string) bool { return dependents[node] }
+	}
+	rel := func(path string) string {
+		if relPath, relErr := filepath.Rel(root, path); relErr == nil {
+			return relPath
+		}
+		return path
+	}
+
+	graph := &DependencyGraph{
+		Phases: []GraphPhase{},
+		Nodes:  []GraphNode{},
+		Edges:  []document.KustomEdge{},
+	}
+	usedBy := map[string][]string{}
+	for _, p := range phases {
+		name := phaseName(p)
+		entrypoint, ok := entrypoints[name]
+		if !ok || !inScope(entrypoint) {
+			continue
+		}
+		graph.Phases = append(graph.Phases, GraphPhase{
+			Name:       p.Name,
+			Namespace:  p.Namespace,
+			Entrypoint: rel(entrypoint),
+		})
+		for _, node := range kustomGraph.Dependencies(entrypoint) {
+			usedBy[node] = append(usedBy[node], name)
+		}
+	}
+	for _, node := range kustomGraph.Nodes() {
+		if !inScope(node) {
+			continue
+		}
+		graph.Nodes = append(graph.Nodes, GraphNode{
+			Path:          rel(node),
+			Kustomization: kustomGraph.IsKustomization(node),
+			Phases:        usedBy[node],
+		})
+	}
+	for _, edge := range kustomGraph.Edges() {
+		if !inScope(edge.From) || !inScope(edge.To) {
+			continue
+		}
+		graph.Edges = append(graph.Edges, document.KustomEdge{From: rel(edge.From), To: rel(edge.To), Type: edge.Type})
+	}
+	return graph, nil
+}
+
+// WriteTable prints nodes of the graph along with the phases which use them
+func (g *DependencyGraph) WriteTable(w io.Writer) error {
+	tw := util.NewTabWriter(w)
+	fmt.Fprintln(tw, "PATH\tPHASES")
+	for _, node := range g.Nodes {
+		fmt.Fprintf(tw, "%s\t%s\n", node.Path, strings.Join(node.Phases, ","))
+	}
+	return tw.Flush()
+}
+
+// WriteDot prints the graph in graphviz dot format, phases are drawn as boxes, kustomization
+// directories as folders and other sources as notes
+func (g *DependencyGraph) WriteDot(w io.Writer) error {
+	lines := []string{"digraph phases {", "  rankdir=LR;"}
+	for _, p := range g.Phases {
+		name := p.Name
+		if p.Namespace != "" {
+			name = p.Namespace + "/" + name
+		}
+		id := strconv.Quote("phase:" + name)
+		lines = append(lines,
+			fmt.Sprintf("  %s [shape=box, label=%s];", id, strconv.Quote(name)),
+			fmt.Sprintf("  %s -> %s;", id, strconv.Quote(p.Entrypoint)))
+	}
+	for _, node := range g.Nodes {
+		shape := "note"
+		if node.Kustomization {
+			shape = "folder"
+		}
+		lines = append(lines, fmt.Sprintf("  %s [shape=%s];", strconv.Quote(node.Path), shape))
+	}
+	for _, edge := range g.Edges {
+		lines = append(lines, fmt.Sprintf("  %s -> %s [label=%s];",
+			strconv.Quote(edge.From), strconv.Quote(edge.To), strconv.Quote(edge.Type)))
+	}
+	lines = append(lines, "}")
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
+
+func phaseName(p *v1alpha1.Phase) string {
+	if p.Namespace == "" {
+		return p.Name
+	}
+	return p.Namespace + "/" + p.Name
+}
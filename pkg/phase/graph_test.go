@@ -0,0 +1,149 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/phase"
+	phaseerrors "opendev.org/airship/airshipctl/pkg/phase/errors"
+)
+
+func graphConfig(t *testing.T) *config.Config {
+	t.Helper()
+	conf := testConfig(t)
+	conf.Manifests["dummy_manifest"].MetadataPath = "graph_site/metadata.yaml"
+	return conf
+}
+
+func TestBuildDependencyGraph(t *testing.T) {
+	helper, err := phase.NewHelper(graphConfig(t))
+	require.NoError(t, err)
+
+	graph, err := phase.BuildDependencyGraph(helper, "")
+	require.NoError(t, err)
+	assert.Equal(t, &phase.DependencyGraph{
+		Phases: []phase.GraphPhase{
+			{Name: "controlplane", Entrypoint: "graph_site/site/controlplane"},
+			{Name: "initinfra", Entrypoint: "graph_site/site/initinfra"},
+		},
+		Nodes: []phase.GraphNode{
+			{Path: "graph_site/function/capi/v1", Kustomization: true, Phases: []string{"initinfra"}},
+			{Path: "graph_site/function/capi/v1/provider.yaml", Phases: []string{"initinfra"}},
+			{Path: "graph_site/function/labels", Kustomization: true, Phases: []string{"controlplane"}},
+			{Path: "graph_site/function/shared", Kustomization: true, Phases: []string{"controlplane", "initinfra"}},
+			{Path: "graph_site/function/shared/configmap.yaml", Phases: []string{"controlplane", "initinfra"}},
+			{Path: "graph_site/site/controlplane", Kustomization: true, Phases: []string{"controlplane"}},
+			{Path: "graph_site/site/initinfra", Kustomization: true, Phases: []string{"initinfra"}},
+			{Path: "graph_site/site/initinfra/namespace.yaml", Phases: []string{"initinfra"}},
+		},
+		Edges: []document.KustomEdge{
+			{From: "graph_site/function/capi/v1", To: "graph_site/function/capi/v1/provider.yaml", Type: "Resources"},
+			{From: "graph_site/function/shared", To: "graph_site/function/shared/configmap.yaml", Type: "Resources"},
+			{From: "graph_site/site/controlplane", To: "graph_site/function/shared", Type: "Bases"},
+			{From: "graph_site/site/controlplane", To: "graph_site/function/labels", Type: "Components"},
+			{From: "graph_site/site/initinfra", To: "graph_site/function/shared", Type: "Resources"},
+			{From: "graph_site/site/initinfra", To: "graph_site/function/capi/v1", Type: "Resources"},
+			{From: "graph_site/site/initinfra", To: "graph_site/site/initinfra/namespace.yaml", Type: "Resources"},
+		},
+	}, graph)
+
+	graph, err = phase.BuildDependencyGraph(helper, "graph_site/function/capi")
+	require.NoError(t, err)
+	assert.Equal(t, &phase.DependencyGraph{
+		Phases: []phase.GraphPhase{
+			{Name: "initinfra", Entrypoint: "graph_site/site/initinfra"},
+		},
+		Nodes: []phase.GraphNode{
+			{Path: "graph_site/function/capi/v1", Kustomization: true, Phases: []string{"initinfra"}},
+			{Path: "graph_site/function/capi/v1/provider.yaml", Phases: []string{"initinfra"}},
+			{Path: "graph_site/site/initinfra", Kustomization: true, Phases: []string{"initinfra"}},
+		},
+		Edges: []document.KustomEdge{
+			{From: "graph_site/function/capi/v1", To: "graph_site/function/capi/v1/provider.yaml", Type: "Resources"},
+			{From: "graph_site/site/initinfra", To: "graph_site/function/capi/v1", Type: "Resources"},
+		},
+	}, graph)
+}
+
+func TestGraphCommand(t *testing.T) {
+	testCases := []struct {
+		name        string
+		options     phase.GraphFlags
+		expectedOut string
+		expectedErr error
+	}{
+		{
+			name:    "table of affected phases",
+			options: phase.GraphFlags{FormatType: phase.TableOutputFormat, AffectedBy: "graph_site/function/labels"},
+			expectedOut: "PATH                           PHASES\n" +
+				"graph_site/function/labels     controlplane\n" +
+				"graph_site/site/controlplane   controlplane\n",
+		},
+		{
+			name:    "dot",
+			options: phase.GraphFlags{FormatType: phase.DotOutputFormat, AffectedBy: "graph_site/function/labels"},
+			expectedOut: `digraph phases {
+  rankdir=LR;
+  "phase:controlplane" [shape=box, label="controlplane"];
+  "phase:controlplane" -> "graph_site/site/controlplane";
+  "graph_site/function/labels" [shape=folder];
+  "graph_site/site/controlplane" [shape=folder];
+  "graph_site/site/controlplane" -> "graph_site/function/labels" [label="Components"];
+}
+`,
+		},
+		{
+			name:    "json without affected phases",
+			options: phase.GraphFlags{FormatType: phase.JSONOutputFormat, AffectedBy: "graph_site/unknown"},
+			expectedOut: `{
+  "phases": [],
+  "nodes": [],
+  "edges": []
+}
+`,
+		},
+		{
+			name:        "invalid format",
+			options:     phase.GraphFlags{FormatType: "yaml"},
+			expectedErr: phaseerrors.ErrInvalidGraphFormat{RequestedFormat: "yaml"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			cmd := phase.GraphCommand{
+				Options: tc.options,
+				Factory: func() (*config.Config, error) { return graphConfig(t), nil },
+				Writer:  buf,
+			}
+			err := cmd.RunE()
+			if tc.expectedErr != nil {
+				assert.Equal(t, tc.expectedErr, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedOut, buf.String())
+		})
+	}
+}
@@ -43,8 +43,9 @@ type Helper struct {
 	workDir                 string
 	docEntryPointPrefix     string
 
-	inventory         inventoryifc.Inventory
-	phaseConfigBundle document.Bundle
+	inventory           inventoryifc.Inventory
+	phaseConfigBundle   document.Bundle
+	kubeconfigOverrides map[string]*config.KubeconfigOverride
 }
 
 // NewHelper constructs metadata interface based on config
@@ -77,6 +78,10 @@ func NewHelper(cfg *config.Config) (ifc.Helper, error) {
 	if err != nil {
 		return nil, err
 	}
+	helper.kubeconfigOverrides, err = cfg.CurrentContextKubeconfigOverrides()
+	if err != nil {
+		return nil, err
+	}
 	helper.docEntryPointPrefix = meta.DocEntryPointPrefix
 	helper.phaseBundleRoot = filepath.Join(helper.targetPath, helper.phaseRepoDir, meta.MetadataPhasePath)
 	helper.inventoryRoot = filepath.Join(helper.targetPath, helper.phaseRepoDir, meta.InventoryPath)
@@ -328,3 +333,8 @@ func (helper *Helper) Inventory() inventoryifc.Inventory {
 func (helper *Helper) PhaseConfigBundle() document.Bundle {
 	return helper.phaseConfigBundle
 }
+
+// KubeconfigOverrides returns kubeconfig overrides of the clusters defined in the current context
+func (helper *Helper) KubeconfigOverrides() map[string]*config.KubeconfigOverride {
+	return helper.kubeconfigOverrides
+}
@@ -41,6 +41,8 @@ type ExecutorStatus struct{}
 type RunOptions struct {
 	DryRun  bool
 	Timeout *time.Duration
+	// EnforcePolicies checks rendered documents against validation policies before the run
+	EnforcePolicies bool
 }
 
 // RenderOptions holds options for render method
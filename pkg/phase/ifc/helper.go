@@ -17,6 +17,7 @@ package ifc
 import (
 	"opendev.org/airship/airshipctl/pkg/api/v1alpha1"
 	"opendev.org/airship/airshipctl/pkg/cluster/clustermap"
+	"opendev.org/airship/airshipctl/pkg/config"
 	"opendev.org/airship/airshipctl/pkg/document"
 	"opendev.org/airship/airshipctl/pkg/inventory/ifc"
 )
@@ -38,4 +39,5 @@ type Helper interface {
 	Inventory() ifc.Inventory
 	PhaseEntryPointBasePath() string
 	PhaseConfigBundle() document.Bundle
+	KubeconfigOverrides() map[string]*config.KubeconfigOverride
 }
@@ -0,0 +1,110 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase
+
+import (
+	"io"
+
+	"opendev.org/airship/airshipctl/pkg/api/v1alpha1"
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/document/lint"
+	"opendev.org/airship/airshipctl/pkg/fs"
+	"opendev.org/airship/airshipctl/pkg/phase/ifc"
+)
+
+// LintCommand document lint command
+type LintCommand struct {
+	Factory      config.Factory
+	Writer       io.Writer
+	PhaseID      ifc.ID
+	Path         string
+	ConfigName   string
+	OutputFormat string
+}
+
+// RunE lints documents of the phase entrypoint or kustomize path. LintConfig document of the
+// phase bundle is used to configure the rules, all rules with default settings are used if
+// there is no such document
+func (c *LintCommand) RunE() error {
+	if c.OutputFormat != lint.TableOutputFormat && c.OutputFormat != lint.JSONOutputFormat &&
+		c.OutputFormat != lint.SARIFOutputFormat {
+		return lint.ErrInvalidFormat{RequestedFormat: c.OutputFormat}
+	}
+	cfg, err := c.Factory()
+	if err != nil {
+		return err
+	}
+	helper, err := NewHelper(cfg)
+	if err != nil {
+		return err
+	}
+
+	root := c.Path
+	if root == "" {
+		p, phaseErr := NewClient(helper).PhaseByID(c.PhaseID)
+		if phaseErr != nil {
+			return phaseErr
+		}
+		if root, err = p.DocumentRoot(); err != nil {
+			return err
+		}
+	}
+
+	lintCfg, err := lintConfig(helper.PhaseConfigBundle(), c.ConfigName)
+	if err != nil {
+		return err
+	}
+	findings, lintErr := lint.Lint(lint.NewTarget(fs.NewDocumentFs(), root), &lintCfg.Spec)
+	// findings of the rules checking unrendered documents are reported even if rendering fails
+	if lintErr != nil && len(findings) == 0 {
+		return lintErr
+	}
+	if err = lint.WriteFindings(c.Writer, c.OutputFormat, findings); err != nil {
+		return err
+	}
+	if lintErr != nil {
+		return lintErr
+	}
+
+	errCount := 0
+	for _, f := range findings {
+		if f.Severity == lint.SeverityError {
+			errCount++
+		}
+	}
+	if errCount > 0 {
+		return lint.ErrLintFailed{Errors: errCount}
+	}
+	return nil
+}
+
+func lintConfig(bundle document.Bundle, name string) (*v1alpha1.LintConfig, error) {
+	lintCfg := &v1alpha1.LintConfig{}
+	selector := document.NewSelector().ByGvk(v1alpha1.GroupVersion.Group, v1alpha1.GroupVersion.Version, "LintConfig")
+	if name != "" {
+		selector = selector.ByName(name)
+	} else {
+		docs, err := bundle.Select(selector)
+		if err != nil || len(docs) == 0 {
+			return lintCfg, err
+		}
+	}
+	doc, err := bundle.SelectOne(selector)
+	if err != nil {
+		return nil, err
+	}
+	return lintCfg, doc.ToAPIObject(lintCfg, v1alpha1.Scheme)
+}
@@ -0,0 +1,88 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase
+
+import (
+	"io"
+	"os"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/document/query"
+	"opendev.org/airship/airshipctl/pkg/phase/ifc"
+)
+
+// QueryCommand document query command
+type QueryCommand struct {
+	Factory config.Factory
+	Writer  io.Writer
+	// Target is either the name of the phase or the path to the kustomize entrypoint
+	Target string
+	query.Filter
+	// Field is JSON path query, e.g. {.spec.containers[*].image}, or dot separated path of the field
+	Field        string
+	OutputFormat string
+}
+
+// RunE renders documents of the phase or kustomize entrypoint and prints the ones matching the selector
+func (c *QueryCommand) RunE() error {
+	if c.OutputFormat != query.TableOutputFormat && c.OutputFormat != query.YAMLOutputFormat &&
+		c.OutputFormat != query.JSONOutputFormat {
+		return query.ErrInvalidFormat{RequestedFormat: c.OutputFormat}
+	}
+	selector, err := c.Selector()
+	if err != nil {
+		return err
+	}
+	root, err := c.documentRoot()
+	if err != nil {
+		return err
+	}
+	bundle, err := document.NewBundleByPath(root)
+	if err != nil {
+		return err
+	}
+	results, err := query.Query(bundle, selector, c.Field)
+	if err != nil {
+		return err
+	}
+	return query.WriteResults(c.Writer, c.OutputFormat, results, c.Field != "")
+}
+
+// documentRoot returns the target itself if it's an existing directory, otherwise the target is
+// considered to be the name of the phase
+func (c *QueryCommand) documentRoot() (string, error) {
+	if info, err := os.Stat(c.Target); err == nil && info.IsDir() {
+		return c.Target, nil
+	}
+	cfg, err := c.Factory()
+	if err != nil {
+		return "", err
+	}
+	return phaseDocumentRoot(cfg, c.Target)
+}
+
+// phaseDocumentRoot returns path to the document entrypoint of the phase
+func phaseDocumentRoot(cfg *config.Config, name string) (string, error) {
+	helper, err := NewHelper(cfg)
+	if err != nil {
+		return "", err
+	}
+	p, err := NewClient(helper).PhaseByID(ifc.ID{Name: name})
+	if err != nil {
+		return "", err
+	}
+	return p.DocumentRoot()
+}
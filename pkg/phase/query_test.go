@@ -0,0 +1,62 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package phase_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document/query"
+	"opendev.org/airship/airshipctl/pkg/phase"
+)
+
+func TestQueryCommand(t *testing.T) {
+	buf := &bytes.Buffer{}
+	cmd := &phase.QueryCommand{
+		Factory: func() (*config.Config, error) {
+			return testConfig(t), nil
+		},
+		Writer: buf,
+		Target: "capi_init",
+		Filter: query.Filter{
+			Kind:       "Phase",
+			APIVersion: "airshipit.org/v1alpha1",
+			Name:       "capi_init",
+		},
+		Field:        "config.documentEntryPoint",
+		OutputFormat: query.TableOutputFormat,
+	}
+	require.NoError(t, cmd.RunE())
+	assert.Contains(t, buf.String(), "valid_site/phases")
+
+	// existing directory is used as kustomize entrypoint
+	buf.Reset()
+	cmd.Target = "testdata/valid_site/phases"
+	require.NoError(t, cmd.RunE())
+	assert.Contains(t, buf.String(), "valid_site/phases")
+
+	cmd.Target = "no_such_phase"
+	assert.Error(t, cmd.RunE())
+
+	cmd.APIVersion = "apps/v1/extra"
+	assert.Equal(t, query.ErrInvalidAPIVersion{APIVersion: "apps/v1/extra"}, cmd.RunE())
+
+	cmd.OutputFormat = "xml"
+	assert.Equal(t, query.ErrInvalidFormat{RequestedFormat: "xml"}, cmd.RunE())
+}
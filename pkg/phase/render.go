@@ -15,12 +15,14 @@
 package phase
 
 import (
+	"bytes"
 	"io"
 	"os"
 	"strings"
 
 	"opendev.org/airship/airshipctl/pkg/config"
 	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/fs"
 	"opendev.org/airship/airshipctl/pkg/phase/errors"
 	"opendev.org/airship/airshipctl/pkg/phase/ifc"
 )
@@ -35,6 +37,21 @@ const (
 
 	// RenderSourcePhase the source will use kustomize root at phase entry point
 	RenderSourcePhase = "phase"
+
+	// RenderFormatYAML writes rendered documents as a multi-document YAML stream
+	RenderFormatYAML = "yaml"
+
+	// RenderFormatDirectory writes each rendered document to a separate file of the output directory
+	RenderFormatDirectory = "directory"
+
+	// RenderFormatKustomization writes rendered documents to the output directory along with kustomization
+	RenderFormatKustomization = "kustomization"
+
+	// RenderFormatTar writes gzipped tarball of the kustomization directory with rendered documents
+	RenderFormatTar = "tar"
+
+	// RenderFormatOCI writes OCI image layout with the tarball of rendered documents to the output directory
+	RenderFormatOCI = "oci"
 )
 
 // RenderCommand holds filters for selector
@@ -55,7 +72,15 @@ type RenderCommand struct {
 	// FailOnDecryptionError makes sure that encrypted documents are getting decrypted by avoiding setting
 	// env variable TOLERATE_DECRYPTION_FAILURES=true
 	FailOnDecryptionError bool
-	PhaseID               ifc.ID
+	// Trace annotates fields modified by replacement transformers and documents generated by templaters
+	// with the source documents and the name of the function config, only built-in functions are traced
+	Trace bool
+	// Format is the format of rendered documents, these can be [yaml|directory|kustomization|tar|oci]
+	Format string
+	// OutputDir is the directory rendered documents are written to if the format is directory,
+	// kustomization or oci
+	OutputDir string
+	PhaseID   ifc.ID
 }
 
 // RunE prints out filtered documents
@@ -67,6 +92,10 @@ func (fo *RenderCommand) RunE(cfgFactory config.Factory, out io.Writer) error {
 	if !fo.FailOnDecryptionError {
 		os.Setenv("TOLERATE_DECRYPTION_FAILURES", "true")
 	}
+	if fo.Trace {
+		document.SetBuiltinTrace(true)
+		defer document.SetBuiltinTrace(false)
+	}
 
 	cfg, err := cfgFactory()
 	if err != nil {
@@ -86,6 +115,21 @@ func (fo *RenderCommand) RunE(cfgFactory config.Factory, out io.Writer) error {
 	}
 	sel := document.NewSelector().ByLabel(fo.Label).ByAnnotation(fo.Annotation).ByGvk(group, version, fo.Kind)
 
+	if fo.Format == "" || fo.Format == RenderFormatYAML {
+		return fo.render(out, helper, sel)
+	}
+	buf := &bytes.Buffer{}
+	if err = fo.render(buf, helper, sel); err != nil {
+		return err
+	}
+	bundle, err := document.NewBundleFromBytes(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	return fo.writeBundle(out, bundle)
+}
+
+func (fo *RenderCommand) render(out io.Writer, helper ifc.Helper, sel document.Selector) error {
 	if fo.Source == RenderSourceConfig {
 		return renderConfigBundle(out, helper, sel)
 	}
@@ -103,6 +147,25 @@ func (fo *RenderCommand) RunE(cfgFactory config.Factory, out io.Writer) error {
 	return phase.Render(out, executorRender, ifc.RenderOptions{FilterSelector: sel})
 }
 
+func (fo *RenderCommand) writeBundle(out io.Writer, bundle document.Bundle) error {
+	fSys := fs.NewDocumentFs()
+	switch fo.Format {
+	case RenderFormatDirectory:
+		_, err := document.WriteBundleFiles(fSys, bundle, fo.OutputDir)
+		return err
+	case RenderFormatKustomization:
+		return document.WriteBundleKustomization(fSys, bundle, fo.OutputDir)
+	case RenderFormatOCI:
+		refName := fo.PhaseID.Name
+		if fo.Source == RenderSourceConfig {
+			refName = RenderSourceConfig
+		}
+		return document.WriteBundleOCILayout(fSys, bundle, fo.OutputDir, refName)
+	default:
+		return document.WriteBundleTarball(out, bundle)
+	}
+}
+
 func renderConfigBundle(out io.Writer, h ifc.Helper, sel document.Selector) error {
 	bundle, err := h.PhaseConfigBundle().SelectBundle(sel)
 	if err != nil {
@@ -126,5 +189,23 @@ func (fo *RenderCommand) Validate() (err error) {
 			ValidSources: []string{RenderSourceConfig, RenderSourceExecutor, RenderSourcePhase},
 		}
 	}
+	if err != nil {
+		return err
+	}
+
+	switch fo.Format {
+	case "", RenderFormatYAML, RenderFormatTar:
+		// do nothing, documents are written to the output stream
+	case RenderFormatDirectory, RenderFormatKustomization, RenderFormatOCI:
+		if fo.OutputDir == "" {
+			err = errors.ErrRenderOutputDirNotSpecified{Format: fo.Format}
+		}
+	default:
+		err = errors.ErrUnknownRenderFormat{
+			Format: fo.Format,
+			ValidFormats: []string{RenderFormatYAML, RenderFormatDirectory, RenderFormatKustomization,
+				RenderFormatTar, RenderFormatOCI},
+		}
+	}
 	return err
 }
@@ -118,6 +118,24 @@ func TestRender(t *testing.T) {
 			expErr: errors.ErrUnknownRenderSource{Source: "unknown",
 				ValidSources: []string{phase.RenderSourceConfig, phase.RenderSourceExecutor, phase.RenderSourcePhase}},
 		},
+		{
+			name: "format doesn't exist",
+			settings: &phase.RenderCommand{
+				Source: phase.RenderSourceConfig,
+				Format: "unknown",
+			},
+			expErr: errors.ErrUnknownRenderFormat{Format: "unknown",
+				ValidFormats: []string{phase.RenderFormatYAML, phase.RenderFormatDirectory,
+					phase.RenderFormatKustomization, phase.RenderFormatTar, phase.RenderFormatOCI}},
+		},
+		{
+			name: "output directory not specified",
+			settings: &phase.RenderCommand{
+				Source: phase.RenderSourceConfig,
+				Format: phase.RenderFormatKustomization,
+			},
+			expErr: errors.ErrRenderOutputDirNotSpecified{Format: phase.RenderFormatKustomization},
+		},
 		{
 			name: "phase name not specified",
 			settings: &phase.RenderCommand{
@@ -168,3 +186,34 @@ func TestRenderConfigBundle(t *testing.T) {
 	assert.Contains(t, buf.String(), "kind: Phase")
 	assert.Contains(t, buf.String(), "kind: ClusterMap")
 }
+
+func TestRenderKustomizationFormat(t *testing.T) {
+	rs := testutil.DummyConfig()
+	dummyManifest := rs.Manifests["dummy_manifest"]
+	dummyManifest.TargetPath = "testdata"
+	dummyManifest.PhaseRepositoryName = config.DefaultTestPhaseRepo
+	dummyManifest.Repositories = map[string]*config.Repository{
+		config.DefaultTestPhaseRepo: {},
+	}
+	dummyManifest.MetadataPath = "metadata.yaml"
+	outputDir, cleanup := testutil.TempDir(t, "airshipctl-render-test")
+	defer cleanup(t)
+
+	settings := &phase.RenderCommand{
+		Kind:      "BareMetalHost",
+		Source:    phase.RenderSourcePhase,
+		Format:    phase.RenderFormatKustomization,
+		OutputDir: outputDir,
+		PhaseID:   ifc.ID{Name: "phase"},
+	}
+	buf := &bytes.Buffer{}
+	require.NoError(t, settings.RunE(func() (*config.Config, error) {
+		return rs, nil
+	}, buf))
+	assert.Empty(t, buf.String())
+
+	kustomization, err := ioutil.ReadFile(path.Join(outputDir, "kustomization.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(kustomization), "- baremetalhost-node02.yaml")
+	assert.FileExists(t, path.Join(outputDir, "baremetalhost-node02.yaml"))
+}
@@ -149,6 +149,18 @@ func (mb *MockBundle) Append(doc document.Document) error {
 	return args.Error(0)
 }
 
+// Replace mock
+func (mb *MockBundle) Replace(doc document.Document) error {
+	args := mb.Called(doc)
+	return args.Error(0)
+}
+
+// Delete mock
+func (mb *MockBundle) Delete(doc document.Document) error {
+	args := mb.Called(doc)
+	return args.Error(0)
+}
+
 var (
 	// EmptyBundleFactory returns empty MockBundle
 	EmptyBundleFactory document.BundleFactoryFunc = func() (document.Bundle, error) {
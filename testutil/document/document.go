@@ -20,28 +20,33 @@ import (
 
 // MockDocument implements Document for unit test purposes
 type MockDocument struct {
-	MockAnnotate       func()
-	MockAsYAML         func() ([]byte, error)
-	MockGetAnnotations func() map[string]string
-	MockGetBool        func() (bool, error)
-	MockGetFloat64     func() (float64, error)
-	MockGetGroup       func() string
-	MockGetInt64       func() (int64, error)
-	MockGetKind        func() string
-	MockGetLabels      func() map[string]string
-	MockGetMap         func() (map[string]interface{}, error)
-	MockGetName        func() string
-	MockGetNamespace   func() string
-	MockGetSlice       func() ([]interface{}, error)
-	MockGetString      func() (string, error)
-	MockGetStringMap   func() (map[string]string, error)
-	MockGetStringSlice func() ([]string, error)
-	MockGetVersion     func() string
-	MockLabel          func()
-	MockMarshalJSON    func() ([]byte, error)
-	MockToObject       func() error
-	MockToAPIObject    func() error
-	MockGetFieldValue  func() (interface{}, error)
+	MockAnnotate            func()
+	MockAsYAML              func() ([]byte, error)
+	MockDeleteField         func() error
+	MockGetAnnotations      func() map[string]string
+	MockGetBool             func() (bool, error)
+	MockGetFloat64          func() (float64, error)
+	MockGetGroup            func() string
+	MockGetInt64            func() (int64, error)
+	MockGetKind             func() string
+	MockGetLabels           func() map[string]string
+	MockGetMap              func() (map[string]interface{}, error)
+	MockGetName             func() string
+	MockGetNamespace        func() string
+	MockGetSlice            func() ([]interface{}, error)
+	MockGetString           func() (string, error)
+	MockGetStringMap        func() (map[string]string, error)
+	MockGetStringSlice      func() ([]string, error)
+	MockGetVersion          func() string
+	MockJSONPatch           func() error
+	MockLabel               func()
+	MockMarshalJSON         func() ([]byte, error)
+	MockMergePatch          func() error
+	MockSetField            func() error
+	MockStrategicMergePatch func() error
+	MockToObject            func() error
+	MockToAPIObject         func() error
+	MockGetFieldValue       func() (interface{}, error)
 }
 
 // Annotate Document interface implementation for unit test purposes
@@ -54,6 +59,11 @@ func (md *MockDocument) AsYAML() ([]byte, error) {
 	return md.MockAsYAML()
 }
 
+// DeleteField Document interface implementation for unit test purposes
+func (md *MockDocument) DeleteField(_ string) error {
+	return md.MockDeleteField()
+}
+
 // GetAnnotations Document interface implementation for unit test purposes
 func (md *MockDocument) GetAnnotations() map[string]string {
 	return md.MockGetAnnotations()
@@ -134,6 +144,11 @@ func (md *MockDocument) GetVersion() string {
 	return md.MockGetVersion()
 }
 
+// JSONPatch Document interface implementation for unit test purposes
+func (md *MockDocument) JSONPatch(_ []byte) error {
+	return md.MockJSONPatch()
+}
+
 // Label Document interface implementation for unit test purposes
 func (md *MockDocument) Label(_ map[string]string) {
 	md.MockLabel()
@@ -144,6 +159,21 @@ func (md *MockDocument) MarshalJSON() ([]byte, error) {
 	return md.MockMarshalJSON()
 }
 
+// MergePatch Document interface implementation for unit test purposes
+func (md *MockDocument) MergePatch(_ []byte) error {
+	return md.MockMergePatch()
+}
+
+// SetField Document interface implementation for unit test purposes
+func (md *MockDocument) SetField(_ string, _ interface{}) error {
+	return md.MockSetField()
+}
+
+// StrategicMergePatch Document interface implementation for unit test purposes
+func (md *MockDocument) StrategicMergePatch(_ []byte) error {
+	return md.MockStrategicMergePatch()
+}
+
 // ToObject Document interface implementation for unit test purposes
 func (md *MockDocument) ToObject(_ interface{}) error {
 	return md.MockToObject()
@@ -19,6 +19,7 @@ import (
 
 	"opendev.org/airship/airshipctl/pkg/api/v1alpha1"
 	"opendev.org/airship/airshipctl/pkg/cluster/clustermap"
+	"opendev.org/airship/airshipctl/pkg/config"
 	"opendev.org/airship/airshipctl/pkg/document"
 	inventoryifc "opendev.org/airship/airshipctl/pkg/inventory/ifc"
 	"opendev.org/airship/airshipctl/pkg/phase/ifc"
@@ -156,3 +157,13 @@ func (mh *MockHelper) PhaseConfigBundle() document.Bundle {
 	}
 	return val
 }
+
+// KubeconfigOverrides mock
+func (mh *MockHelper) KubeconfigOverrides() map[string]*config.KubeconfigOverride {
+	args := mh.Called()
+	val, ok := args.Get(0).(map[string]*config.KubeconfigOverride)
+	if !ok {
+		return nil
+	}
+	return val
+}